@@ -221,6 +221,9 @@ func appInfo(cmd *cobra.Command, args []string) {
 		fmt.Println(bold("addresses:"))
 		for _, addr := range info.Addresses {
 			fmt.Printf("  %s\n", addr.Hostname)
+			for _, p := range addr.Ports {
+				fmt.Printf("    %s: %d/%s\n", p.Name, p.Port, p.Protocol)
+			}
 		}
 	}
 	if len(info.EnvVars) > 0 {
@@ -416,6 +419,41 @@ WARNING:
 	Run: appLogs,
 }
 
+var appEventsCmd = &cobra.Command{
+	Use:   "events <name>",
+	Short: "Show app events",
+	Long: `Show the cluster events of the application.
+
+Events report things like pods being scheduled, images being pulled,
+containers crashing or failing health checks.`,
+	Example: `  $ teresa app events foo
+
+  You can also keep watching for new events:
+
+  $ teresa app events foo --follow`,
+	Run: appEvents,
+}
+
+var appTopCmd = &cobra.Command{
+	Use:   "top <name>",
+	Short: "Show app resource usage",
+	Long: `Show the current cpu and memory usage of each pod of the app.
+
+The percentages are relative to the default limits of the app.`,
+	Example: "  $ teresa app top foo",
+	Run:     appTop,
+}
+
+var appClearBuildCacheCmd = &cobra.Command{
+	Use:   "clear-build-cache <name>",
+	Short: "Clear the app build cache",
+	Long: `Remove the build cache of the app.
+
+The next deploy will install all the app dependencies from scratch.`,
+	Example: "  $ teresa app clear-build-cache foo",
+	Run:     appClearBuildCache,
+}
+
 func init() {
 	// add AppCmd
 	RootCmd.AddCommand(appCmd)
@@ -426,6 +464,9 @@ func init() {
 	appCmd.AddCommand(appEnvSetCmd)
 	appCmd.AddCommand(appEnvUnSetCmd)
 	appCmd.AddCommand(appLogsCmd)
+	appCmd.AddCommand(appEventsCmd)
+	appCmd.AddCommand(appTopCmd)
+	appCmd.AddCommand(appClearBuildCacheCmd)
 
 	appCreateCmd.Flags().String("team", "", "team owner of the app")
 	appCreateCmd.Flags().Int32("scale-min", 1, "auto scale min size")
@@ -445,6 +486,8 @@ func init() {
 	// App logs
 	appLogsCmd.Flags().Int64("lines", 10, "number of lines")
 	appLogsCmd.Flags().Bool("follow", false, "follow logs")
+	// App events
+	appEventsCmd.Flags().Bool("follow", false, "watch for new events")
 }
 
 func appLogs(cmd *cobra.Command, args []string) {
@@ -480,3 +523,106 @@ func appLogs(cmd *cobra.Command, args []string) {
 		fmt.Println(msg.Text)
 	}
 }
+
+func appEvents(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	appName := args[0]
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := appb.NewAppClient(conn)
+	req := &appb.EventsRequest{Name: appName, Follow: follow}
+	stream, err := cli.Events(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	warn := color.New(color.FgYellow).SprintFunc()
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			client.PrintErrorAndExit(client.GetErrorMsg(err))
+		}
+		evType := ev.Type
+		if evType == "Warning" {
+			evType = warn(evType)
+		}
+		count := ""
+		if ev.Count > 1 {
+			count = fmt.Sprintf(" (x%d)", ev.Count)
+		}
+		fmt.Printf("%s %s %s %s: %s%s\n", ev.LastSeen, evType, ev.Object, ev.Reason, ev.Message, count)
+	}
+}
+
+func appTop(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := appb.NewAppClient(conn)
+	m, err := cli.Metrics(context.Background(), &appb.MetricsRequest{Name: name})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if m.Limits != nil && len(m.Limits.Default) > 0 {
+		bold := color.New(color.Bold).SprintFunc()
+		fmt.Println(bold("limits:"))
+		for _, item := range m.Limits.Default {
+			fmt.Printf("  %s %s\n", bold(item.Resource), item.Quantity)
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"POD", "CPU", "CPU%", "MEMORY", "MEMORY%"})
+	for _, pod := range m.Pods {
+		table.Append([]string{
+			pod.Name,
+			pod.Cpu,
+			fmt.Sprintf("%d%%", pod.CpuLimitPercentage),
+			pod.Memory,
+			fmt.Sprintf("%d%%", pod.MemoryLimitPercentage),
+		})
+	}
+	table.Render()
+}
+
+func appClearBuildCache(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := appb.NewAppClient(conn)
+	if _, err := cli.ClearBuildCache(context.Background(), &appb.ClearBuildCacheRequest{Name: name}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Build cache cleared with success")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa-api/cmd/client/connection"
+	"github.com/luizalabs/teresa-api/pkg/client"
+	auditpb "github.com/luizalabs/teresa-api/pkg/protobuf/audit"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log",
+	Long: `Show who changed what and when: the calls of every operation that changes
+an app, a team, a user or a webhook, with their parameters (env var values and
+passwords are masked) and results.
+
+Admins can see all the entries and team owners the entries of their teams.
+
+The --since and --until flags take a duration ago, like 24h, or a RFC3339 date.`,
+	Example: `  $ teresa audit --app foo --since 24h
+
+  $ teresa audit --team foo --method App.SetEnv --params`,
+	Run: auditList,
+}
+
+func init() {
+	RootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().String("user", "", "only the calls of the user")
+	auditCmd.Flags().String("team", "", "only the calls on the team and its apps")
+	auditCmd.Flags().String("app", "", "only the calls on the app")
+	auditCmd.Flags().String("method", "", "only the calls of the method, like App.SetEnv")
+	auditCmd.Flags().String("since", "", "only the calls after, duration ago or date")
+	auditCmd.Flags().String("until", "", "only the calls before, duration ago or date")
+	auditCmd.Flags().Int64("limit", 50, "number of entries to show")
+	auditCmd.Flags().Bool("params", false, "show the parameters of the calls")
+}
+
+// parseAuditTime turns a duration ago, like 24h, into a RFC3339 date; other
+// values are sent as given.
+func parseAuditTime(s string) string {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return s
+	}
+	return time.Now().Add(-d).UTC().Format(time.RFC3339)
+}
+
+func auditList(cmd *cobra.Command, args []string) {
+	user, _ := cmd.Flags().GetString("user")
+	team, _ := cmd.Flags().GetString("team")
+	app, _ := cmd.Flags().GetString("app")
+	method, _ := cmd.Flags().GetString("method")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	limit, _ := cmd.Flags().GetInt64("limit")
+	showParams, _ := cmd.Flags().GetBool("params")
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := auditpb.NewAuditClient(conn)
+	req := &auditpb.ListRequest{
+		User:   user,
+		Team:   team,
+		App:    app,
+		Method: method,
+		Since:  parseAuditTime(since),
+		Until:  parseAuditTime(until),
+		Limit:  limit,
+	}
+	resp, err := cli.List(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Entries) == 0 {
+		fmt.Println("No audit entries found")
+		return
+	}
+
+	header := []string{"DATE", "USER", "METHOD", "TEAM", "APP", "TARGET", "RESULT"}
+	if showParams {
+		header = append(header, "PARAMS")
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, e := range resp.Entries {
+		result := color.GreenString("ok")
+		if !e.Success {
+			result = color.RedString(e.Error)
+		}
+		row := []string{e.CreatedAt, e.User, e.Method, e.Team, e.App, e.Target, result}
+		if showParams {
+			row = append(row, e.Params)
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
@@ -17,3 +17,8 @@ const (
 	deploymentSuccessMark = "----------deployment-success----------"
 	deploymentErrorMark   = "----------deployment-error----------"
 )
+
+const (
+	teresaIgnoreFileName = ".teresaignore"
+	gitIgnoreFileName    = ".gitignore"
+)
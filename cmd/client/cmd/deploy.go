@@ -11,6 +11,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/luizalabs/teresa-api/cmd/client/connection"
+	"github.com/luizalabs/teresa-api/cmd/client/ignore"
 	"github.com/luizalabs/teresa-api/cmd/client/tar"
 	"github.com/luizalabs/teresa-api/pkg/client"
 	dpb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
@@ -33,10 +34,33 @@ var deployCmd = &cobra.Command{
 	eg.:
 	
 	  $ teresa deploy . --app webapi --description "release 1.2 with new checkout"
+
+	You can also deploy an image built elsewhere, in this case only
+	the teresa.yaml and Procfile of the app folder are sent:
+
+	  $ teresa deploy . --app webapi --image luizalabs/webapi:1.2 --description "release 1.2"
+
+	The files matching the .teresaignore files of the app folder (and, with
+	--use-gitignore, the .gitignore files) aren't sent, the patterns follow
+	the gitignore format. To list the files that would be sent:
+
+	  $ teresa deploy . --dry-run --use-gitignore
 	`,
 	Run: deployApp,
 }
 
+var deployPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Promote a deploy to another app",
+	Long: `Deploy the same artifact of a deploy of an app to another app.
+
+The slug (or image) and the teresa.yaml of the source deploy are reused,
+while the env vars and the release command are the ones of the target app.
+The deploy id is shown at the end of every deploy.`,
+	Example: `  $ teresa deploy promote --from webapi-staging --to webapi --deploy-id 1a2b3c4d`,
+	Run:     deployPromote,
+}
+
 func getCurrentClusterName() (string, error) {
 	cfg, err := client.ReadConfigFile(cfgFile)
 	if err != nil {
@@ -48,30 +72,32 @@ func getCurrentClusterName() (string, error) {
 	return cfg.CurrentCluster, nil
 }
 
-func createTempArchiveToUpload(appName, source string) (path string, err error) {
+func createTempArchiveToUpload(appName, source string, useGitignore bool) (path string, err error) {
 	id := uuid.NewV4()
 	source, err = filepath.Abs(source)
 	if err != nil {
 		return "", err
 	}
 	p := filepath.Join(os.TempDir(), fmt.Sprintf("%s_%s.tar.gz", appName, id))
-	if err = createArchive(source, p); err != nil {
+	if err = createArchive(source, p, useGitignore); err != nil {
 		return "", err
 	}
 	return p, nil
 }
 
-func createArchive(source, target string) error {
+func checkAppFolder(source string) error {
 	dir, err := os.Stat(source)
 	if err != nil {
 		return fmt.Errorf("Dir not found to create an archive. %s", err)
 	} else if !dir.IsDir() {
 		return errors.New("Path to create the app archive isn't a directory")
 	}
+	return nil
+}
 
-	ignorePatterns, err := getIgnorePatterns(source)
-	if err != nil {
-		return errors.New("Invalid file '.teresaignore'")
+func createArchive(source, target string, useGitignore bool) error {
+	if err := checkAppFolder(source); err != nil {
+		return err
 	}
 
 	t, err := tar.New(target)
@@ -80,67 +106,74 @@ func createArchive(source, target string) error {
 	}
 	defer t.Close()
 
-	if ignorePatterns != nil {
-		if err = addFiles(source, t, ignorePatterns); err != nil {
+	return walkAppFiles(source, useGitignore, func(path, name string) error {
+		return t.AddFile(path, name)
+	})
+}
+
+// listArchiveFiles prints the files that would be packed, and their total
+// size, without creating the archive.
+func listArchiveFiles(source string, useGitignore bool) error {
+	if err := checkAppFolder(source); err != nil {
+		return err
+	}
+
+	var count, size int64
+	err := walkAppFiles(source, useGitignore, func(path, name string) error {
+		info, err := os.Stat(path)
+		if err != nil {
 			return err
 		}
-	} else {
-		t.AddAll(source)
+		fmt.Println(name)
+		count++
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	fmt.Printf("%d files, %d bytes\n", count, size)
 	return nil
 }
 
-func getIgnorePatterns(source string) ([]string, error) {
-	fPath := filepath.Join(source, ".teresaignore")
-	if _, err := os.Stat(fPath); err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+// createConfigArchive creates an archive with only the teresa.yaml and
+// Procfile of source, returning false if none of them exist.
+func createConfigArchive(source, target string) (bool, error) {
+	var files []string
+	for _, name := range []string{"teresa.yaml", "Procfile"} {
+		if _, err := os.Stat(filepath.Join(source, name)); err == nil {
+			files = append(files, name)
 		}
-		return nil, err
+	}
+	if len(files) == 0 {
+		return false, nil
 	}
 
-	file, err := os.Open(fPath)
+	t, err := tar.New(target)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	defer file.Close()
-
-	patterns := make([]string, 0)
-	scanner := bufio.NewScanner(file)
+	defer t.Close()
 
-	for scanner.Scan() {
-		if text := scanner.Text(); text != "" {
-			patterns = append(patterns, text)
+	for _, name := range files {
+		if err := t.AddFile(filepath.Join(source, name), name); err != nil {
+			return false, err
 		}
 	}
-
-	if len(patterns) == 0 {
-		return nil, nil
-	}
-
-	return patterns, nil
+	return true, nil
 }
 
-func addFiles(source string, tar tar.Writer, ignorePatterns []string) error {
-	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		for _, ip := range ignorePatterns {
-			if matched, _ := filepath.Match(ip, info.Name()); matched {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-		}
-		if info.IsDir() {
-			return nil
-		}
-		basePath := fmt.Sprintf("%s%c", source, filepath.Separator)
-		filename := strings.Replace(path, basePath, "", 1)
-		return tar.AddFile(path, filename)
-	})
+// walkAppFiles calls fn with each file of the app folder source that
+// isn't ignored by the .teresaignore files (and by the .gitignore files
+// when useGitignore is set), using the gitignore pattern format.
+func walkAppFiles(source string, useGitignore bool, fn ignore.WalkFunc) error {
+	m := new(ignore.Matcher)
+	ignoreFiles := []string{teresaIgnoreFileName}
+	if useGitignore {
+		m.Add("", []string{".git/"})
+		ignoreFiles = []string{gitIgnoreFileName, teresaIgnoreFileName}
+	}
+	return ignore.Walk(source, m, ignoreFiles, fn)
 }
 
 func init() {
@@ -149,6 +182,17 @@ func init() {
 	deployCmd.Flags().String("app", "", "app name (required)")
 	deployCmd.Flags().String("description", "", "deploy description (required)")
 	deployCmd.Flags().Bool("no-input", false, "deploy app without warning")
+	deployCmd.Flags().String("image", "", "deploy a pre-built image instead of building the app")
+	deployCmd.Flags().Bool("no-cache", false, "build the app without the build cache")
+	deployCmd.Flags().Bool("use-gitignore", false, "also skip the files ignored by the .gitignore files")
+	deployCmd.Flags().Bool("dry-run", false, "list the files that would be sent, without deploying")
+
+	deployCmd.AddCommand(deployPromoteCmd)
+	deployPromoteCmd.Flags().String("from", "", "source app name (required)")
+	deployPromoteCmd.Flags().String("to", "", "target app name (required)")
+	deployPromoteCmd.Flags().String("deploy-id", "", "deploy id of the source app (required)")
+	deployPromoteCmd.Flags().String("description", "", "deploy description")
+	deployPromoteCmd.Flags().Bool("no-input", false, "promote without warning")
 
 }
 
@@ -161,6 +205,20 @@ func deployApp(cmd *cobra.Command, args []string) {
 	appName, _ := cmd.Flags().GetString("app")
 	deployDescription, _ := cmd.Flags().GetString("description")
 	noInput, _ := cmd.Flags().GetBool("no-input")
+	image, _ := cmd.Flags().GetString("image")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	useGitignore, _ := cmd.Flags().GetBool("use-gitignore")
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		if err := listArchiveFiles(appFolder, useGitignore); err != nil {
+			client.PrintErrorAndExit("Error listing the app files: %v", err)
+		}
+		return
+	}
+
+	if !validateAppConfig(appFolder) {
+		client.PrintErrorAndExit("Invalid config files, nothing was deployed")
+	}
 
 	currentClusterName, err := getCurrentClusterName()
 	if err != nil {
@@ -194,13 +252,19 @@ func deployApp(cmd *cobra.Command, args []string) {
 	info := &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{&dpb.DeployRequest_Info{
 		App:         appName,
 		Description: deployDescription,
+		Image:       image,
+		NoCache:     noCache,
 	}}}
 	if err := stream.Send(info); err != nil {
 		client.PrintErrorAndExit("Error sending deploy information: %v", err)
 	}
 
 	g, ctx := errgroup.WithContext(ctx)
-	g.Go(func() error { return sendAppTarball(appName, appFolder, stream) })
+	if image != "" {
+		g.Go(func() error { return sendAppConfig(appName, appFolder, stream) })
+	} else {
+		g.Go(func() error { return sendAppTarball(appName, appFolder, useGitignore, stream) })
+	}
 	g.Go(func() error { return streamServerMsgs(stream) })
 
 	if err := g.Wait(); err != nil {
@@ -208,14 +272,34 @@ func deployApp(cmd *cobra.Command, args []string) {
 	}
 }
 
-func sendAppTarball(appName, appFolder string, stream dpb.Deploy_MakeClient) error {
+func sendAppTarball(appName, appFolder string, useGitignore bool, stream dpb.Deploy_MakeClient) error {
 	fmt.Println("Generating tarball of:", appFolder)
-	tarPath, err := createTempArchiveToUpload(appName, appFolder)
+	tarPath, err := createTempArchiveToUpload(appName, appFolder, useGitignore)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error generating tarball:")
+		return err
+	}
+	return sendFile(tarPath, stream)
+}
+
+func sendAppConfig(appName, appFolder string, stream dpb.Deploy_MakeClient) error {
+	source, err := filepath.Abs(appFolder)
+	if err != nil {
+		return err
+	}
+	tarPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s_%s.tar.gz", appName, uuid.NewV4()))
+	found, err := createConfigArchive(source, tarPath)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error generating tarball:")
 		return err
 	}
+	if !found {
+		return stream.CloseSend()
+	}
+	return sendFile(tarPath, stream)
+}
 
+func sendFile(tarPath string, stream dpb.Deploy_MakeClient) error {
 	f, err := os.Open(tarPath)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error reading temp file:")
@@ -260,3 +344,59 @@ func streamServerMsgs(stream dpb.Deploy_MakeClient) error {
 	}
 	return nil
 }
+
+func deployPromote(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	deployId, _ := cmd.Flags().GetString("deploy-id")
+	if from == "" || to == "" || deployId == "" {
+		cmd.Usage()
+		return
+	}
+	description, _ := cmd.Flags().GetString("description")
+	noInput, _ := cmd.Flags().GetBool("no-input")
+
+	fmt.Printf(
+		"Promoting deploy %s of app %s to app %s...\n",
+		color.YellowString(deployId),
+		color.CyanString(`"%s"`, from),
+		color.CyanString(`"%s"`, to),
+	)
+
+	if !noInput {
+		fmt.Print("Are you sure? (yes/NO)? ")
+		s, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !strings.HasPrefix(strings.ToLower(s), "yes") {
+			return
+		}
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := dpb.NewDeployClient(conn)
+	req := &dpb.PromoteRequest{
+		FromApp:     from,
+		ToApp:       to,
+		DeployId:    deployId,
+		Description: description,
+	}
+	stream, err := cli.Promote(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			client.PrintErrorAndExit(client.GetErrorMsg(err))
+		}
+		fmt.Print(msg.Text)
+	}
+}
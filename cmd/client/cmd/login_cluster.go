@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
 	context "golang.org/x/net/context"
 
 	"github.com/fatih/color"
@@ -8,24 +12,41 @@ import (
 	"github.com/luizalabs/teresa-api/pkg/client"
 	"github.com/spf13/cobra"
 
+	oidcpb "github.com/luizalabs/teresa-api/pkg/protobuf/oidc"
 	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
 )
 
-var userName string
+var (
+	userName    string
+	loginOIDC   bool
+	loginDevice bool
+)
 
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login in the currently selected cluster",
 	Long: `Login in the selected cluster.
 
+With --oidc the login is done in the identity provider of the cluster, in
+the browser, or with --device by entering a code in any device.
+
+Users with two-factor authentication are asked for a code of their
+authenticator app or one of their recovery codes.
+
 eg.:
 
 	$ teresa login --user user@mydomain.com
+
+	$ teresa login --oidc
 	`,
 	Run: login,
 }
 
 func login(cmd *cobra.Command, args []string) {
+	if loginOIDC || loginDevice {
+		oidcLogin()
+		return
+	}
 	if userName == "" {
 		cmd.Usage()
 		return
@@ -43,18 +64,119 @@ func login(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	cli := userpb.NewUserClient(conn)
-	res, err := cli.Login(context.Background(), &userpb.LoginRequest{Email: userName, Password: p})
+	req := &userpb.LoginRequest{Email: userName, Password: p}
+	res, err := cli.Login(context.Background(), req)
+	if client.IsCodeRequired(err) {
+		req.TotpCode = readCode("Two-factor authentication code (or recovery code): ")
+		res, err = cli.Login(context.Background(), req)
+	}
 	if err != nil {
 		client.PrintErrorAndExit(client.GetErrorMsg(err))
 	}
 	color.Green("Login OK")
 
-	if err = client.SaveToken(cfgFile, res.Token); err != nil {
+	if err = client.SaveToken(cfgFile, res.Token, res.RefreshToken); err != nil {
 		client.PrintErrorAndExit("Error trying to save token in configuration file: %v", err)
 	}
 }
 
+func oidcLogin() {
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := oidcpb.NewOIDCClient(conn)
+	cfg, err := cli.Config(context.Background(), &oidcpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	o := client.NewOIDCLogin(cfg.Issuer, cfg.ClientId, cfg.Scopes)
+	var idToken, nonce string
+	if loginDevice {
+		idToken, err = o.Device(func(userCode, verificationURI string) {
+			fmt.Printf("Open %s and enter the code %s\n", verificationURI, color.New(color.Bold).SprintFunc()(userCode))
+		})
+	} else {
+		idToken, nonce, err = o.AuthCode(func(authURL string) {
+			fmt.Printf("Login in your browser, if it doesn't open visit:\n\n%s\n\n", authURL)
+			openBrowser(authURL)
+		})
+	}
+	if err != nil {
+		client.PrintErrorAndExit("Error logging in with the identity provider: %v", err)
+	}
+
+	req := &oidcpb.LoginRequest{IdToken: idToken, Nonce: nonce}
+	res, err := cli.Login(context.Background(), req)
+	if client.IsCodeRequired(err) {
+		req.TotpCode = readCode("Two-factor authentication code (or recovery code): ")
+		res, err = cli.Login(context.Background(), req)
+	}
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	color.Green("Login OK")
+
+	if err = client.SaveToken(cfgFile, res.Token, res.RefreshToken); err != nil {
+		client.PrintErrorAndExit("Error trying to save token in configuration file: %v", err)
+	}
+}
+
+// openBrowser opens the URL in the default browser, errors are ignored as
+// the URL is also printed.
+func openBrowser(u string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	cmd.Start()
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Logout of the currently selected cluster",
+	Long: `Logout of the selected cluster, revoking the tokens of the current login.
+
+To end the sessions of all the logins of a user use revoke-sessions.`,
+	Run: logout,
+}
+
+func logout(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	// read after connecting, which may have renewed the tokens
+	cfg, err := client.GetConfig(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error reading the configuration file: %v", err)
+	}
+
+	cli := userpb.NewUserClient(conn)
+	req := &userpb.LogoutRequest{RefreshToken: cfg.RefreshToken}
+	if _, err := cli.Logout(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	if err = client.SaveToken(cfgFile, "", ""); err != nil {
+		client.PrintErrorAndExit("Error trying to remove the token from configuration file: %v", err)
+	}
+	color.Green("Logout OK")
+}
+
 func init() {
 	loginCmd.Flags().StringVar(&userName, "user", "", "username to login with")
+	loginCmd.Flags().BoolVar(&loginOIDC, "oidc", false, "login with the identity provider (OpenID Connect)")
+	loginCmd.Flags().BoolVar(&loginDevice, "device", false, "login with the identity provider entering a code in any device")
 	RootCmd.AddCommand(loginCmd)
+	RootCmd.AddCommand(logoutCmd)
 }
@@ -41,16 +41,66 @@ You can add a new user as a member of a team with:
 
   $ teresa team add-user --user john.doe@foodomain.com --team foo
 
+The member role can be owner, member (the default) or viewer:
+
+  $ teresa team add-user --user john.doe@foodomain.com --team foo --role viewer
+
+Owners can manage the members of the team, members can create apps, deploy and
+change their env vars and viewers can only see the info and the logs of the apps.
+
 You need to create a user before use this command.`,
 	Run: teamAddUser,
 }
 
+var teamSetRoleCmd = &cobra.Command{
+	Use:   "set-role",
+	Short: "Set the role of a member of a team",
+	Long: `Set the role of a member of a team, one of owner, member or viewer.
+
+  $ teresa team set-role --user john.doe@foodomain.com --team foo --role owner`,
+	Run: teamSetRole,
+}
+
+var teamRemoveUserCmd = &cobra.Command{
+	Use:   "remove-user",
+	Short: "Remove a member from a team",
+	Long: `Remove a member from a team.
+
+You can remove a user from a team with:
+
+  $ teresa team remove-user --user john.doe@foodomain.com --team foo`,
+	Run: teamRemoveUser,
+}
+
+var teamDeleteCmd = &cobra.Command{
+	Use:   "delete <team-name>",
+	Short: "Delete a team",
+	Long: `Delete a team.
+
+A team that still owns applications can only be deleted with the
+--force flag, which deletes its applications too.`,
+	Example: "$ teresa team delete foo",
+	Run:     teamDelete,
+}
+
+var teamUpdateCmd = &cobra.Command{
+	Use:     "update <team-name>",
+	Short:   "Update the email and URL of a team",
+	Long:    "Update the email and URL of a team, only the ones given change.",
+	Example: "$ teresa team update foo --email foo@foodomain.com --url http://site.foodomain.com",
+	Run:     teamUpdate,
+}
+
 func init() {
 	RootCmd.AddCommand(teamCmd)
 	// Commands
 	teamCmd.AddCommand(teamListCmd)
 	teamCmd.AddCommand(teamCreateCmd)
 	teamCmd.AddCommand(teamAddUserCmd)
+	teamCmd.AddCommand(teamSetRoleCmd)
+	teamCmd.AddCommand(teamRemoveUserCmd)
+	teamCmd.AddCommand(teamDeleteCmd)
+	teamCmd.AddCommand(teamUpdateCmd)
 
 	teamListCmd.Flags().Bool("show-users", false, "show members of team")
 
@@ -59,7 +109,19 @@ func init() {
 
 	teamAddUserCmd.Flags().String("user", "", "user email")
 	teamAddUserCmd.Flags().String("team", "", "team name")
+	teamAddUserCmd.Flags().String("role", "", "member role: owner, member or viewer (default member)")
+
+	teamSetRoleCmd.Flags().String("user", "", "user email")
+	teamSetRoleCmd.Flags().String("team", "", "team name")
+	teamSetRoleCmd.Flags().String("role", "", "member role: owner, member or viewer")
+
+	teamRemoveUserCmd.Flags().String("user", "", "user email")
+	teamRemoveUserCmd.Flags().String("team", "", "team name")
+
+	teamDeleteCmd.Flags().Bool("force", false, "delete the team and the apps it still owns")
 
+	teamUpdateCmd.Flags().String("email", "", "team email, if any")
+	teamUpdateCmd.Flags().String("url", "", "team site's URL, if any")
 }
 
 func createTeam(cmd *cobra.Command, args []string) {
@@ -68,6 +130,9 @@ func createTeam(cmd *cobra.Command, args []string) {
 		return
 	}
 	name := args[0]
+	if !cmd.Flags().Changed("email") && !cmd.Flags().Changed("url") {
+		client.PrintErrorAndExit("Nothing to update, use --email or --url")
+	}
 	email, _ := cmd.Flags().GetString("email")
 	url, _ := cmd.Flags().GetString("url")
 
@@ -94,6 +159,7 @@ func teamAddUser(cmd *cobra.Command, args []string) {
 	if err != nil {
 		client.PrintErrorAndExit("Invalid user parameter: %v", err)
 	}
+	role, _ := cmd.Flags().GetString("role")
 	if team == "" || user == "" {
 		cmd.Usage()
 		return
@@ -106,13 +172,109 @@ func teamAddUser(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	cli := teampb.NewTeamClient(conn)
-	req := &teampb.AddUserRequest{Name: team, User: user}
+	req := &teampb.AddUserRequest{Name: team, User: user, Role: role}
 	if _, err := cli.AddUser(context.Background(), req); err != nil {
 		client.PrintErrorAndExit(client.GetErrorMsg(err))
 	}
 	fmt.Printf("User %s is now member of the team %s\n", color.CyanString(user), color.CyanString(team))
 }
 
+func teamSetRole(cmd *cobra.Command, args []string) {
+	team, _ := cmd.Flags().GetString("team")
+	user, _ := cmd.Flags().GetString("user")
+	role, _ := cmd.Flags().GetString("role")
+	if team == "" || user == "" || role == "" {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.SetRoleRequest{Name: team, User: user, Role: role}
+	if _, err := cli.SetRole(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("User %s is now %s of the team %s\n", color.CyanString(user), role, color.CyanString(team))
+}
+
+func teamRemoveUser(cmd *cobra.Command, args []string) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid team parameter: %v", err)
+	}
+	user, err := cmd.Flags().GetString("user")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid user parameter: %v", err)
+	}
+	if team == "" || user == "" {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.RemoveUserRequest{Name: team, User: user}
+	if _, err := cli.RemoveUser(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("User %s is no longer member of the team %s\n", color.CyanString(user), color.CyanString(team))
+}
+
+func teamDelete(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+	force, _ := cmd.Flags().GetBool("force")
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.DeleteRequest{Name: name, Force: force}
+	if _, err := cli.Delete(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Team %s deleted with success\n", color.CyanString(name))
+}
+
+func teamUpdate(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+	email, _ := cmd.Flags().GetString("email")
+	url, _ := cmd.Flags().GetString("url")
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.UpdateRequest{Name: name, Email: email, Url: url}
+	if _, err := cli.Update(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Team updated with success")
+}
+
 func teamList(cmd *cobra.Command, args []string) {
 	showUsers, _ := cmd.Flags().GetBool("show-users")
 
@@ -146,7 +308,7 @@ func teamList(cmd *cobra.Command, args []string) {
 			continue
 		}
 		for _, u := range t.Users {
-			fmt.Printf("- %s (%s)\n", u.Name, u.Email)
+			fmt.Printf("- %s (%s) %s\n", u.Name, u.Email, u.Role)
 		}
 	}
 }
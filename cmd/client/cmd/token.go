@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa-api/cmd/client/connection"
+	"github.com/luizalabs/teresa-api/pkg/client"
+	tokenpb "github.com/luizalabs/teresa-api/pkg/protobuf/token"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Everything about API tokens",
+	Long: `Create, list and revoke API tokens.
+
+API tokens don't expire and are limited to some apps (or the apps of some
+teams) and actions, to be used by CI jobs and other automations instead of a
+user login. Set the TERESA_TOKEN env var to use one.
+
+The actions are:
+  deploy: deploy and promote
+  read:   app info, logs, events and top`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API token",
+	Long: `Create an API token limited to some apps or teams and actions.
+
+The token is shown only once, store it in a safe place.`,
+	Example: `  $ teresa token create ci --app foo --actions deploy
+
+  $ teresa token create monitoring --team bar --actions read`,
+	Run: tokenCreate,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the API tokens",
+	Long:  "List your API tokens (admins see the tokens of all users).",
+	Run:   tokenList,
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:     "revoke <id>",
+	Short:   "Revoke an API token",
+	Example: "  $ teresa token revoke 1",
+	Run:     tokenRevoke,
+}
+
+func init() {
+	RootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	tokenCreateCmd.Flags().StringSlice("app", nil, "apps the token can access")
+	tokenCreateCmd.Flags().StringSlice("team", nil, "teams whose apps the token can access")
+	tokenCreateCmd.Flags().StringSlice("actions", nil, "actions allowed (deploy, read)")
+}
+
+func tokenCreate(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	apps, _ := cmd.Flags().GetStringSlice("app")
+	teams, _ := cmd.Flags().GetStringSlice("team")
+	actions, _ := cmd.Flags().GetStringSlice("actions")
+	if len(apps) == 0 && len(teams) == 0 {
+		client.PrintErrorAndExit("At least one app or team is required")
+	}
+	if len(actions) == 0 {
+		client.PrintErrorAndExit("At least one action is required")
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	req := &tokenpb.CreateRequest{
+		Name:    args[0],
+		Apps:    apps,
+		Teams:   teams,
+		Actions: actions,
+	}
+	resp, err := cli.Create(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Token created with success, it won't be shown again:")
+	color.New(color.FgGreen, color.Bold).Println(resp.Token)
+}
+
+func tokenList(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	resp, err := cli.List(context.Background(), &tokenpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Tokens) == 0 {
+		fmt.Println("No API tokens found")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "NAME", "USER", "PREFIX", "APPS", "TEAMS", "ACTIONS", "CREATED", "LAST USED"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, t := range resp.Tokens {
+		table.Append([]string{
+			fmt.Sprint(t.Id),
+			t.Name,
+			t.User,
+			t.Prefix,
+			strings.Join(t.Apps, ","),
+			strings.Join(t.Teams, ","),
+			strings.Join(t.Actions, ","),
+			t.CreatedAt,
+			t.LastUsedAt,
+		})
+	}
+	table.Render()
+}
+
+func tokenRevoke(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		client.PrintErrorAndExit("Invalid token id: %s", args[0])
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	if _, err := cli.Revoke(context.Background(), &tokenpb.RevokeRequest{Id: id}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Token revoked with success")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa-api/cmd/client/connection"
+	"github.com/luizalabs/teresa-api/pkg/client"
+	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
+)
+
+var totpCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Everything about two-factor authentication",
+	Long: `Enable and disable the two-factor authentication of your user.
+
+With two-factor authentication the login asks for a code of an authenticator
+app (TOTP), like Google Authenticator, besides the password.`,
+}
+
+var totpEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable two-factor authentication",
+	Long: `Enable the two-factor authentication of your user.
+
+Add the key shown to your authenticator app and enter a code to confirm it.
+The recovery codes log you in without the app, once each; they are shown
+only once, store them in a safe place.`,
+	Example: "  $ teresa 2fa enable",
+	Run:     totpEnable,
+}
+
+var totpDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable two-factor authentication",
+	Long: `Disable the two-factor authentication of your user, asking for a code.
+
+Admins can disable the two-factor authentication of any user, like when its
+device is lost.`,
+	Example: `  $ teresa 2fa disable
+
+  $ teresa 2fa disable --email user@mydomain.com`,
+	Run: totpDisable,
+}
+
+func init() {
+	RootCmd.AddCommand(totpCmd)
+	totpCmd.AddCommand(totpEnableCmd)
+	totpCmd.AddCommand(totpDisableCmd)
+
+	totpDisableCmd.Flags().String("email", "", "user email (admins only)")
+}
+
+func readCode(prompt string) string {
+	fmt.Print(prompt)
+	s, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(s)
+}
+
+func totpEnable(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	e, err := cli.EnrollTOTP(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Add this key to your authenticator app:\n\n%s\n\n", color.New(color.Bold).SprintFunc()(e.Secret))
+	fmt.Printf("or the URI (as a QR code, eg. with qrencode -t ansi):\n\n%s\n\n", e.ProvisioningUri)
+
+	code := readCode("Code: ")
+	res, err := cli.EnableTOTP(context.Background(), &userpb.EnableTOTPRequest{Code: code})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	color.Green("Two-factor authentication enabled")
+	fmt.Println("\nRecovery codes, each logs in once without the app:")
+	for _, c := range res.RecoveryCodes {
+		fmt.Printf("  %s\n", c)
+	}
+}
+
+func totpDisable(cmd *cobra.Command, args []string) {
+	email, _ := cmd.Flags().GetString("email")
+	req := &userpb.DisableTOTPRequest{Email: email}
+	if email == "" {
+		req.Code = readCode("Two-factor authentication code (or recovery code): ")
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	if _, err := cli.DisableTOTP(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Two-factor authentication disabled")
+}
@@ -61,6 +61,72 @@ func setPassword(cmd *cobra.Command, args []string) {
 	fmt.Println("Password updated")
 }
 
+var revokeSessionsCmd = &cobra.Command{
+	Use:   "revoke-sessions",
+	Short: "Revoke all the sessions of a user",
+	Long: `Revoke the tokens of all the logins of a user, who has to login again.
+
+Admins can revoke the sessions of any user.`,
+	Example: "  $ teresa revoke-sessions --email user@mydomain.com",
+	Run:     revokeSessions,
+}
+
+func revokeSessions(cmd *cobra.Command, args []string) {
+	email, _ := cmd.Flags().GetString("email")
+	if email == "" {
+		cmd.Usage()
+		return
+	}
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	_, err = cli.RevokeSessions(
+		context.Background(),
+		&userpb.RevokeSessionsRequest{Email: email},
+	)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Sessions revoked")
+}
+
+var unlockUserCmd = &cobra.Command{
+	Use:   "unlock-user",
+	Short: "Unlock a user locked out after failed logins",
+	Long: `Forget the failed logins of a user, ending its lockout.
+
+Only admins can unlock users.`,
+	Example: "  $ teresa unlock-user --email user@mydomain.com",
+	Run:     unlockUser,
+}
+
+func unlockUser(cmd *cobra.Command, args []string) {
+	email, _ := cmd.Flags().GetString("email")
+	if email == "" {
+		cmd.Usage()
+		return
+	}
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	_, err = cli.Unlock(
+		context.Background(),
+		&userpb.UnlockRequest{Email: email},
+	)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("User unlocked")
+}
+
 func deleteUser(cmd *cobra.Command, args []string) {
 	email, _ := cmd.Flags().GetString("email")
 	if email == "" {
@@ -138,4 +204,10 @@ func init() {
 	deleteUserCmd.Flags().String("email", "", "user email [required]")
 
 	RootCmd.AddCommand(setUserPasswordCmd)
+
+	RootCmd.AddCommand(revokeSessionsCmd)
+	revokeSessionsCmd.Flags().String("email", "", "user email [required]")
+
+	RootCmd.AddCommand(unlockUserCmd)
+	unlockUserCmd.Flags().String("email", "", "user email [required]")
 }
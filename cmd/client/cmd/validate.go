@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
+	"github.com/luizalabs/teresa-api/pkg/client"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [app folder]",
+	Short: "Validate the teresa.yaml and Procfile of an app",
+	Long: `Validate the teresa.yaml and the Procfile of an app folder (the current
+folder by default) without connecting to the server.
+
+Unknown keys, values of the wrong type, out of range values and process
+types missing in the Procfile are reported with their line numbers. The
+same validation runs before every deploy.`,
+	Example: `  $ teresa validate
+
+  $ teresa validate ~/src/webapi`,
+	Run: validateApp,
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+}
+
+func validateApp(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if !validateAppConfig(dir) {
+		os.Exit(1)
+	}
+	fmt.Println(color.GreenString("teresa.yaml and Procfile are valid"))
+}
+
+// validateAppConfig prints the problems of the config files of dir,
+// returning false if there is any.
+func validateAppConfig(dir string) bool {
+	_, _, err := appconfig.ParseDir(dir)
+	if err == nil {
+		return true
+	}
+	vErr, ok := err.(*appconfig.ValidationError)
+	if !ok {
+		client.PrintErrorAndExit("Error reading the config files: %v", err)
+	}
+	for _, fErr := range vErr.Errors {
+		fmt.Fprintln(os.Stderr, color.RedString(fErr.Error()))
+	}
+	return false
+}
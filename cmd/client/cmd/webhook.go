@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa-api/cmd/client/connection"
+	"github.com/luizalabs/teresa-api/pkg/client"
+	webhookpb "github.com/luizalabs/teresa-api/pkg/protobuf/webhook"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Everything about webhooks",
+}
+
+var webhookCreateCmd = &cobra.Command{
+	Use:   "create <url>",
+	Short: "Create a webhook",
+	Long: `Create a webhook of a team or of an app.
+
+The events are posted as JSON, signed with the HMAC-SHA256 of the body in the
+X-Teresa-Signature header. A random secret is generated when none is given.
+
+Available events: deploy.started, deploy.succeeded, deploy.failed,
+app.env_changed and app.created.`,
+	Example: `  $ teresa webhook create https://chat.foodomain.com/hook --team foo
+
+  $ teresa webhook create https://ci.foodomain.com/hook --app foo --events deploy.succeeded,deploy.failed`,
+	Run: webhookCreate,
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the webhooks of your teams",
+	Run:   webhookList,
+}
+
+var webhookDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a webhook",
+	Run:   webhookDelete,
+}
+
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries <id>",
+	Short: "Show the last deliveries of a webhook",
+	Run:   webhookDeliveries,
+}
+
+func init() {
+	RootCmd.AddCommand(webhookCmd)
+	webhookCmd.AddCommand(webhookCreateCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookDeleteCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+
+	webhookCreateCmd.Flags().String("team", "", "team name")
+	webhookCreateCmd.Flags().String("app", "", "app name")
+	webhookCreateCmd.Flags().String("secret", "", "secret used to sign the payloads")
+	webhookCreateCmd.Flags().StringSlice("events", nil, "events to send (default all)")
+
+	webhookDeliveriesCmd.Flags().Int64("limit", 20, "number of deliveries to show")
+}
+
+func webhookCreate(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	team, _ := cmd.Flags().GetString("team")
+	app, _ := cmd.Flags().GetString("app")
+	if team == "" && app == "" {
+		cmd.Usage()
+		return
+	}
+	secret, _ := cmd.Flags().GetString("secret")
+	events, err := cmd.Flags().GetStringSlice("events")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid events parameter: %v", err)
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := webhookpb.NewWebhookClient(conn)
+	req := &webhookpb.CreateRequest{
+		Team:   team,
+		App:    app,
+		Url:    args[0],
+		Secret: secret,
+		Events: events,
+	}
+	resp, err := cli.Create(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Webhook %s created with success\n", color.CyanString("%d", resp.Id))
+	if secret == "" {
+		fmt.Println("Secret:", resp.Secret)
+	}
+}
+
+func webhookList(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := webhookpb.NewWebhookClient(conn)
+	resp, err := cli.List(context.Background(), &webhookpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Webhooks) == 0 {
+		fmt.Println("No webhooks found")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "TEAM", "APP", "URL", "EVENTS"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, h := range resp.Webhooks {
+		events := "all"
+		if len(h.Events) > 0 {
+			events = strings.Join(h.Events, ",")
+		}
+		table.Append([]string{fmt.Sprint(h.Id), h.Team, h.App, h.Url, events})
+	}
+	table.Render()
+}
+
+func parseWebhookId(cmd *cobra.Command, args []string) (uint64, bool) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return 0, false
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		client.PrintErrorAndExit("Invalid webhook id: %s", args[0])
+	}
+	return id, true
+}
+
+func webhookDelete(cmd *cobra.Command, args []string) {
+	id, ok := parseWebhookId(cmd, args)
+	if !ok {
+		return
+	}
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := webhookpb.NewWebhookClient(conn)
+	if _, err := cli.Delete(context.Background(), &webhookpb.DeleteRequest{Id: id}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Webhook deleted with success")
+}
+
+func webhookDeliveries(cmd *cobra.Command, args []string) {
+	id, ok := parseWebhookId(cmd, args)
+	if !ok {
+		return
+	}
+	limit, _ := cmd.Flags().GetInt64("limit")
+
+	conn, err := connection.New(cfgFile)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := webhookpb.NewWebhookClient(conn)
+	req := &webhookpb.DeliveriesRequest{Id: id, Limit: limit}
+	resp, err := cli.Deliveries(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Deliveries) == 0 {
+		fmt.Println("No deliveries found")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"DATE", "EVENT", "ATTEMPT", "STATUS", "RESULT"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, d := range resp.Deliveries {
+		result := color.GreenString("ok")
+		if !d.Success {
+			result = color.RedString(d.Error)
+		}
+		table.Append([]string{d.CreatedAt, d.Event, fmt.Sprint(d.Attempt), fmt.Sprint(d.StatusCode), result})
+	}
+	table.Render()
+}
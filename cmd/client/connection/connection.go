@@ -1,14 +1,49 @@
 package connection
 
 import (
+	"os"
+	"time"
+
+	context "golang.org/x/net/context"
+
 	"github.com/luizalabs/teresa-api/pkg/client"
+	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
 	"google.golang.org/grpc"
 )
 
+// refreshMargin is how long before its expiration the login token is renewed.
+const refreshMargin = time.Minute
+
 func New(cfgFile string) (*grpc.ClientConn, error) {
 	cfg, err := client.GetConfig(cfgFile)
 	if err != nil {
 		return nil, err
 	}
+	if shouldRefresh(cfg) {
+		// on errors the old token is kept and the server asks for a login
+		if res, err := refresh(cfg); err == nil {
+			cfg.Token = res.Token
+			if err := client.SaveToken(cfgFile, res.Token, res.RefreshToken); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return client.New(*cfg)
 }
+
+func shouldRefresh(cfg *client.ClusterConfig) bool {
+	return cfg.RefreshToken != "" &&
+		os.Getenv(client.TokenEnvVar) == "" &&
+		client.TokenExpired(cfg.Token, refreshMargin)
+}
+
+func refresh(cfg *client.ClusterConfig) (*userpb.LoginResponse, error) {
+	conn, err := client.New(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	return cli.Refresh(context.Background(), &userpb.RefreshRequest{RefreshToken: cfg.RefreshToken})
+}
@@ -0,0 +1,208 @@
+// Package ignore implements the gitignore pattern format, used by the
+// .teresaignore (and, optionally, the .gitignore) files.
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type pattern struct {
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher holds the patterns of a tree of ignore files; like in git, the
+// last matching pattern decides, so the patterns of deeper files override
+// the ones of their parents.
+type Matcher struct {
+	patterns []*pattern
+}
+
+// Add adds the lines of an ignore file of the dir base, a slash separated
+// path relative to the root of the tree ("" for the root itself).
+func (m *Matcher) Add(base string, lines []string) {
+	for _, l := range lines {
+		if p := parsePattern(base, l); p != nil {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+}
+
+// AddFile adds the patterns of the ignore file name of the dir base, a
+// missing file is not an error.
+func (m *Matcher) AddFile(base, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	m.Add(base, lines)
+	return nil
+}
+
+// Match reports whether the slash separated path, relative to the root of
+// the tree, is ignored. The parent dirs aren't checked, walkers must skip
+// the ignored dirs.
+func (m *Matcher) Match(p string, isDir bool) bool {
+	ignored := false
+	for _, pt := range m.patterns {
+		if pt.dirOnly && !isDir {
+			continue
+		}
+		rel := p
+		if pt.base != "" {
+			if !strings.HasPrefix(p, pt.base+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(p, pt.base+"/")
+		}
+		if pt.re.MatchString(rel) {
+			ignored = !pt.negate
+		}
+	}
+	return ignored
+}
+
+func parsePattern(base, line string) *pattern {
+	line = strings.TrimRight(line, "\r")
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	p := &pattern{base: strings.Trim(base, "/")}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+
+	// a slash at the beginning or in the middle anchors the pattern to the
+	// dir of the ignore file, otherwise it matches at any level
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return nil
+	}
+	p.re = re
+	return p
+}
+
+// trimTrailingSpaces removes the trailing spaces not escaped with a
+// backslash.
+func trimTrailingSpaces(s string) string {
+	for strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\\ ") {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func globToRegexp(glob string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**") && (i == 0 || glob[i-1] == '/'):
+			rest := glob[i+2:]
+			switch {
+			case rest == "":
+				b.WriteString(".*")
+				i++
+			case rest[0] == '/':
+				b.WriteString("(?:.*/)?")
+				i += 2
+			default:
+				b.WriteString("[^/]*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// WalkFunc is called with the path of each file not ignored and its
+// slash separated name relative to the root.
+type WalkFunc func(path, name string) error
+
+// Walk walks the files of root skipping the ones ignored by m or by the
+// ignore files (like .teresaignore) found in each dir of the tree.
+func Walk(root string, m *Matcher, ignoreFiles []string, fn WalkFunc) error {
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if name == "." {
+			name = ""
+		} else if m.Match(name, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			return fn(p, name)
+		}
+		for _, f := range ignoreFiles {
+			if err := m.AddFile(name, filepath.Join(p, f)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
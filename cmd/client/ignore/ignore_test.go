@@ -0,0 +1,116 @@
+package ignore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	var testCases = []struct {
+		patterns []string
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{[]string{"*.pyc"}, "app/models.pyc", false, true},
+		{[]string{"*.pyc"}, "app/models.py", false, false},
+		{[]string{"node_modules"}, "web/static/node_modules", true, true},
+		{[]string{"/build"}, "build", true, true},
+		{[]string{"/build"}, "app/build", true, false},
+		{[]string{"build/"}, "app/build", true, true},
+		{[]string{"build/"}, "app/build", false, false},
+		{[]string{"doc/*.txt"}, "doc/notes.txt", false, true},
+		{[]string{"doc/*.txt"}, "doc/server/arch.txt", false, false},
+		{[]string{"**/logs"}, "a/b/logs", true, true},
+		{[]string{"logs/**"}, "logs/a/b.log", false, true},
+		{[]string{"a/**/b"}, "a/b", true, true},
+		{[]string{"a/**/b"}, "a/x/y/b", true, true},
+		{[]string{"*.log", "!keep.log"}, "keep.log", false, false},
+		{[]string{"*.log", "!keep.log"}, "other.log", false, true},
+		{[]string{"!keep.log", "*.log"}, "keep.log", false, true},
+		{[]string{"file?.txt"}, "file1.txt", false, true},
+		{[]string{"file[0-9].txt"}, "filex.txt", false, false},
+		{[]string{"file[!0-9].txt"}, "filex.txt", false, true},
+		{[]string{"# comment", "", `\#hash`}, "#hash", false, true},
+		{[]string{"trailing   "}, "trailing", false, true},
+	}
+
+	for _, tc := range testCases {
+		m := new(Matcher)
+		m.Add("", tc.patterns)
+		if actual := m.Match(tc.path, tc.isDir); actual != tc.expected {
+			t.Errorf("expected %v for %s with %v, got %v", tc.expected, tc.path, tc.patterns, actual)
+		}
+	}
+}
+
+func TestMatchBase(t *testing.T) {
+	m := new(Matcher)
+	m.Add("", []string{"*.tmp"})
+	m.Add("web", []string{"/dist", "!important.tmp"})
+
+	var testCases = []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"web/dist", true, true},
+		{"dist", true, false},
+		{"api/dist", true, false},
+		{"web/important.tmp", false, false},
+		{"api/important.tmp", false, true},
+	}
+
+	for _, tc := range testCases {
+		if actual := m.Match(tc.path, tc.isDir); actual != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.path, actual)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root, err := ioutil.TempDir("", "ignore")
+	if err != nil {
+		t.Fatal("error creating temp dir:", err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		".teresaignore":                 "node_modules/\n/secrets\n",
+		"app.py":                        "",
+		"secrets/key":                   "",
+		"web/node_modules/lib/index.js": "",
+		"web/index.js":                  "",
+		"web/.teresaignore":             "*.map\n",
+		"web/index.js.map":              "",
+		"lib/secrets/readme":            "",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal("error creating dir:", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal("error writing file:", err)
+		}
+	}
+
+	var actual []string
+	err = Walk(root, new(Matcher), []string{".teresaignore"}, func(path, name string) error {
+		actual = append(actual, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("error walking:", err)
+	}
+
+	expected := []string{".teresaignore", "app.py", "lib/secrets/readme", "web/.teresaignore", "web/index.js"}
+	sort.Strings(actual)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -28,6 +28,20 @@ type Team struct {
 	URL   string        `gorm:"size:1024;"`
 	Users []User        `gorm:"many2many:teams_users;"`
 	Apps  []Application `gorm:"ForeignKey:TeamID"`
+	// Roles of the Users by email, loaded with them by the team listings
+	Roles map[string]string `gorm:"-"`
+}
+
+// TeamUser represents the membership of a user in a team and its role
+type TeamUser struct {
+	TeamID uint   `gorm:"primary_key;auto_increment:false;"`
+	UserID uint   `gorm:"primary_key;auto_increment:false;"`
+	Role   string `gorm:"size:16;not null;default:'member';"`
+}
+
+// TableName is the many2many join table of teams and users
+func (TeamUser) TableName() string {
+	return "teams_users"
 }
 
 // User represents a developer
@@ -38,6 +52,14 @@ type User struct {
 	Password string `gorm:"size:60;not null;"`
 	IsAdmin  bool   `gorm:"not null;"`
 	Teams    []Team `gorm:"many2many:teams_users;"`
+	// TokenVersion is bumped to revoke all the tokens of the user
+	TokenVersion int `gorm:"not null;default:0;"`
+	// TOTPSecret is the base32 secret of the two-factor authentication,
+	// enrolled but not used until TOTPEnabled; TOTPCounter is the time step
+	// of the last code used, which can't be used again
+	TOTPSecret  string `gorm:"size:32;"`
+	TOTPEnabled bool   `gorm:"not null;default:false;"`
+	TOTPCounter int64  `gorm:"not null;default:0;"`
 }
 
 // Application represents an application
@@ -77,6 +99,86 @@ type Deployment struct {
 	AppID uint
 }
 
+// Webhook represents an outgoing webhook subscription of a team or of a
+// single app
+type Webhook struct {
+	BaseModel
+	Team      string `gorm:"size:128;not null;index;"`
+	App       string `gorm:"size:128;index;"`
+	URL       string `gorm:"size:1024;not null;"`
+	Secret    string `gorm:"size:128;not null;"`
+	Events    string `gorm:"size:1024;"`
+	CreatedBy string `gorm:"size:64;"`
+}
+
+// WebhookDelivery represents an attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	BaseModel
+	WebhookID  uint   `gorm:"not null;index;"`
+	Event      string `gorm:"size:64;not null;"`
+	Payload    string `gorm:"type:text;"`
+	Attempt    int    `gorm:"not null;"`
+	StatusCode int
+	Error      string `gorm:"size:2048;"`
+	Success    bool   `gorm:"not null;"`
+}
+
+// AuditEntry represents a call of a mutating rpc, with the request
+// parameters without secrets
+type AuditEntry struct {
+	BaseModel
+	User    string `gorm:"size:64;index;"`
+	Method  string `gorm:"size:128;not null;index;"`
+	Team    string `gorm:"size:128;index;"`
+	App     string `gorm:"size:128;index;"`
+	Params  string `gorm:"type:text;"`
+	Success bool   `gorm:"not null;"`
+	Error   string `gorm:"size:2048;"`
+	// Target names the resource of the rpcs targeting no app or team, like
+	// the token of Token.Revoke
+	Target string `gorm:"size:128;"`
+}
+
+// APIToken represents a long-lived token of a user scoped to apps or teams
+// and to actions, only the hash of the token is stored
+type APIToken struct {
+	BaseModel
+	Name       string `gorm:"size:128;"`
+	User       string `gorm:"size:64;not null;index;"`
+	Hash       string `gorm:"size:64;not null;unique_index;"`
+	Prefix     string `gorm:"size:16;not null;"`
+	Apps       string `gorm:"size:1024;"`
+	Teams      string `gorm:"size:1024;"`
+	Actions    string `gorm:"size:64;not null;"`
+	LastUsedAt *time.Time
+}
+
+// RevokedToken represents a token revoked before its expiration (on logout),
+// kept until it expires
+type RevokedToken struct {
+	BaseModel
+	TokenID   string    `gorm:"size:32;not null;unique_index;"`
+	ExpiresAt time.Time `gorm:"not null;index;"`
+}
+
+// RecoveryCode represents a single use code of a user to login without
+// its two-factor authentication device, only the hash is stored
+type RecoveryCode struct {
+	BaseModel
+	UserID uint   `gorm:"not null;index;"`
+	Hash   string `gorm:"size:64;not null;unique_index;"`
+}
+
+// LoginAttempts counts the recent failed logins of an e-mail or of a client
+// address, which is locked out after too many of them
+type LoginAttempts struct {
+	BaseModel
+	Subject      string    `gorm:"size:128;not null;unique_index;"`
+	Failures     int       `gorm:"not null;"`
+	FirstFailure time.Time `gorm:"not null;"`
+	LockedUntil  *time.Time
+}
+
 // Authenticate check if the user's password matches via bcrypt
 func (u *User) Authenticate(p *string) (err error) {
 	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(*p))
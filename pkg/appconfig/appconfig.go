@@ -0,0 +1,186 @@
+// Package appconfig parses and validates the teresa.yaml and Procfile of
+// the apps, it's shared by the server and the client so the errors can be
+// found before uploading the app.
+package appconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	TeresaYamlFileName = "teresa.yaml"
+	ProcfileFileName   = "Procfile"
+	DockerfileFileName = "Dockerfile"
+)
+
+const (
+	BuildSlug   = "slug"
+	BuildDocker = "docker"
+)
+
+const (
+	SchemeHTTP  = "HTTP"
+	SchemeHTTPS = "HTTPS"
+)
+
+const (
+	ProtocolTCP = "TCP"
+	ProtocolUDP = "UDP"
+)
+
+const (
+	// DefaultPort is the app port when the teresa.yaml doesn't set one.
+	DefaultPort = 5000
+	// AppPortName is the name of the app port in the container and the
+	// service.
+	AppPortName = "http"
+)
+
+const (
+	maxDrainTimeoutSeconds = 30
+	maxPort                = 65535
+	maxPortNameLength      = 15
+)
+
+type HTTPHeader struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type TCPSocketProbe struct {
+	Port int32 `yaml:"port,omitempty"`
+}
+
+type ExecProbe struct {
+	Command []string `yaml:"command"`
+}
+
+// HealthCheckProbe is an http probe on Path (the default), unless one of
+// TCPSocket or Exec is set.
+type HealthCheckProbe struct {
+	FailureThreshold    int32           `yaml:"failureThreshold"`
+	InitialDelaySeconds int32           `yaml:"initialDelaySeconds"`
+	PeriodSeconds       int32           `yaml:"periodSeconds"`
+	SuccessThreshold    int32           `yaml:"successThreshold"`
+	TimeoutSeconds      int32           `yaml:"timeoutSeconds"`
+	Path                string          `yaml:"path"`
+	Scheme              string          `yaml:"scheme,omitempty"`
+	Headers             []*HTTPHeader   `yaml:"headers,omitempty"`
+	TCPSocket           *TCPSocketProbe `yaml:"tcpSocket,omitempty"`
+	Exec                *ExecProbe      `yaml:"exec,omitempty"`
+}
+
+// HealthCheck holds the probes of the app; the ones in Processes replace
+// the top level probes for the given Procfile process types.
+type HealthCheck struct {
+	Liveness  *HealthCheckProbe       `yaml:"liveness,omitempty"`
+	Readiness *HealthCheckProbe       `yaml:"readiness,omitempty"`
+	Processes map[string]*HealthCheck `yaml:"processes,omitempty"`
+}
+
+// ForProcess returns the probes of the process type.
+func (hc *HealthCheck) ForProcess(processType string) *HealthCheck {
+	if hc == nil {
+		return nil
+	}
+	if p, found := hc.Processes[processType]; found {
+		return p
+	}
+	return &HealthCheck{Liveness: hc.Liveness, Readiness: hc.Readiness}
+}
+
+type RollingUpdate struct {
+	MaxSurge       string `yaml:"maxSurge,omitempty"`
+	MaxUnavailable string `yaml:"maxUnavailable,omitempty"`
+}
+
+type PreStop struct {
+	DrainTimeoutSeconds int `yaml:"drainTimeoutSeconds,omitempty"`
+}
+
+type Lifecycle struct {
+	PreStop *PreStop `yaml:"preStop,omitempty"`
+}
+
+// Port is an extra port of the app, exposed by the service on the same
+// number.
+type Port struct {
+	Name     string `yaml:"name"`
+	Port     int32  `yaml:"port"`
+	Protocol string `yaml:"protocol,omitempty"`
+}
+
+// ProtocolOrDefault returns the protocol of the port, TCP by default.
+func (p *Port) ProtocolOrDefault() string {
+	if p.Protocol == "" {
+		return ProtocolTCP
+	}
+	return strings.ToUpper(p.Protocol)
+}
+
+type TeresaYaml struct {
+	Build         string         `yaml:"build,omitempty"`
+	Port          int32          `yaml:"port,omitempty"`
+	Ports         []*Port        `yaml:"ports,omitempty"`
+	HealthCheck   *HealthCheck   `yaml:"healthCheck,omitempty"`
+	RollingUpdate *RollingUpdate `yaml:"rollingUpdate,omitempty"`
+	Lifecycle     *Lifecycle     `yaml:"lifecycle,omitempty"`
+}
+
+// AppPort returns the port the app listens on (the PORT env var).
+func (t *TeresaYaml) AppPort() int32 {
+	if t == nil || t.Port == 0 {
+		return DefaultPort
+	}
+	return t.Port
+}
+
+type Procfile map[string]string
+
+// Parse parses and validates the contents of the teresa.yaml and the
+// Procfile, nil contents are missing files. The error, if any, is a
+// *ValidationError with all the problems found.
+func Parse(teresaYaml, procfile []byte) (*TeresaYaml, Procfile, error) {
+	v := new(validator)
+	var tYaml *TeresaYaml
+	if teresaYaml != nil {
+		tYaml = new(TeresaYaml)
+		v.parse(TeresaYamlFileName, teresaYaml, tYaml)
+		v.validateTeresaYaml(tYaml)
+	}
+	var proc Procfile
+	if procfile != nil {
+		proc = make(Procfile)
+		v.parse(ProcfileFileName, procfile, &proc)
+		v.validateProcfile(proc)
+	}
+	if tYaml != nil && proc != nil {
+		v.validateProcesses(tYaml, proc)
+	}
+	return tYaml, proc, v.err()
+}
+
+// ParseDir reads and parses the teresa.yaml and the Procfile of dir, if
+// they exist.
+func ParseDir(dir string) (*TeresaYaml, Procfile, error) {
+	teresaYaml, err := readFile(filepath.Join(dir, TeresaYamlFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	procfile, err := readFile(filepath.Join(dir, ProcfileFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return Parse(teresaYaml, procfile)
+}
+
+func readFile(name string) ([]byte, error) {
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return b, err
+}
@@ -0,0 +1,178 @@
+package appconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const validTeresaYaml = `
+build: slug
+healthCheck:
+  liveness:
+    path: /healthcheck/
+    scheme: https
+    headers:
+    - name: Host
+      value: teresa.io
+  processes:
+    worker:
+      liveness:
+        exec:
+          command: ["cat", "/tmp/healthy"]
+lifecycle:
+  preStop:
+    drainTimeoutSeconds: 20
+`
+
+func TestParse(t *testing.T) {
+	tYaml, proc, err := Parse([]byte(validTeresaYaml), []byte("web: python app.py\nworker: python worker.py\n"))
+	if err != nil {
+		t.Fatal("error parsing the config files:", err)
+	}
+	if tYaml.HealthCheck.Liveness.Headers[0].Value != "teresa.io" {
+		t.Errorf("expected teresa.io, got %s", tYaml.HealthCheck.Liveness.Headers[0].Value)
+	}
+	if tYaml.HealthCheck.ForProcess("worker").Liveness.Exec == nil {
+		t.Error("expected exec probe for worker")
+	}
+	if proc["worker"] != "python worker.py" {
+		t.Errorf("expected python worker.py, got %s", proc["worker"])
+	}
+}
+
+func TestParseMissingFiles(t *testing.T) {
+	tYaml, proc, err := Parse(nil, nil)
+	if err != nil || tYaml != nil || proc != nil {
+		t.Errorf("expected nothing, got %v, %v, %v", tYaml, proc, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var testCases = []struct {
+		teresaYaml string
+		procfile   string
+		file       string
+		line       int
+		field      string
+	}{
+		{"build: maven\n", "", TeresaYamlFileName, 1, "build"},
+		{"healthCheck:\n  liveness:\n    pth: /\n", "", TeresaYamlFileName, 3, "healthCheck.liveness.pth"},
+		{"healthCheck:\n  liveness:\n    periodSeconds: abc\n", "", TeresaYamlFileName, 3, ""},
+		{"lifecycle:\n  preStop:\n    drainTimeoutSeconds: 40\n", "", TeresaYamlFileName, 3, "lifecycle.preStop.drainTimeoutSeconds"},
+		{"healthCheck:\n  processes:\n    worker:\n      liveness:\n        path: /\n", "web: python app.py\n", TeresaYamlFileName, 3, "healthCheck.processes.worker"},
+		{"healthCheck:\n  readiness:\n    headers:\n    - name: Host\n    - value: x\n", "", TeresaYamlFileName, 5, "healthCheck.readiness.headers[1].name"},
+		{"", "web: python app.py\nworker:\n", ProcfileFileName, 2, "worker"},
+		{"port: 70000\n", "", TeresaYamlFileName, 1, "port"},
+		{"ports:\n- name: grpc\n  port: 50051\n- name: http\n  port: 9100\n", "", TeresaYamlFileName, 4, "ports[1].name"},
+		{"port: 8080\nports:\n- name: metrics\n  port: 8080\n", "", TeresaYamlFileName, 4, "ports[0].port"},
+		{"ports:\n- name: metrics\n  port: 9100\n  protocol: sctp\n", "", TeresaYamlFileName, 4, "ports[0].protocol"},
+		{"ports:\n- name: Metrics_Port\n  port: 9100\n", "", TeresaYamlFileName, 2, "ports[0].name"},
+	}
+
+	for _, tc := range testCases {
+		var procfile []byte
+		if tc.procfile != "" {
+			procfile = []byte(tc.procfile)
+		}
+		_, _, err := Parse([]byte(tc.teresaYaml), procfile)
+		vErr, ok := err.(*ValidationError)
+		if !ok || len(vErr.Errors) != 1 {
+			t.Errorf("expected one validation error for %q, got %v", tc.teresaYaml, err)
+			continue
+		}
+		fErr := vErr.Errors[0]
+		if fErr.File != tc.file || fErr.Line != tc.line || fErr.Field != tc.field {
+			t.Errorf("expected %s:%d %s, got %s:%d %s", tc.file, tc.line, tc.field, fErr.File, fErr.Line, fErr.Field)
+		}
+	}
+}
+
+func TestValidateHealthCheck(t *testing.T) {
+	var testCases = []struct {
+		hc    *HealthCheck
+		field string
+	}{
+		{&HealthCheck{Liveness: &HealthCheckProbe{Path: "/hc/", Scheme: "https"}}, ""},
+		{&HealthCheck{Liveness: &HealthCheckProbe{TCPSocket: &TCPSocketProbe{Port: 6000}}}, ""},
+		{&HealthCheck{Processes: map[string]*HealthCheck{
+			"worker": {Liveness: &HealthCheckProbe{Exec: &ExecProbe{Command: []string{"true"}}}},
+		}}, ""},
+		{&HealthCheck{Liveness: &HealthCheckProbe{Scheme: "ftp"}}, "healthCheck.liveness.scheme"},
+		{&HealthCheck{Readiness: &HealthCheckProbe{PeriodSeconds: -1}}, "healthCheck.readiness.periodSeconds"},
+		{&HealthCheck{Liveness: &HealthCheckProbe{TCPSocket: &TCPSocketProbe{Port: 70000}}}, "healthCheck.liveness.tcpSocket.port"},
+		{&HealthCheck{Liveness: &HealthCheckProbe{Path: "/", TCPSocket: &TCPSocketProbe{}}}, "healthCheck.liveness"},
+		{&HealthCheck{Liveness: &HealthCheckProbe{TCPSocket: &TCPSocketProbe{}, Exec: &ExecProbe{}}}, "healthCheck.liveness"},
+		{&HealthCheck{Processes: map[string]*HealthCheck{
+			"worker": {Liveness: &HealthCheckProbe{Exec: &ExecProbe{}}},
+		}}, "healthCheck.processes.worker.liveness.exec.command"},
+		{&HealthCheck{Processes: map[string]*HealthCheck{
+			"worker": {Processes: map[string]*HealthCheck{"web": {}}},
+		}}, "healthCheck.processes.worker.processes"},
+	}
+
+	for _, tc := range testCases {
+		v := new(validator)
+		v.validateTeresaYaml(&TeresaYaml{HealthCheck: tc.hc})
+		if tc.field == "" {
+			if len(v.errs) != 0 {
+				t.Errorf("expected no error, got %v", v.err())
+			}
+			continue
+		}
+		if len(v.errs) != 1 || v.errs[0].Field != tc.field {
+			t.Errorf("expected error on %s, got %v", tc.field, v.err())
+		}
+	}
+}
+
+func TestHealthCheckForProcess(t *testing.T) {
+	web := &HealthCheckProbe{Path: "/hc/"}
+	worker := &HealthCheckProbe{Exec: &ExecProbe{Command: []string{"true"}}}
+	hc := &HealthCheck{
+		Liveness:  web,
+		Processes: map[string]*HealthCheck{"worker": {Liveness: worker}},
+	}
+
+	if actual := hc.ForProcess("web").Liveness; actual != web {
+		t.Errorf("expected %v, got %v", web, actual)
+	}
+	if actual := hc.ForProcess("worker").Liveness; actual != worker {
+		t.Errorf("expected %v, got %v", worker, actual)
+	}
+	var nilHC *HealthCheck
+	if nilHC.ForProcess("web") != nil {
+		t.Error("expected nil health check")
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appconfig")
+	if err != nil {
+		t.Fatal("error creating temp dir:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ProcfileFileName), []byte("web: python app.py\n"), 0644); err != nil {
+		t.Fatal("error writing Procfile:", err)
+	}
+
+	tYaml, proc, err := ParseDir(dir)
+	if err != nil {
+		t.Fatal("error parsing dir:", err)
+	}
+	if tYaml != nil {
+		t.Errorf("expected nil teresa.yaml, got %v", tYaml)
+	}
+	if proc["web"] != "python app.py" {
+		t.Errorf("expected python app.py, got %s", proc["web"])
+	}
+}
+
+func TestFieldErrorString(t *testing.T) {
+	err := &FieldError{File: "teresa.yaml", Line: 3, Field: "build", Msg: "invalid"}
+	if actual := err.Error(); actual != "teresa.yaml:3: build: invalid" {
+		t.Errorf("expected teresa.yaml:3: build: invalid, got %s", actual)
+	}
+}
@@ -0,0 +1,396 @@
+package appconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	yamlLineRe     = regexp.MustCompile(`line (\d+): (.*)`)
+	fieldSegmentRe = regexp.MustCompile(`[^.\[\]]+|\[\d+\]`)
+	portNameRe     = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+// FieldError is a problem in a config file, Field is the path of the
+// offending field (like healthCheck.liveness.scheme) and Line its line, or
+// zero when unknown.
+type FieldError struct {
+	File  string
+	Line  int
+	Field string
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	s := e.File
+	if e.Line > 0 {
+		s = fmt.Sprintf("%s:%d", s, e.Line)
+	}
+	if e.Field != "" {
+		s = fmt.Sprintf("%s: %s", s, e.Field)
+	}
+	return fmt.Sprintf("%s: %s", s, e.Msg)
+}
+
+// ValidationError holds all the problems found in the config files.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type validator struct {
+	lines map[string][]string
+	errs  []*FieldError
+}
+
+func (v *validator) add(file, field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{
+		File:  file,
+		Line:  lineOf(v.lines[file], field),
+		Field: field,
+		Msg:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.Stable(fieldErrors(v.errs))
+	return &ValidationError{Errors: v.errs}
+}
+
+// fieldErrors sorts the errors by file (teresa.yaml first), line and field.
+type fieldErrors []*FieldError
+
+func (e fieldErrors) Len() int      { return len(e) }
+func (e fieldErrors) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e fieldErrors) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	if a.File != b.File {
+		return a.File > b.File
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Field < b.Field
+}
+
+// parse unmarshals b into out, collecting the syntax and type errors and
+// the keys out doesn't know.
+func (v *validator) parse(file string, b []byte, out interface{}) {
+	if v.lines == nil {
+		v.lines = make(map[string][]string)
+	}
+	v.lines[file] = strings.Split(string(b), "\n")
+
+	if err := yaml.Unmarshal(b, out); err != nil {
+		msgs := []string{err.Error()}
+		if tErr, ok := err.(*yaml.TypeError); ok {
+			msgs = tErr.Errors
+		}
+		for _, m := range msgs {
+			fErr := &FieldError{File: file, Msg: strings.TrimPrefix(m, "yaml: ")}
+			if sm := yamlLineRe.FindStringSubmatch(m); sm != nil {
+				fErr.Line, _ = strconv.Atoi(sm[1])
+				fErr.Msg = sm[2]
+			}
+			v.errs = append(v.errs, fErr)
+		}
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(b, &raw); err == nil {
+		v.checkKeys(file, "", raw, reflect.TypeOf(out))
+	}
+}
+
+func (v *validator) checkKeys(file, field string, raw interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return // type errors are reported by the unmarshal
+		}
+		for k, val := range m {
+			name := fmt.Sprint(k)
+			f := joinField(field, name)
+			ft, found := fieldTypeByTag(t, name)
+			if !found {
+				v.add(file, f, "unknown key")
+				continue
+			}
+			v.checkKeys(file, f, val, ft)
+		}
+	case reflect.Map:
+		m, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		for k, val := range m {
+			v.checkKeys(file, joinField(field, fmt.Sprint(k)), val, t.Elem())
+		}
+	case reflect.Slice:
+		l, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for i, val := range l {
+			v.checkKeys(file, fmt.Sprintf("%s[%d]", field, i), val, t.Elem())
+		}
+	}
+}
+
+func fieldTypeByTag(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if tag == "" {
+			tag = strings.ToLower(f.Name)
+		}
+		if tag == name {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// lineOf returns the line (starting at one) of a field of a block style
+// yaml document; missing fields get the line of their closest parent, or
+// zero.
+func lineOf(lines []string, field string) int {
+	start, indent, inItem := 0, -1, false
+	line := 0
+	for _, seg := range fieldSegmentRe.FindAllString(field, -1) {
+		found := false
+		if strings.HasPrefix(seg, "[") {
+			idx, _ := strconv.Atoi(seg[1 : len(seg)-1])
+			n := 0
+			for i := start; i < len(lines); i++ {
+				r, c := splitIndent(lines[i])
+				if c == "" || strings.HasPrefix(c, "#") {
+					continue
+				}
+				isItem := c == "-" || strings.HasPrefix(c, "- ")
+				if r < indent || (r == indent && !isItem) {
+					break
+				}
+				if !isItem {
+					continue
+				}
+				if n == idx {
+					start, indent, inItem, line, found = i, r, true, i+1, true
+					break
+				}
+				n++
+			}
+		} else {
+			childIndent := -1
+			for i := start; i < len(lines); i++ {
+				r, c := splitIndent(lines[i])
+				if c == "" || strings.HasPrefix(c, "#") {
+					continue
+				}
+				if r <= indent && !(inItem && i == start) {
+					break
+				}
+				eff := r
+				if strings.HasPrefix(c, "- ") {
+					eff, c = r+2, strings.TrimLeft(c[2:], " ")
+				}
+				if childIndent < 0 {
+					childIndent = eff
+				}
+				if eff == childIndent && strings.HasPrefix(c, seg+":") {
+					start, indent, inItem, line, found = i+1, eff, false, i+1, true
+					break
+				}
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return line
+}
+
+func splitIndent(s string) (int, string) {
+	c := strings.TrimLeft(s, " ")
+	return len(s) - len(c), strings.TrimRight(c, " \r")
+}
+
+func (v *validator) validateTeresaYaml(tYaml *TeresaYaml) {
+	switch tYaml.Build {
+	case "", BuildSlug, BuildDocker:
+	default:
+		v.add(TeresaYamlFileName, "build", "invalid value %q, expected %s or %s", tYaml.Build, BuildSlug, BuildDocker)
+	}
+	if tYaml.Port < 0 || tYaml.Port > maxPort {
+		v.add(TeresaYamlFileName, "port", "invalid port %d", tYaml.Port)
+	}
+	v.validatePorts(tYaml)
+	if tYaml.HealthCheck != nil {
+		v.validateHealthCheck("healthCheck", tYaml.HealthCheck, true)
+	}
+	if tYaml.Lifecycle != nil && tYaml.Lifecycle.PreStop != nil {
+		if tYaml.Lifecycle.PreStop.DrainTimeoutSeconds > maxDrainTimeoutSeconds || tYaml.Lifecycle.PreStop.DrainTimeoutSeconds < 0 {
+			v.add(TeresaYamlFileName, "lifecycle.preStop.drainTimeoutSeconds", "must be between 0 and %d", maxDrainTimeoutSeconds)
+		}
+	}
+}
+
+func (v *validator) validatePorts(tYaml *TeresaYaml) {
+	names := map[string]bool{AppPortName: true}
+	ports := map[int32]bool{tYaml.AppPort(): true}
+	for i, p := range tYaml.Ports {
+		field := fmt.Sprintf("ports[%d]", i)
+		if p == nil {
+			v.add(TeresaYamlFileName, field, "empty")
+			continue
+		}
+		switch {
+		case p.Name == "":
+			v.add(TeresaYamlFileName, field+".name", "must not be empty")
+		case len(p.Name) > maxPortNameLength || !portNameRe.MatchString(p.Name):
+			v.add(TeresaYamlFileName, field+".name", "invalid name %q, expected up to %d lowercase letters, digits and dashes", p.Name, maxPortNameLength)
+		case names[p.Name]:
+			v.add(TeresaYamlFileName, field+".name", "duplicated name %q", p.Name)
+		}
+		names[p.Name] = true
+
+		switch {
+		case p.Port <= 0 || p.Port > maxPort:
+			v.add(TeresaYamlFileName, field+".port", "invalid port %d", p.Port)
+		case ports[p.Port]:
+			v.add(TeresaYamlFileName, field+".port", "duplicated port %d", p.Port)
+		}
+		ports[p.Port] = true
+
+		switch p.ProtocolOrDefault() {
+		case ProtocolTCP, ProtocolUDP:
+		default:
+			v.add(TeresaYamlFileName, field+".protocol", "invalid value %q, expected %s or %s", p.Protocol, ProtocolTCP, ProtocolUDP)
+		}
+	}
+}
+
+func (v *validator) validateHealthCheck(field string, hc *HealthCheck, topLevel bool) {
+	if hc.Liveness != nil {
+		v.validateProbe(field+".liveness", hc.Liveness)
+	}
+	if hc.Readiness != nil {
+		v.validateProbe(field+".readiness", hc.Readiness)
+	}
+	if len(hc.Processes) > 0 && !topLevel {
+		v.add(TeresaYamlFileName, field+".processes", "not allowed in a process")
+		return
+	}
+	for name, p := range hc.Processes {
+		f := fmt.Sprintf("%s.processes.%s", field, name)
+		if p == nil {
+			v.add(TeresaYamlFileName, f, "empty")
+			continue
+		}
+		v.validateHealthCheck(f, p, false)
+	}
+}
+
+func (v *validator) validateProbe(field string, p *HealthCheckProbe) {
+	ints := []struct {
+		name  string
+		value int32
+	}{
+		{"failureThreshold", p.FailureThreshold},
+		{"initialDelaySeconds", p.InitialDelaySeconds},
+		{"periodSeconds", p.PeriodSeconds},
+		{"successThreshold", p.SuccessThreshold},
+		{"timeoutSeconds", p.TimeoutSeconds},
+	}
+	for _, i := range ints {
+		if i.value < 0 {
+			v.add(TeresaYamlFileName, field+"."+i.name, "must not be negative")
+		}
+	}
+
+	var handlers []string
+	if p.TCPSocket != nil {
+		handlers = append(handlers, "tcpSocket")
+	}
+	if p.Exec != nil {
+		handlers = append(handlers, "exec")
+	}
+	if len(handlers) > 1 {
+		v.add(TeresaYamlFileName, field, "only one of tcpSocket and exec can be set")
+		return
+	}
+	if len(handlers) == 1 && (p.Path != "" || p.Scheme != "" || len(p.Headers) > 0) {
+		v.add(TeresaYamlFileName, field, "path, scheme and headers are not allowed with %s", handlers[0])
+		return
+	}
+
+	switch {
+	case p.TCPSocket != nil:
+		if p.TCPSocket.Port < 0 || p.TCPSocket.Port > maxPort {
+			v.add(TeresaYamlFileName, field+".tcpSocket.port", "invalid port %d", p.TCPSocket.Port)
+		}
+	case p.Exec != nil:
+		if len(p.Exec.Command) == 0 {
+			v.add(TeresaYamlFileName, field+".exec.command", "must not be empty")
+		}
+	default:
+		switch strings.ToUpper(p.Scheme) {
+		case "", SchemeHTTP, SchemeHTTPS:
+		default:
+			v.add(TeresaYamlFileName, field+".scheme", "invalid value %q, expected %s or %s", p.Scheme, SchemeHTTP, SchemeHTTPS)
+		}
+		for i, h := range p.Headers {
+			if h == nil || h.Name == "" {
+				v.add(TeresaYamlFileName, fmt.Sprintf("%s.headers[%d].name", field, i), "must not be empty")
+			}
+		}
+	}
+}
+
+func (v *validator) validateProcfile(proc Procfile) {
+	for name, cmd := range proc {
+		if strings.TrimSpace(cmd) == "" {
+			v.add(ProcfileFileName, name, "empty command")
+		}
+	}
+}
+
+// validateProcesses checks that the process types of the teresa.yaml are
+// in the Procfile.
+func (v *validator) validateProcesses(tYaml *TeresaYaml, proc Procfile) {
+	if tYaml.HealthCheck == nil {
+		return
+	}
+	for name := range tYaml.HealthCheck.Processes {
+		if _, found := proc[name]; !found {
+			v.add(TeresaYamlFileName, "healthCheck.processes."+name, "process type %q not in the %s", name, ProcfileFileName)
+		}
+	}
+}
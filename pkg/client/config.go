@@ -12,10 +12,11 @@ import (
 )
 
 type ClusterConfig struct {
-	Server   string `yaml:"server"`
-	Token    string `yaml:"token"`
-	UseTLS   bool   `yaml:"tls"`
-	Insecure bool   `yaml:"insecure"`
+	Server       string `yaml:"server"`
+	Token        string `yaml:"token"`
+	RefreshToken string `yaml:"refresh_token,omitempty"`
+	UseTLS       bool   `yaml:"tls"`
+	Insecure     bool   `yaml:"insecure"`
 }
 
 type Config struct {
@@ -23,6 +24,10 @@ type Config struct {
 	CurrentCluster string                   `yaml:"current_cluster"`
 }
 
+// TokenEnvVar holds a token (like an API token in a CI job) used instead of the
+// one of the current cluster.
+const TokenEnvVar = "TERESA_TOKEN"
+
 var (
 	DefaultConfigFileLocation string
 	ErrInvalidConfigFile      = errors.New("Invalid config file")
@@ -38,7 +43,7 @@ func init() {
 	DefaultConfigFileLocation = filepath.Join(homeDir, ".teresa", "config.yaml")
 }
 
-func SaveToken(cfgFile, token string) error {
+func SaveToken(cfgFile, token, refreshToken string) error {
 	cfg, err := ReadConfigFile(cfgFile)
 	if err != nil {
 		return err
@@ -49,6 +54,7 @@ func SaveToken(cfgFile, token string) error {
 	}
 
 	cc.Token = token
+	cc.RefreshToken = refreshToken
 	cfg.Clusters[cfg.CurrentCluster] = cc
 	return SaveConfigFile(cfgFile, cfg)
 }
@@ -83,6 +89,9 @@ func GetConfig(cfgFile string) (*ClusterConfig, error) {
 	if !ok {
 		return nil, ErrInvalidConfigFile
 	}
+	if token := os.Getenv(TokenEnvVar); token != "" {
+		currentClusterConfig.Token = token
+	}
 	return &currentClusterConfig, nil
 }
 
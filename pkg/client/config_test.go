@@ -82,6 +82,20 @@ func TestGetConfigForValidFiles(t *testing.T) {
 	}
 }
 
+func TestGetConfigTokenFromEnv(t *testing.T) {
+	expectedToken := "trs_api-token"
+	os.Setenv(TokenEnvVar, expectedToken)
+	defer os.Unsetenv(TokenEnvVar)
+
+	c, err := GetConfig(filepath.Join("testdata", "validConfigFile.yaml"))
+	if err != nil {
+		t.Fatal("error trying to get config: ", err)
+	}
+	if c.Token != expectedToken {
+		t.Errorf("expected %s, got %s", expectedToken, c.Token)
+	}
+}
+
 func TestSaveConfigFile(t *testing.T) {
 	var testCases = []struct {
 		path         string
@@ -138,7 +152,7 @@ func TestSaveTokenForInvalidConfigFile(t *testing.T) {
 	}
 	defer os.Remove(confPath)
 
-	if err := SaveToken(confPath, "gopher"); err != ErrInvalidConfigFile {
+	if err := SaveToken(confPath, "gopher", ""); err != ErrInvalidConfigFile {
 		t.Errorf("expected ErrInvalidConfigFile, got %v", err)
 	}
 }
@@ -159,7 +173,8 @@ func TestSaveToken(t *testing.T) {
 	defer os.Remove(confPath)
 
 	expectedToken := "gopher"
-	if err := SaveToken(confPath, expectedToken); err != nil {
+	expectedRefreshToken := "gopher-refresh"
+	if err := SaveToken(confPath, expectedToken, expectedRefreshToken); err != nil {
 		t.Fatal("error trying to save token: ", err)
 	}
 
@@ -171,4 +186,7 @@ func TestSaveToken(t *testing.T) {
 	if c.Token != expectedToken {
 		t.Errorf("expected %s, got %s", expectedToken, c.Token)
 	}
+	if c.RefreshToken != expectedRefreshToken {
+		t.Errorf("expected %s, got %s", expectedRefreshToken, c.RefreshToken)
+	}
 }
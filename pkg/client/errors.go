@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/color"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -17,6 +18,13 @@ func GetErrorMsg(err error) string {
 	return stat.Message()
 }
 
+// IsCodeRequired reports whether the login failed for lack of the
+// two-factor authentication code.
+func IsCodeRequired(err error) bool {
+	stat, ok := status.FromError(err)
+	return ok && stat.Code() == codes.Unauthenticated
+}
+
 func PrintErrorAndExit(format string, args ...interface{}) {
 	fmt.Fprintln(os.Stderr, color.RedString(format, args...))
 	os.Exit(1)
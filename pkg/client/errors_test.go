@@ -31,6 +31,23 @@ func TestGetErrorMsg(t *testing.T) {
 
 }
 
+func TestIsCodeRequired(t *testing.T) {
+	var testCases = []struct {
+		err      error
+		expected bool
+	}{
+		{status.Errorf(codes.Unauthenticated, "Two-factor authentication code required"), true},
+		{auth.ErrPermissionDenied, false},
+		{errors.New("Generic Error"), false},
+	}
+
+	for _, tc := range testCases {
+		if actual := IsCodeRequired(tc.err); actual != tc.expected {
+			t.Errorf("expected %v for %v, got %v", tc.expected, tc.err, actual)
+		}
+	}
+}
+
 func TestPrintErrorAndExit(t *testing.T) {
 	if os.Getenv("PRINT_ERROR_AND_EXIT") == "1" {
 		PrintErrorAndExit("Some terrible error")
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	oidcDiscoveryPath       = "/.well-known/openid-configuration"
+	oidcCallbackPath        = "/callback"
+	deviceCodeGrantType     = "urn:ietf:params:oauth:grant-type:device_code"
+	defaultDevicePollPeriod = 5 * time.Second
+)
+
+var (
+	ErrOIDCTimeout        = errors.New("Timeout waiting for the login")
+	ErrOIDCInvalidState   = errors.New("Invalid state in the login callback")
+	ErrOIDCDeviceNotFound = errors.New("The identity provider doesn't support the device flow")
+)
+
+// OIDCLogin gets an ID token from an OpenID Connect issuer, with the
+// authorization code flow with PKCE or the device flow.
+type OIDCLogin struct {
+	Issuer   string
+	ClientID string
+	Scopes   []string
+	// Timeout is how long to wait for the user to login
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+type oidcDiscovery struct {
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type oidcDeviceResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+func (o *OIDCLogin) discover() (*oidcDiscovery, error) {
+	resp, err := o.Client.Get(strings.TrimSuffix(o.Issuer, "/") + oidcDiscoveryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching the identity provider metadata: %s", resp.Status)
+	}
+	d := new(oidcDiscovery)
+	return d, json.NewDecoder(resp.Body).Decode(d)
+}
+
+// AuthCode runs the authorization code flow with PKCE, receiving the code on
+// a local listener; open is called with the URL the user must visit. It
+// returns the ID token and its nonce.
+func (o *OIDCLogin) AuthCode(open func(authURL string)) (string, string, error) {
+	d, err := o.discover()
+	if err != nil {
+		return "", "", err
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", "", err
+	}
+	defer l.Close()
+	redirectURI := fmt.Sprintf("http://%s%s", l.Addr().String(), oidcCallbackPath)
+
+	state, nonce, verifier := randomString(), randomString(), randomString()
+	sum := sha256.Sum256([]byte(verifier))
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {o.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {strings.Join(o.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, ErrOIDCInvalidState.Error(), http.StatusBadRequest)
+			errs <- ErrOIDCInvalidState
+		case q.Get("error") != "":
+			http.Error(w, q.Get("error"), http.StatusBadRequest)
+			errs <- fmt.Errorf("%s: %s", q.Get("error"), q.Get("error_description"))
+		default:
+			fmt.Fprintln(w, "Login OK, you can close this window.")
+			codes <- q.Get("code")
+		}
+	})
+	go http.Serve(l, mux)
+
+	open(d.AuthorizationEndpoint + "?" + params.Encode())
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return "", "", err
+	case <-time.After(o.Timeout):
+		return "", "", ErrOIDCTimeout
+	}
+
+	tr, err := o.token(d.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {o.ClientID},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return tr.IDToken, nonce, nil
+}
+
+// Device runs the device flow, show is called with the code the user must
+// enter in the verification URL. It returns the ID token.
+func (o *OIDCLogin) Device(show func(userCode, verificationURI string)) (string, error) {
+	d, err := o.discover()
+	if err != nil {
+		return "", err
+	}
+	if d.DeviceAuthorizationEndpoint == "" {
+		return "", ErrOIDCDeviceNotFound
+	}
+
+	resp, err := o.Client.PostForm(d.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {o.ClientID},
+		"scope":     {strings.Join(o.Scopes, " ")},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("starting the device flow: %s", resp.Status)
+	}
+	dr := new(oidcDeviceResponse)
+	if err := json.NewDecoder(resp.Body).Decode(dr); err != nil {
+		return "", err
+	}
+
+	verificationURI := dr.VerificationURIComplete
+	if verificationURI == "" {
+		verificationURI = dr.VerificationURI
+	}
+	show(dr.UserCode, verificationURI)
+
+	interval := time.Duration(dr.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollPeriod
+	}
+	timeout := o.Timeout
+	if dr.ExpiresIn > 0 && time.Duration(dr.ExpiresIn)*time.Second < timeout {
+		timeout = time.Duration(dr.ExpiresIn) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+		tr, err := o.token(d.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {dr.DeviceCode},
+			"client_id":   {o.ClientID},
+		})
+		if err == nil {
+			return tr.IDToken, nil
+		}
+		if tr == nil {
+			return "", err
+		}
+		switch tr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return "", err
+		}
+	}
+	return "", ErrOIDCTimeout
+}
+
+// token calls the token endpoint; the response is returned with OAuth2
+// errors, to check the pending device flow.
+func (o *OIDCLogin) token(endpoint string, params url.Values) (*oidcTokenResponse, error) {
+	resp, err := o.Client.PostForm(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tr := new(oidcTokenResponse)
+	if err := json.NewDecoder(resp.Body).Decode(tr); err != nil {
+		return nil, fmt.Errorf("invalid token response: %s", resp.Status)
+	}
+	if tr.Error != "" {
+		return tr, fmt.Errorf("%s: %s", tr.Error, tr.ErrorDescription)
+	}
+	if tr.IDToken == "" {
+		return nil, errors.New("token response without ID token")
+	}
+	return tr, nil
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// NewOIDCLogin returns an OIDCLogin with the config of the server.
+func NewOIDCLogin(issuer, clientID string, scopes []string) *OIDCLogin {
+	return &OIDCLogin{
+		Issuer:   issuer,
+		ClientID: clientID,
+		Scopes:   scopes,
+		Timeout:  5 * time.Minute,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
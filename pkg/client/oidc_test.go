@@ -0,0 +1,77 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/luizalabs/teresa-api/pkg/server/oidc"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+)
+
+func TestOIDCLoginAuthCode(t *testing.T) {
+	issuer, err := oidc.NewFakeIssuer("teresa", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("error starting fake issuer: ", err)
+	}
+	defer issuer.Close()
+
+	o := NewOIDCLogin(issuer.URL, "teresa", []string{"openid", "email"})
+	// the browser of the user, the fake issuer logs in without asking
+	open := func(authURL string) {
+		go func() {
+			resp, err := http.Get(authURL)
+			if err != nil {
+				t.Error("error opening the authorization URL: ", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	idToken, nonce, err := o.AuthCode(open)
+	if err != nil {
+		t.Fatal("error on auth code flow: ", err)
+	}
+	if idToken == "" || nonce == "" {
+		t.Fatalf("expected an ID token and a nonce, got %q and %q", idToken, nonce)
+	}
+
+	tOps := team.NewFakeOperations().(*team.FakeOperations)
+	opts := &oidc.Options{Issuer: issuer.URL, ClientID: "teresa", EmailClaim: "email"}
+	if _, err := oidc.NewOperations(opts, tOps.UserOps, tOps).Login(idToken, nonce, ""); err != nil {
+		t.Error("error on server login with the ID token: ", err)
+	}
+}
+
+func TestOIDCLoginDevice(t *testing.T) {
+	issuer, err := oidc.NewFakeIssuer("teresa", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("error starting fake issuer: ", err)
+	}
+	defer issuer.Close()
+
+	o := NewOIDCLogin(issuer.URL, "teresa", []string{"openid", "email"})
+	var userCode string
+	idToken, err := o.Device(func(code, uri string) { userCode = code })
+	if err != nil {
+		t.Fatal("error on device flow: ", err)
+	}
+	if userCode == "" {
+		t.Error("expected a user code")
+	}
+	if idToken == "" {
+		t.Error("expected an ID token")
+	}
+}
+
+func TestOIDCLoginInvalidClient(t *testing.T) {
+	issuer, err := oidc.NewFakeIssuer("teresa", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("error starting fake issuer: ", err)
+	}
+	defer issuer.Close()
+
+	o := NewOIDCLogin(issuer.URL, "other", []string{"openid"})
+	if _, err := o.Device(func(string, string) {}); err == nil {
+		t.Error("expected an error for an invalid client")
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// TokenExpired reports whether the login token expires in less than margin.
+// The signature is not verified, that is up to the server, and tokens that
+// aren't JWTs, like API tokens, never expire.
+func TokenExpired(token string, margin time.Duration) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	claims := struct {
+		ExpiresAt int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return false
+	}
+	return time.Now().Add(margin).After(time.Unix(claims.ExpiresAt, 0))
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func fakeJWT(exp time.Time) string {
+	payload := fmt.Sprintf(`{"email":"gopher@luizalabs.com","exp":%d}`, exp.Unix())
+	return "header." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestTokenExpired(t *testing.T) {
+	var testCases = []struct {
+		token    string
+		expected bool
+	}{
+		{fakeJWT(time.Now().Add(time.Hour)), false},
+		{fakeJWT(time.Now().Add(30 * time.Second)), true},
+		{fakeJWT(time.Now().Add(-time.Hour)), true},
+		{"trs_0123456789abcdef", false},
+		{"invalid.token.value", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := TokenExpired(tc.token, time.Minute); actual != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.token, actual)
+		}
+	}
+}
@@ -17,6 +17,11 @@ It has these top-level messages:
 	SetEnvRequest
 	UnsetEnvRequest
 	Empty
+	EventsRequest
+	EventsResponse
+	MetricsRequest
+	MetricsResponse
+	ClearBuildCacheRequest
 */
 package app
 
@@ -293,7 +298,8 @@ func (m *InfoResponse) GetLimits() *InfoResponse_Limits {
 }
 
 type InfoResponse_Address struct {
-	Hostname string `protobuf:"bytes,1,opt,name=hostname" json:"hostname,omitempty"`
+	Hostname string                       `protobuf:"bytes,1,opt,name=hostname" json:"hostname,omitempty"`
+	Ports    []*InfoResponse_Address_Port `protobuf:"bytes,2,rep,name=ports" json:"ports,omitempty"`
 }
 
 func (m *InfoResponse_Address) Reset()                    { *m = InfoResponse_Address{} }
@@ -308,6 +314,47 @@ func (m *InfoResponse_Address) GetHostname() string {
 	return ""
 }
 
+func (m *InfoResponse_Address) GetPorts() []*InfoResponse_Address_Port {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+type InfoResponse_Address_Port struct {
+	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Port     int32  `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+	Protocol string `protobuf:"bytes,3,opt,name=protocol" json:"protocol,omitempty"`
+}
+
+func (m *InfoResponse_Address_Port) Reset()         { *m = InfoResponse_Address_Port{} }
+func (m *InfoResponse_Address_Port) String() string { return proto.CompactTextString(m) }
+func (*InfoResponse_Address_Port) ProtoMessage()    {}
+func (*InfoResponse_Address_Port) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{4, 0, 0}
+}
+
+func (m *InfoResponse_Address_Port) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InfoResponse_Address_Port) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *InfoResponse_Address_Port) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
 type InfoResponse_EnvVar struct {
 	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
@@ -544,6 +591,244 @@ func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
 func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
+type EventsRequest struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Follow bool   `protobuf:"varint,2,opt,name=follow" json:"follow,omitempty"`
+}
+
+func (m *EventsRequest) Reset()                    { *m = EventsRequest{} }
+func (m *EventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*EventsRequest) ProtoMessage()               {}
+func (*EventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *EventsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+type EventsResponse struct {
+	Type     string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Reason   string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	Object   string `protobuf:"bytes,3,opt,name=object" json:"object,omitempty"`
+	Message  string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+	Count    int32  `protobuf:"varint,5,opt,name=count" json:"count,omitempty"`
+	LastSeen string `protobuf:"bytes,6,opt,name=last_seen,json=lastSeen" json:"last_seen,omitempty"`
+}
+
+func (m *EventsResponse) Reset()                    { *m = EventsResponse{} }
+func (m *EventsResponse) String() string            { return proto.CompactTextString(m) }
+func (*EventsResponse) ProtoMessage()               {}
+func (*EventsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *EventsResponse) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *EventsResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *EventsResponse) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *EventsResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *EventsResponse) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *EventsResponse) GetLastSeen() string {
+	if m != nil {
+		return m.LastSeen
+	}
+	return ""
+}
+
+type MetricsRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *MetricsRequest) Reset()                    { *m = MetricsRequest{} }
+func (m *MetricsRequest) String() string            { return proto.CompactTextString(m) }
+func (*MetricsRequest) ProtoMessage()               {}
+func (*MetricsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *MetricsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type MetricsResponse struct {
+	Pods   []*MetricsResponse_Pod  `protobuf:"bytes,1,rep,name=pods" json:"pods,omitempty"`
+	Limits *MetricsResponse_Limits `protobuf:"bytes,2,opt,name=limits" json:"limits,omitempty"`
+}
+
+func (m *MetricsResponse) Reset()                    { *m = MetricsResponse{} }
+func (m *MetricsResponse) String() string            { return proto.CompactTextString(m) }
+func (*MetricsResponse) ProtoMessage()               {}
+func (*MetricsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *MetricsResponse) GetPods() []*MetricsResponse_Pod {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *MetricsResponse) GetLimits() *MetricsResponse_Limits {
+	if m != nil {
+		return m.Limits
+	}
+	return nil
+}
+
+type MetricsResponse_Pod struct {
+	Name                  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Cpu                   string `protobuf:"bytes,2,opt,name=cpu" json:"cpu,omitempty"`
+	Memory                string `protobuf:"bytes,3,opt,name=memory" json:"memory,omitempty"`
+	CpuLimitPercentage    int32  `protobuf:"varint,4,opt,name=cpu_limit_percentage,json=cpuLimitPercentage" json:"cpu_limit_percentage,omitempty"`
+	MemoryLimitPercentage int32  `protobuf:"varint,5,opt,name=memory_limit_percentage,json=memoryLimitPercentage" json:"memory_limit_percentage,omitempty"`
+}
+
+func (m *MetricsResponse_Pod) Reset()                    { *m = MetricsResponse_Pod{} }
+func (m *MetricsResponse_Pod) String() string            { return proto.CompactTextString(m) }
+func (*MetricsResponse_Pod) ProtoMessage()               {}
+func (*MetricsResponse_Pod) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11, 0} }
+
+func (m *MetricsResponse_Pod) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MetricsResponse_Pod) GetCpu() string {
+	if m != nil {
+		return m.Cpu
+	}
+	return ""
+}
+
+func (m *MetricsResponse_Pod) GetMemory() string {
+	if m != nil {
+		return m.Memory
+	}
+	return ""
+}
+
+func (m *MetricsResponse_Pod) GetCpuLimitPercentage() int32 {
+	if m != nil {
+		return m.CpuLimitPercentage
+	}
+	return 0
+}
+
+func (m *MetricsResponse_Pod) GetMemoryLimitPercentage() int32 {
+	if m != nil {
+		return m.MemoryLimitPercentage
+	}
+	return 0
+}
+
+type MetricsResponse_Limits struct {
+	Default        []*MetricsResponse_Limits_LimitRangeQuantity `protobuf:"bytes,1,rep,name=default" json:"default,omitempty"`
+	DefaultRequest []*MetricsResponse_Limits_LimitRangeQuantity `protobuf:"bytes,2,rep,name=default_request,json=defaultRequest" json:"default_request,omitempty"`
+}
+
+func (m *MetricsResponse_Limits) Reset()                    { *m = MetricsResponse_Limits{} }
+func (m *MetricsResponse_Limits) String() string            { return proto.CompactTextString(m) }
+func (*MetricsResponse_Limits) ProtoMessage()               {}
+func (*MetricsResponse_Limits) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11, 1} }
+
+func (m *MetricsResponse_Limits) GetDefault() []*MetricsResponse_Limits_LimitRangeQuantity {
+	if m != nil {
+		return m.Default
+	}
+	return nil
+}
+
+func (m *MetricsResponse_Limits) GetDefaultRequest() []*MetricsResponse_Limits_LimitRangeQuantity {
+	if m != nil {
+		return m.DefaultRequest
+	}
+	return nil
+}
+
+type MetricsResponse_Limits_LimitRangeQuantity struct {
+	Quantity string `protobuf:"bytes,1,opt,name=quantity" json:"quantity,omitempty"`
+	Resource string `protobuf:"bytes,2,opt,name=resource" json:"resource,omitempty"`
+}
+
+func (m *MetricsResponse_Limits_LimitRangeQuantity) Reset() {
+	*m = MetricsResponse_Limits_LimitRangeQuantity{}
+}
+func (m *MetricsResponse_Limits_LimitRangeQuantity) String() string {
+	return proto.CompactTextString(m)
+}
+func (*MetricsResponse_Limits_LimitRangeQuantity) ProtoMessage() {}
+func (*MetricsResponse_Limits_LimitRangeQuantity) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{11, 1, 0}
+}
+
+func (m *MetricsResponse_Limits_LimitRangeQuantity) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *MetricsResponse_Limits_LimitRangeQuantity) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+type ClearBuildCacheRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *ClearBuildCacheRequest) Reset()                    { *m = ClearBuildCacheRequest{} }
+func (m *ClearBuildCacheRequest) String() string            { return proto.CompactTextString(m) }
+func (*ClearBuildCacheRequest) ProtoMessage()               {}
+func (*ClearBuildCacheRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *ClearBuildCacheRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CreateRequest)(nil), "app.CreateRequest")
 	proto.RegisterType((*CreateRequest_Limits)(nil), "app.CreateRequest.Limits")
@@ -554,6 +839,7 @@ func init() {
 	proto.RegisterType((*InfoRequest)(nil), "app.InfoRequest")
 	proto.RegisterType((*InfoResponse)(nil), "app.InfoResponse")
 	proto.RegisterType((*InfoResponse_Address)(nil), "app.InfoResponse.Address")
+	proto.RegisterType((*InfoResponse_Address_Port)(nil), "app.InfoResponse.Address.Port")
 	proto.RegisterType((*InfoResponse_EnvVar)(nil), "app.InfoResponse.EnvVar")
 	proto.RegisterType((*InfoResponse_Status)(nil), "app.InfoResponse.Status")
 	proto.RegisterType((*InfoResponse_Status_Pod)(nil), "app.InfoResponse.Status.Pod")
@@ -564,6 +850,14 @@ func init() {
 	proto.RegisterType((*SetEnvRequest_EnvVar)(nil), "app.SetEnvRequest.EnvVar")
 	proto.RegisterType((*UnsetEnvRequest)(nil), "app.UnsetEnvRequest")
 	proto.RegisterType((*Empty)(nil), "app.Empty")
+	proto.RegisterType((*EventsRequest)(nil), "app.EventsRequest")
+	proto.RegisterType((*EventsResponse)(nil), "app.EventsResponse")
+	proto.RegisterType((*MetricsRequest)(nil), "app.MetricsRequest")
+	proto.RegisterType((*MetricsResponse)(nil), "app.MetricsResponse")
+	proto.RegisterType((*MetricsResponse_Pod)(nil), "app.MetricsResponse.Pod")
+	proto.RegisterType((*MetricsResponse_Limits)(nil), "app.MetricsResponse.Limits")
+	proto.RegisterType((*MetricsResponse_Limits_LimitRangeQuantity)(nil), "app.MetricsResponse.Limits.LimitRangeQuantity")
+	proto.RegisterType((*ClearBuildCacheRequest)(nil), "app.ClearBuildCacheRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -582,6 +876,9 @@ type AppClient interface {
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
 	SetEnv(ctx context.Context, in *SetEnvRequest, opts ...grpc.CallOption) (*Empty, error)
 	UnsetEnv(ctx context.Context, in *UnsetEnvRequest, opts ...grpc.CallOption) (*Empty, error)
+	Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (App_EventsClient, error)
+	Metrics(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*MetricsResponse, error)
+	ClearBuildCache(ctx context.Context, in *ClearBuildCacheRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type appClient struct {
@@ -660,6 +957,56 @@ func (c *appClient) UnsetEnv(ctx context.Context, in *UnsetEnvRequest, opts ...g
 	return out, nil
 }
 
+func (c *appClient) Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (App_EventsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_App_serviceDesc.Streams[1], c.cc, "/app.App/Events", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &appEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type App_EventsClient interface {
+	Recv() (*EventsResponse, error)
+	grpc.ClientStream
+}
+
+type appEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *appEventsClient) Recv() (*EventsResponse, error) {
+	m := new(EventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *appClient) Metrics(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*MetricsResponse, error) {
+	out := new(MetricsResponse)
+	err := grpc.Invoke(ctx, "/app.App/Metrics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appClient) ClearBuildCache(ctx context.Context, in *ClearBuildCacheRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/app.App/ClearBuildCache", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for App service
 
 type AppServer interface {
@@ -668,6 +1015,9 @@ type AppServer interface {
 	Info(context.Context, *InfoRequest) (*InfoResponse, error)
 	SetEnv(context.Context, *SetEnvRequest) (*Empty, error)
 	UnsetEnv(context.Context, *UnsetEnvRequest) (*Empty, error)
+	Events(*EventsRequest, App_EventsServer) error
+	Metrics(context.Context, *MetricsRequest) (*MetricsResponse, error)
+	ClearBuildCache(context.Context, *ClearBuildCacheRequest) (*Empty, error)
 }
 
 func RegisterAppServer(s *grpc.Server, srv AppServer) {
@@ -767,6 +1117,63 @@ func _App_UnsetEnv_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _App_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppServer).Events(m, &appEventsServer{stream})
+}
+
+type App_EventsServer interface {
+	Send(*EventsResponse) error
+	grpc.ServerStream
+}
+
+type appEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *appEventsServer) Send(m *EventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _App_Metrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppServer).Metrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/app.App/Metrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppServer).Metrics(ctx, req.(*MetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _App_ClearBuildCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearBuildCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppServer).ClearBuildCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/app.App/ClearBuildCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppServer).ClearBuildCache(ctx, req.(*ClearBuildCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _App_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "app.App",
 	HandlerType: (*AppServer)(nil),
@@ -787,6 +1194,14 @@ var _App_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UnsetEnv",
 			Handler:    _App_UnsetEnv_Handler,
 		},
+		{
+			MethodName: "Metrics",
+			Handler:    _App_Metrics_Handler,
+		},
+		{
+			MethodName: "ClearBuildCache",
+			Handler:    _App_ClearBuildCache_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -794,6 +1209,11 @@ var _App_serviceDesc = grpc.ServiceDesc{
 			Handler:       _App_Logs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Events",
+			Handler:       _App_Events_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/protobuf/app/app.proto",
 }
@@ -801,52 +1221,72 @@ var _App_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/app/app.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 738 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x55, 0xdd, 0x6a, 0xdb, 0x4c,
-	0x10, 0x45, 0x91, 0x2d, 0xdb, 0xe3, 0xe4, 0x4b, 0xbe, 0x25, 0x04, 0x45, 0xcd, 0x45, 0x22, 0x28,
-	0x18, 0x92, 0x3a, 0x6e, 0x12, 0x28, 0xb4, 0x37, 0x0d, 0xc5, 0x85, 0x42, 0xa0, 0xa9, 0x92, 0xf4,
-	0xd6, 0x6c, 0xec, 0xb5, 0x2b, 0x22, 0x6b, 0x37, 0xda, 0x95, 0x1b, 0x17, 0xfa, 0x04, 0x7d, 0x92,
-	0x3e, 0x57, 0xe9, 0x13, 0x94, 0xde, 0x97, 0xfd, 0x91, 0x2c, 0x3b, 0xb6, 0x4b, 0x0a, 0xed, 0x85,
-	0xd1, 0xcc, 0xec, 0x99, 0x99, 0xdd, 0xd9, 0xb3, 0xc7, 0xe0, 0xb1, 0x9b, 0xc1, 0x21, 0x4b, 0xa8,
-	0xa0, 0xd7, 0x69, 0xff, 0x10, 0x33, 0x26, 0x7f, 0x4d, 0x15, 0x40, 0x36, 0x66, 0xcc, 0xff, 0x5a,
-	0x82, 0xb5, 0x57, 0x09, 0xc1, 0x82, 0x04, 0xe4, 0x36, 0x25, 0x5c, 0x20, 0x04, 0xa5, 0x18, 0x0f,
-	0x89, 0x6b, 0xed, 0x5a, 0x8d, 0x5a, 0xa0, 0x6c, 0x19, 0x13, 0x04, 0x0f, 0xdd, 0x15, 0x1d, 0x93,
-	0x36, 0xda, 0x83, 0x55, 0x96, 0xd0, 0x2e, 0xe1, 0xbc, 0x23, 0xc6, 0x8c, 0xb8, 0xb6, 0x5a, 0xab,
-	0x9b, 0xd8, 0xe5, 0x98, 0x11, 0xf4, 0x14, 0x9c, 0x28, 0x1c, 0x86, 0x82, 0xbb, 0xa5, 0x5d, 0xab,
-	0x51, 0x3f, 0xda, 0x6e, 0xca, 0xee, 0x53, 0xed, 0x9a, 0x67, 0x0a, 0x10, 0x18, 0x20, 0x7a, 0x01,
-	0x80, 0x53, 0x41, 0x3b, 0xbc, 0x8b, 0x23, 0xe2, 0x96, 0x55, 0xda, 0xce, 0x9c, 0xb4, 0xd3, 0x54,
-	0xd0, 0x0b, 0x89, 0x09, 0x6a, 0x38, 0x33, 0xbd, 0x9f, 0x16, 0x38, 0xba, 0x1e, 0x7a, 0x0d, 0x95,
-	0x1e, 0xe9, 0xe3, 0x34, 0x12, 0xae, 0xb5, 0x6b, 0x37, 0xea, 0x47, 0x07, 0x0b, 0x7b, 0xeb, 0x4f,
-	0x80, 0xe3, 0x01, 0x79, 0x97, 0xe2, 0x58, 0x84, 0x62, 0x1c, 0x64, 0xc9, 0xe8, 0x0a, 0xd6, 0x8d,
-	0xd9, 0x49, 0x74, 0x96, 0xbb, 0xf2, 0x07, 0xf5, 0xfe, 0x33, 0x45, 0x0c, 0xd2, 0x3b, 0x03, 0x74,
-	0x1f, 0x85, 0x3c, 0xa8, 0xde, 0x1a, 0xdb, 0x8c, 0x3f, 0xf7, 0xe5, 0x5a, 0x42, 0x38, 0x4d, 0x93,
-	0x2e, 0x31, 0xd7, 0x90, 0xfb, 0x1e, 0x81, 0x5a, 0x3e, 0x0f, 0x74, 0x02, 0x5b, 0x5d, 0x96, 0x76,
-	0x04, 0x4e, 0x06, 0x44, 0x74, 0x52, 0x11, 0x46, 0xe1, 0x27, 0x2c, 0x42, 0x1a, 0xab, 0x92, 0xe5,
-	0x60, 0xb3, 0xcb, 0xd2, 0x4b, 0xb5, 0x78, 0x35, 0x59, 0x43, 0x1b, 0x60, 0x0f, 0xf1, 0x9d, 0xaa,
-	0x5c, 0x0e, 0xa4, 0xa9, 0x22, 0x61, 0xac, 0xae, 0x55, 0x46, 0xc2, 0xd8, 0x7f, 0x0b, 0xf5, 0x33,
-	0x3a, 0xe0, 0xcb, 0x88, 0xb2, 0x09, 0xe5, 0x28, 0x8c, 0x09, 0x57, 0x85, 0xec, 0x40, 0x3b, 0x68,
-	0x0b, 0x9c, 0x3e, 0x8d, 0x22, 0xfa, 0x51, 0x55, 0xab, 0x06, 0xc6, 0xf3, 0x7d, 0x58, 0xd5, 0x05,
-	0x39, 0xa3, 0x31, 0x37, 0x34, 0xbb, 0x13, 0x59, 0x45, 0x69, 0xfb, 0x7b, 0x50, 0x7f, 0x13, 0xf7,
-	0xe9, 0x92, 0xa6, 0xfe, 0x77, 0x07, 0x56, 0x35, 0xa6, 0x58, 0x07, 0x0f, 0x27, 0x75, 0xf0, 0x10,
-	0x3d, 0x83, 0x1a, 0xee, 0xf5, 0x12, 0xc2, 0xb9, 0xda, 0x9d, 0x9d, 0xd3, 0xb1, 0x98, 0xd9, 0x3c,
-	0xd5, 0x90, 0x60, 0x82, 0x45, 0xc7, 0x50, 0x25, 0xf1, 0xa8, 0x33, 0xc2, 0x09, 0x77, 0x6d, 0x95,
-	0xe7, 0xde, 0xcf, 0x6b, 0xc7, 0xa3, 0xf7, 0x38, 0x09, 0x2a, 0x44, 0x7d, 0x39, 0x6a, 0x81, 0xc3,
-	0x05, 0x16, 0x69, 0xc6, 0xfc, 0x39, 0x29, 0x17, 0x6a, 0x3d, 0x30, 0x38, 0xf4, 0x7c, 0x0e, 0xf1,
-	0x1f, 0xcd, 0xd9, 0xe0, 0x1c, 0xde, 0xcb, 0x6e, 0xe6, 0x9d, 0x39, 0x8b, 0xba, 0x4d, 0x3f, 0x33,
-	0xef, 0x31, 0x54, 0xcc, 0x51, 0x25, 0xb1, 0x3e, 0x50, 0x2e, 0x0a, 0x53, 0xcd, 0x7d, 0xaf, 0x05,
-	0x8e, 0x3e, 0x99, 0x64, 0xc3, 0x0d, 0xc9, 0x58, 0x29, 0x4d, 0x79, 0xd5, 0x23, 0x1c, 0xa5, 0x19,
-	0x1b, 0xb5, 0xe3, 0x7d, 0x06, 0x47, 0x1f, 0x4c, 0x66, 0x74, 0x59, 0x6a, 0x48, 0x27, 0x4d, 0xd4,
-	0x82, 0x12, 0xa3, 0xbd, 0x6c, 0x8a, 0x3b, 0x8b, 0x46, 0xd2, 0x3c, 0xa7, 0xbd, 0x40, 0x21, 0xbd,
-	0x43, 0xb0, 0xcf, 0x69, 0x6f, 0x11, 0xd3, 0xe4, 0xe4, 0xf2, 0xf6, 0xca, 0xf9, 0x47, 0x2f, 0xc1,
-	0xfb, 0x31, 0x11, 0x9a, 0xf6, 0xac, 0xd0, 0xec, 0x2f, 0x1a, 0xfe, 0x52, 0x9d, 0xb9, 0x5c, 0xa4,
-	0x33, 0x0f, 0x2a, 0xf7, 0x57, 0x65, 0xc6, 0xff, 0x62, 0xc1, 0xda, 0x05, 0x11, 0xed, 0x78, 0xb4,
-	0x4c, 0x02, 0x4e, 0x0a, 0xef, 0xa5, 0xf8, 0xce, 0xa6, 0x32, 0x67, 0x1f, 0xcc, 0xc3, 0x99, 0xe6,
-	0xbf, 0x84, 0xf5, 0xab, 0x98, 0xff, 0x76, 0x3b, 0xdb, 0x33, 0xdb, 0xa9, 0xe5, 0x3d, 0xfd, 0x0a,
-	0x94, 0xdb, 0x43, 0x26, 0xc6, 0x47, 0xdf, 0x2c, 0xb0, 0x4f, 0x19, 0x43, 0x0d, 0x70, 0xb4, 0xa0,
-	0x23, 0x74, 0x5f, 0xdd, 0x3d, 0x50, 0x31, 0x95, 0x81, 0x9e, 0x40, 0x49, 0x2a, 0x17, 0xda, 0x50,
-	0xb1, 0x82, 0x2a, 0x7a, 0xff, 0x17, 0x22, 0xfa, 0xbe, 0x5a, 0x16, 0xda, 0x87, 0x92, 0xbc, 0x41,
-	0x03, 0x2f, 0xe8, 0x99, 0x81, 0x4f, 0xa9, 0x57, 0x03, 0x1c, 0x3d, 0x2b, 0xb3, 0x8b, 0xa9, 0xc1,
-	0x4d, 0xed, 0xe2, 0x00, 0xaa, 0xd9, 0x08, 0xd0, 0xa6, 0x8a, 0xcf, 0x4c, 0xa4, 0x88, 0xbe, 0x76,
-	0xd4, 0xdf, 0xfe, 0xf1, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xc9, 0xba, 0x38, 0x75, 0x14, 0x08,
-	0x00, 0x00,
+	// 1059 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x56, 0x4f, 0x6f, 0xdc, 0x44,
+	0x14, 0x97, 0xe3, 0x5d, 0xef, 0xee, 0x4b, 0x93, 0xb4, 0xd3, 0x10, 0x5c, 0xb7, 0x42, 0xa9, 0xc5,
+	0x21, 0x52, 0xc3, 0x26, 0x24, 0x11, 0x48, 0xed, 0x85, 0x10, 0x05, 0x01, 0x0a, 0x22, 0x38, 0x09,
+	0x1c, 0x57, 0x13, 0xef, 0x24, 0x35, 0xb5, 0x3d, 0x13, 0xcf, 0x78, 0xe9, 0x22, 0xf1, 0x05, 0xe0,
+	0x4b, 0xc0, 0x09, 0x71, 0xe0, 0x7b, 0x71, 0x47, 0x1c, 0xb8, 0xa1, 0xf9, 0x63, 0xaf, 0xed, 0xac,
+	0x17, 0x05, 0xa9, 0x1c, 0xa2, 0x7d, 0xef, 0xcd, 0xfb, 0x33, 0x7e, 0xef, 0xfd, 0x7e, 0x19, 0xf0,
+	0xd8, 0xab, 0xeb, 0x1d, 0x96, 0x51, 0x41, 0x2f, 0xf3, 0xab, 0x1d, 0xcc, 0x98, 0xfc, 0x1b, 0x2a,
+	0x03, 0xb2, 0x31, 0x63, 0xfe, 0x6f, 0x1d, 0x58, 0x39, 0xca, 0x08, 0x16, 0x24, 0x20, 0x37, 0x39,
+	0xe1, 0x02, 0x21, 0xe8, 0xa4, 0x38, 0x21, 0xae, 0xb5, 0x69, 0x6d, 0x0d, 0x02, 0x25, 0x4b, 0x9b,
+	0x20, 0x38, 0x71, 0x97, 0xb4, 0x4d, 0xca, 0xe8, 0x29, 0xdc, 0x63, 0x19, 0x0d, 0x09, 0xe7, 0x23,
+	0x31, 0x65, 0xc4, 0xb5, 0xd5, 0xd9, 0xb2, 0xb1, 0x9d, 0x4f, 0x19, 0x41, 0xef, 0x83, 0x13, 0x47,
+	0x49, 0x24, 0xb8, 0xdb, 0xd9, 0xb4, 0xb6, 0x96, 0xf7, 0x1e, 0x0d, 0x65, 0xf5, 0x5a, 0xb9, 0xe1,
+	0x89, 0x72, 0x08, 0x8c, 0x23, 0x7a, 0x01, 0x80, 0x73, 0x41, 0x47, 0x3c, 0xc4, 0x31, 0x71, 0xbb,
+	0x2a, 0xec, 0xc9, 0x9c, 0xb0, 0xc3, 0x5c, 0xd0, 0x33, 0xe9, 0x13, 0x0c, 0x70, 0x21, 0x7a, 0x7f,
+	0x59, 0xe0, 0xe8, 0x7c, 0xe8, 0x13, 0xe8, 0x8d, 0xc9, 0x15, 0xce, 0x63, 0xe1, 0x5a, 0x9b, 0xf6,
+	0xd6, 0xf2, 0xde, 0x76, 0x6b, 0x6d, 0xfd, 0x13, 0xe0, 0xf4, 0x9a, 0x7c, 0x95, 0xe3, 0x54, 0x44,
+	0x62, 0x1a, 0x14, 0xc1, 0xe8, 0x02, 0xd6, 0x8c, 0x38, 0xca, 0x74, 0x94, 0xbb, 0xf4, 0x1f, 0xf2,
+	0xad, 0x9a, 0x24, 0xc6, 0xd3, 0x3b, 0x01, 0x74, 0xdb, 0x0b, 0x79, 0xd0, 0xbf, 0x31, 0xb2, 0x69,
+	0x7f, 0xff, 0xa6, 0x72, 0x96, 0x11, 0x4e, 0xf3, 0x2c, 0x24, 0x66, 0x0c, 0xa5, 0xee, 0x11, 0x18,
+	0x94, 0xfd, 0x40, 0x07, 0xb0, 0x11, 0xb2, 0x7c, 0x24, 0x70, 0x76, 0x4d, 0xc4, 0x28, 0x17, 0x51,
+	0x1c, 0x7d, 0x8f, 0x45, 0x44, 0x53, 0x95, 0xb2, 0x1b, 0xac, 0x87, 0x2c, 0x3f, 0x57, 0x87, 0x17,
+	0xb3, 0x33, 0x74, 0x1f, 0xec, 0x04, 0xbf, 0x56, 0x99, 0xbb, 0x81, 0x14, 0x95, 0x25, 0x4a, 0x5d,
+	0xdb, 0x58, 0xa2, 0xd4, 0xff, 0x12, 0x96, 0x4f, 0xe8, 0x35, 0x5f, 0xb4, 0x28, 0xeb, 0xd0, 0x8d,
+	0xa3, 0x94, 0x70, 0x95, 0xc8, 0x0e, 0xb4, 0x82, 0x36, 0xc0, 0xb9, 0xa2, 0x71, 0x4c, 0xbf, 0x53,
+	0xd9, 0xfa, 0x81, 0xd1, 0x7c, 0x1f, 0xee, 0xe9, 0x84, 0x9c, 0xd1, 0x94, 0x9b, 0x35, 0x7b, 0x2d,
+	0x8a, 0x8c, 0x52, 0xf6, 0x9f, 0xc2, 0xf2, 0x67, 0xe9, 0x15, 0x5d, 0x50, 0xd4, 0xff, 0xa3, 0x07,
+	0xf7, 0xb4, 0x4f, 0x35, 0x0f, 0x4e, 0x66, 0x79, 0x70, 0x82, 0x3e, 0x84, 0x01, 0x1e, 0x8f, 0x33,
+	0xc2, 0x39, 0xe1, 0x66, 0x84, 0x7a, 0x1d, 0xab, 0x91, 0xc3, 0x43, 0xed, 0x12, 0xcc, 0x7c, 0xd1,
+	0x3e, 0xf4, 0x49, 0x3a, 0x19, 0x4d, 0x70, 0xc6, 0x5d, 0x5b, 0xc5, 0xb9, 0xb7, 0xe3, 0x8e, 0xd3,
+	0xc9, 0xd7, 0x38, 0x0b, 0x7a, 0x44, 0xfd, 0x72, 0xb4, 0x0b, 0x0e, 0x17, 0x58, 0xe4, 0xc5, 0xe6,
+	0xcf, 0x09, 0x39, 0x53, 0xe7, 0x81, 0xf1, 0x43, 0xcf, 0xe7, 0x2c, 0xfe, 0xe3, 0x39, 0x17, 0x9c,
+	0xb3, 0xf7, 0xb2, 0x9a, 0xc1, 0x99, 0xd3, 0x56, 0xad, 0x0e, 0x33, 0xef, 0x57, 0x0b, 0x7a, 0xe6,
+	0x5b, 0xe5, 0x66, 0xbd, 0xa4, 0x5c, 0x54, 0xda, 0x5a, 0xea, 0xe8, 0x00, 0xba, 0x8c, 0x66, 0xa2,
+	0xe8, 0xd8, 0x3b, 0xad, 0x1d, 0x1b, 0x9e, 0xd2, 0x4c, 0x04, 0xda, 0xd9, 0xfb, 0x1c, 0x3a, 0x52,
+	0x6d, 0xa3, 0x12, 0xe9, 0x64, 0x36, 0x4d, 0xc9, 0xf2, 0x06, 0x8a, 0x92, 0x42, 0x1a, 0x1b, 0x1a,
+	0x29, 0x75, 0x6f, 0x17, 0x1c, 0xdd, 0x5c, 0xb9, 0x90, 0xaf, 0x48, 0x01, 0x0c, 0x29, 0xca, 0x6d,
+	0x9b, 0xe0, 0x38, 0x2f, 0x00, 0xa1, 0x15, 0xef, 0x07, 0x70, 0x74, 0x6f, 0x65, 0x44, 0xc8, 0x72,
+	0xb3, 0xf7, 0x52, 0x44, 0xbb, 0xb2, 0xfa, 0xb8, 0x18, 0xe4, 0x93, 0xb6, 0xa9, 0x0c, 0x4f, 0xe9,
+	0x38, 0x50, 0x9e, 0xde, 0x0e, 0xd8, 0xa7, 0x74, 0xdc, 0xb6, 0xec, 0x72, 0x78, 0x65, 0x79, 0xa5,
+	0xfc, 0x4f, 0x60, 0xf4, 0xfe, 0x9c, 0x71, 0xdd, 0x71, 0x93, 0xeb, 0x9e, 0xb5, 0xcd, 0x7f, 0x21,
+	0xd5, 0x9d, 0xb7, 0x51, 0xdd, 0x9d, 0xd2, 0xbd, 0x51, 0xa6, 0xf3, 0x7f, 0xb2, 0x60, 0xe5, 0x8c,
+	0x88, 0xe3, 0x74, 0xb2, 0x88, 0x85, 0x0e, 0x2a, 0x90, 0xad, 0x42, 0xbd, 0x16, 0xd9, 0xc4, 0xec,
+	0xdd, 0x37, 0xcd, 0xff, 0x08, 0xd6, 0x2e, 0x52, 0xfe, 0xaf, 0xd7, 0x79, 0xd4, 0xb8, 0xce, 0xa0,
+	0xac, 0xe9, 0xf7, 0xa0, 0x7b, 0x9c, 0x30, 0x31, 0xf5, 0x5f, 0xc0, 0xca, 0xf1, 0x84, 0xa4, 0x62,
+	0x21, 0xbb, 0xce, 0x78, 0x74, 0xa9, 0xc6, 0xa3, 0xbf, 0x58, 0xb0, 0x5a, 0x44, 0x57, 0x28, 0x70,
+	0xca, 0xca, 0x70, 0x29, 0xcb, 0xf0, 0x8c, 0x60, 0x4e, 0x53, 0xf3, 0x15, 0x46, 0x93, 0x76, 0x7a,
+	0xf9, 0x2d, 0x09, 0x85, 0x01, 0x9f, 0xd1, 0x90, 0x0b, 0xbd, 0x84, 0x70, 0x8e, 0xaf, 0x89, 0x62,
+	0xb1, 0x41, 0x50, 0xa8, 0xb2, 0x1d, 0x21, 0xcd, 0x53, 0xa1, 0x78, 0xaa, 0x1b, 0x68, 0x05, 0x3d,
+	0x86, 0x41, 0x8c, 0xb9, 0x18, 0x71, 0x42, 0x52, 0xc5, 0x44, 0x83, 0xa0, 0x2f, 0x0d, 0x67, 0x84,
+	0xa4, 0xfe, 0xbb, 0xb0, 0xfa, 0x05, 0x11, 0x59, 0x14, 0x2e, 0xfa, 0x42, 0xff, 0xe7, 0x0e, 0xac,
+	0x95, 0x6e, 0xe6, 0x53, 0xb6, 0x0d, 0x66, 0xad, 0x0a, 0xf9, 0x36, 0x7c, 0x66, 0x78, 0x45, 0xfb,
+	0x25, 0x17, 0x2e, 0x55, 0x38, 0xb4, 0xe9, 0xdf, 0xa0, 0xc3, 0xdf, 0xad, 0x76, 0x94, 0x1b, 0x12,
+	0xd1, 0x2d, 0x93, 0xa2, 0xec, 0x57, 0x42, 0x12, 0x9a, 0x4d, 0x8b, 0x7e, 0x69, 0x0d, 0xed, 0x82,
+	0x84, 0xf3, 0x48, 0xe5, 0x1c, 0x31, 0x92, 0x85, 0x24, 0x15, 0x45, 0xf3, 0xba, 0x01, 0x0a, 0x59,
+	0xae, 0xca, 0x9e, 0x96, 0x27, 0xe8, 0x03, 0x78, 0x5b, 0xc7, 0xde, 0x0e, 0xd2, 0x9d, 0x7d, 0x4b,
+	0x1f, 0x37, 0xe2, 0xbc, 0xbf, 0x67, 0xe0, 0xff, 0xb4, 0x09, 0xfe, 0xe1, 0x82, 0x0f, 0x5e, 0x88,
+	0xff, 0x6f, 0xda, 0xf0, 0x7f, 0xd7, 0x8c, 0x6f, 0x96, 0x02, 0xb6, 0x61, 0xe3, 0x28, 0x26, 0x38,
+	0xfb, 0x38, 0x8f, 0xe2, 0xf1, 0x11, 0x0e, 0x5f, 0x2e, 0x7a, 0xb9, 0xee, 0xfd, 0x68, 0x83, 0x7d,
+	0xc8, 0x18, 0xda, 0x02, 0x47, 0xbf, 0xd5, 0x10, 0xba, 0xfd, 0x70, 0xf3, 0x40, 0xd9, 0x14, 0x12,
+	0xd1, 0x7b, 0xd0, 0x91, 0x8f, 0x12, 0x74, 0x5f, 0xd9, 0x2a, 0x0f, 0x1e, 0xef, 0x41, 0xc5, 0xa2,
+	0x9b, 0xb0, 0x6b, 0xa1, 0x67, 0xd0, 0x91, 0xcc, 0x68, 0xdc, 0x2b, 0x4f, 0x15, 0xef, 0x41, 0xc5,
+	0x62, 0x56, 0x79, 0x0b, 0x1c, 0xcd, 0x41, 0xe6, 0x16, 0x35, 0x42, 0xaa, 0xdd, 0x62, 0x1b, 0xfa,
+	0x05, 0xb5, 0xa0, 0x75, 0x65, 0x6f, 0x30, 0x4d, 0xcd, 0x7b, 0x1f, 0x1c, 0x8d, 0x7f, 0x93, 0xb7,
+	0x46, 0x25, 0xde, 0xc3, 0x9a, 0xad, 0xbc, 0xf9, 0x01, 0xf4, 0xcc, 0x4c, 0xd1, 0xc3, 0xfa, 0x84,
+	0x75, 0xd8, 0xfa, 0xbc, 0xb1, 0xa3, 0xe7, 0xb0, 0xd6, 0x68, 0x3f, 0xd2, 0x10, 0x9b, 0x3f, 0x94,
+	0xea, 0x35, 0x2f, 0x1d, 0xf5, 0x5f, 0x7d, 0xff, 0x9f, 0x01, 0x00, 0x1f, 0x78, 0x81, 0xf9, 0x96,
+	0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,299 @@
+// Code generated by protoc-gen-go.
+// source: pkg/protobuf/audit/audit.proto
+// DO NOT EDIT!
+
+/*
+Package audit is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/audit/audit.proto
+
+It has these top-level messages:
+	ListRequest
+	ListResponse
+*/
+package audit
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListRequest struct {
+	User   string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	Team   string `protobuf:"bytes,2,opt,name=team" json:"team,omitempty"`
+	App    string `protobuf:"bytes,3,opt,name=app" json:"app,omitempty"`
+	Method string `protobuf:"bytes,4,opt,name=method" json:"method,omitempty"`
+	Since  string `protobuf:"bytes,5,opt,name=since" json:"since,omitempty"`
+	Until  string `protobuf:"bytes,6,opt,name=until" json:"until,omitempty"`
+	Limit  int64  `protobuf:"varint,7,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *ListRequest) Reset()                    { *m = ListRequest{} }
+func (m *ListRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()               {}
+func (*ListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ListRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListRequest) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ListRequest) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *ListRequest) GetUntil() string {
+	if m != nil {
+		return m.Until
+	}
+	return ""
+}
+
+func (m *ListRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListResponse struct {
+	Entries []*ListResponse_Entry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ListResponse) GetEntries() []*ListResponse_Entry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type ListResponse_Entry struct {
+	User      string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	Method    string `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	Team      string `protobuf:"bytes,3,opt,name=team" json:"team,omitempty"`
+	App       string `protobuf:"bytes,4,opt,name=app" json:"app,omitempty"`
+	Params    string `protobuf:"bytes,5,opt,name=params" json:"params,omitempty"`
+	Success   bool   `protobuf:"varint,6,opt,name=success" json:"success,omitempty"`
+	Error     string `protobuf:"bytes,7,opt,name=error" json:"error,omitempty"`
+	CreatedAt string `protobuf:"bytes,8,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	Target    string `protobuf:"bytes,9,opt,name=target" json:"target,omitempty"`
+}
+
+func (m *ListResponse_Entry) Reset()                    { *m = ListResponse_Entry{} }
+func (m *ListResponse_Entry) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Entry) ProtoMessage()               {}
+func (*ListResponse_Entry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
+func (m *ListResponse_Entry) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetParams() string {
+	if m != nil {
+		return m.Params
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *ListResponse_Entry) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ListRequest)(nil), "audit.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "audit.ListResponse")
+	proto.RegisterType((*ListResponse_Entry)(nil), "audit.ListResponse.Entry")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Audit service
+
+type AuditClient interface {
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type auditClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuditClient(cc *grpc.ClientConn) AuditClient {
+	return &auditClient{cc}
+}
+
+func (c *auditClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/audit.Audit/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Audit service
+
+type AuditServer interface {
+	List(context.Context, *ListRequest) (*ListResponse, error)
+}
+
+func RegisterAuditServer(s *grpc.Server, srv AuditServer) {
+	s.RegisterService(&_Audit_serviceDesc, srv)
+}
+
+func _Audit_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audit.Audit/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Audit_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "audit.Audit",
+	HandlerType: (*AuditServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Audit_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/audit/audit.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/audit/audit.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 314 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0xcf, 0x4e, 0xc2, 0x40,
+	0x10, 0x87, 0x53, 0xda, 0x02, 0x1d, 0x3c, 0x98, 0xd1, 0x98, 0x95, 0x44, 0x43, 0x38, 0x71, 0x82,
+	0x04, 0x2e, 0x5e, 0x39, 0x78, 0xf3, 0xd4, 0x17, 0x30, 0x4b, 0x19, 0x71, 0x23, 0xfd, 0xe3, 0xee,
+	0xf4, 0xe0, 0xcb, 0x98, 0xf8, 0x56, 0x3e, 0x8e, 0xd9, 0xd9, 0x12, 0x6d, 0xe4, 0xd2, 0xcc, 0xef,
+	0xeb, 0x24, 0x33, 0xdf, 0xb4, 0x70, 0xdf, 0xbc, 0x1d, 0x56, 0x8d, 0xad, 0xb9, 0xde, 0xb5, 0x2f,
+	0x2b, 0xdd, 0xee, 0x0d, 0x87, 0xe7, 0x52, 0x20, 0xa6, 0x12, 0xe6, 0x5f, 0x11, 0x4c, 0x9e, 0x8c,
+	0xe3, 0x9c, 0xde, 0x5b, 0x72, 0x8c, 0x08, 0x49, 0xeb, 0xc8, 0xaa, 0x68, 0x16, 0x2d, 0xb2, 0x5c,
+	0x6a, 0xcf, 0x98, 0x74, 0xa9, 0x06, 0x81, 0xf9, 0x1a, 0x2f, 0x21, 0xd6, 0x4d, 0xa3, 0x62, 0x41,
+	0xbe, 0xc4, 0x1b, 0x18, 0x96, 0xc4, 0xaf, 0xf5, 0x5e, 0x25, 0x02, 0xbb, 0x84, 0xd7, 0x90, 0x3a,
+	0x53, 0x15, 0xa4, 0x52, 0xc1, 0x21, 0x78, 0xda, 0x56, 0x6c, 0x8e, 0x6a, 0x18, 0xa8, 0x04, 0x4f,
+	0x8f, 0xa6, 0x34, 0xac, 0x46, 0xb3, 0x68, 0x11, 0xe7, 0x21, 0xcc, 0x3f, 0x07, 0x70, 0x11, 0x76,
+	0x74, 0x4d, 0x5d, 0x39, 0xc2, 0x0d, 0x8c, 0xa8, 0x62, 0x6b, 0xc8, 0xa9, 0x68, 0x16, 0x2f, 0x26,
+	0xeb, 0xdb, 0x65, 0x50, 0xfb, 0xdb, 0xb5, 0x7c, 0xac, 0xd8, 0x7e, 0xe4, 0xa7, 0xce, 0xe9, 0x77,
+	0x04, 0xa9, 0xa0, 0xb3, 0x8e, 0xbf, 0xdb, 0x0f, 0x7a, 0xdb, 0x9f, 0xdc, 0xe3, 0xff, 0xee, 0x49,
+	0xcf, 0xbd, 0xd1, 0x56, 0x97, 0xae, 0x93, 0xec, 0x12, 0x2a, 0x18, 0xb9, 0xb6, 0x28, 0xc8, 0x39,
+	0xf1, 0x1c, 0xe7, 0xa7, 0xe8, 0x4d, 0xc9, 0xda, 0xda, 0x8a, 0x69, 0x96, 0x87, 0x80, 0x77, 0x00,
+	0x85, 0x25, 0xcd, 0xb4, 0x7f, 0xd6, 0xac, 0xc6, 0xf2, 0x2a, 0xeb, 0xc8, 0x96, 0xfd, 0x18, 0xd6,
+	0xf6, 0x40, 0xac, 0xb2, 0x30, 0x26, 0xa4, 0xf5, 0x03, 0xa4, 0x5b, 0xef, 0x8f, 0x2b, 0x48, 0xfc,
+	0x09, 0x10, 0x7b, 0xf7, 0x90, 0x2f, 0x3b, 0xbd, 0x3a, 0x73, 0xa3, 0xdd, 0x50, 0x7e, 0x86, 0xcd,
+	0xcf, 0x00, 0xc9, 0x24, 0x8d, 0x1b, 0x2e, 0x02, 0x00, 0x00,
+}
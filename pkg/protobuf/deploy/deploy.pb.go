@@ -11,6 +11,7 @@ It is generated from these files:
 It has these top-level messages:
 	DeployRequest
 	DeployResponse
+	PromoteRequest
 */
 package deploy
 
@@ -158,6 +159,8 @@ func _DeployRequest_OneofSizer(msg proto.Message) (n int) {
 type DeployRequest_Info struct {
 	App         string `protobuf:"bytes,1,opt,name=app" json:"app,omitempty"`
 	Description string `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
+	Image       string `protobuf:"bytes,3,opt,name=image" json:"image,omitempty"`
+	NoCache     bool   `protobuf:"varint,4,opt,name=no_cache,json=noCache" json:"no_cache,omitempty"`
 }
 
 func (m *DeployRequest_Info) Reset()                    { *m = DeployRequest_Info{} }
@@ -179,6 +182,20 @@ func (m *DeployRequest_Info) GetDescription() string {
 	return ""
 }
 
+func (m *DeployRequest_Info) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *DeployRequest_Info) GetNoCache() bool {
+	if m != nil {
+		return m.NoCache
+	}
+	return false
+}
+
 type DeployRequest_File struct {
 	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
 }
@@ -211,11 +228,52 @@ func (m *DeployResponse) GetText() string {
 	return ""
 }
 
+type PromoteRequest struct {
+	FromApp     string `protobuf:"bytes,1,opt,name=from_app,json=fromApp" json:"from_app,omitempty"`
+	ToApp       string `protobuf:"bytes,2,opt,name=to_app,json=toApp" json:"to_app,omitempty"`
+	DeployId    string `protobuf:"bytes,3,opt,name=deploy_id,json=deployId" json:"deploy_id,omitempty"`
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *PromoteRequest) Reset()                    { *m = PromoteRequest{} }
+func (m *PromoteRequest) String() string            { return proto.CompactTextString(m) }
+func (*PromoteRequest) ProtoMessage()               {}
+func (*PromoteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *PromoteRequest) GetFromApp() string {
+	if m != nil {
+		return m.FromApp
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetToApp() string {
+	if m != nil {
+		return m.ToApp
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetDeployId() string {
+	if m != nil {
+		return m.DeployId
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*DeployRequest)(nil), "deploy.DeployRequest")
 	proto.RegisterType((*DeployRequest_Info)(nil), "deploy.DeployRequest.Info")
 	proto.RegisterType((*DeployRequest_File)(nil), "deploy.DeployRequest.File")
 	proto.RegisterType((*DeployResponse)(nil), "deploy.DeployResponse")
+	proto.RegisterType((*PromoteRequest)(nil), "deploy.PromoteRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -230,6 +288,7 @@ const _ = grpc.SupportPackageIsVersion4
 
 type DeployClient interface {
 	Make(ctx context.Context, opts ...grpc.CallOption) (Deploy_MakeClient, error)
+	Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (Deploy_PromoteClient, error)
 }
 
 type deployClient struct {
@@ -271,10 +330,43 @@ func (x *deployMakeClient) Recv() (*DeployResponse, error) {
 	return m, nil
 }
 
+func (c *deployClient) Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (Deploy_PromoteClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deploy_serviceDesc.Streams[1], c.cc, "/deploy.Deploy/Promote", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deployPromoteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Deploy_PromoteClient interface {
+	Recv() (*DeployResponse, error)
+	grpc.ClientStream
+}
+
+type deployPromoteClient struct {
+	grpc.ClientStream
+}
+
+func (x *deployPromoteClient) Recv() (*DeployResponse, error) {
+	m := new(DeployResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Deploy service
 
 type DeployServer interface {
 	Make(Deploy_MakeServer) error
+	Promote(*PromoteRequest, Deploy_PromoteServer) error
 }
 
 func RegisterDeployServer(s *grpc.Server, srv DeployServer) {
@@ -307,6 +399,27 @@ func (x *deployMakeServer) Recv() (*DeployRequest, error) {
 	return m, nil
 }
 
+func _Deploy_Promote_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PromoteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeployServer).Promote(m, &deployPromoteServer{stream})
+}
+
+type Deploy_PromoteServer interface {
+	Send(*DeployResponse) error
+	grpc.ServerStream
+}
+
+type deployPromoteServer struct {
+	grpc.ServerStream
+}
+
+func (x *deployPromoteServer) Send(m *DeployResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Deploy_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "deploy.Deploy",
 	HandlerType: (*DeployServer)(nil),
@@ -318,6 +431,11 @@ var _Deploy_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Promote",
+			Handler:       _Deploy_Promote_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/protobuf/deploy/deploy.proto",
 }
@@ -325,21 +443,27 @@ var _Deploy_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/deploy/deploy.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 245 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0x41, 0x4b, 0x03, 0x31,
-	0x10, 0x85, 0x8d, 0xa6, 0x2b, 0x9d, 0xaa, 0xc8, 0xa0, 0x52, 0x16, 0x0f, 0x4b, 0xf1, 0xd0, 0xd3,
-	0xb6, 0xd4, 0x93, 0x1e, 0x55, 0x44, 0x0f, 0x5e, 0xf2, 0x0f, 0xb6, 0xed, 0xac, 0x86, 0x5d, 0x92,
-	0xd8, 0x4d, 0x44, 0xff, 0xa8, 0xbf, 0x47, 0x32, 0x59, 0x41, 0x45, 0x7a, 0xca, 0x9b, 0xc7, 0xfb,
-	0xde, 0x0c, 0x81, 0xc2, 0x35, 0xcf, 0x33, 0xb7, 0xb1, 0xde, 0x2e, 0x43, 0x3d, 0x5b, 0x93, 0x6b,
-	0xed, 0x47, 0xff, 0x94, 0x6c, 0x63, 0x96, 0xa6, 0xc9, 0xa7, 0x80, 0xc3, 0x3b, 0x96, 0x8a, 0x5e,
-	0x03, 0x75, 0x1e, 0xe7, 0x20, 0xb5, 0xa9, 0xed, 0x58, 0x14, 0x62, 0x3a, 0x5a, 0xe4, 0x65, 0x8f,
-	0xfd, 0x0a, 0x95, 0x8f, 0xa6, 0xb6, 0x0f, 0x3b, 0x8a, 0x93, 0x91, 0xa8, 0x75, 0x4b, 0xe3, 0xdd,
-	0x6d, 0xc4, 0xbd, 0x6e, 0x29, 0x12, 0x31, 0x99, 0x5f, 0x83, 0x8c, 0x0d, 0x78, 0x0c, 0x7b, 0x95,
-	0x73, 0xbc, 0x6a, 0xa8, 0xa2, 0xc4, 0x02, 0x46, 0x6b, 0xea, 0x56, 0x1b, 0xed, 0xbc, 0xb6, 0x86,
-	0x2b, 0x87, 0xea, 0xa7, 0x95, 0x9f, 0x83, 0x8c, 0x5d, 0x78, 0x02, 0x83, 0xd5, 0x4b, 0x30, 0x0d,
-	0xd3, 0x07, 0x2a, 0x0d, 0x37, 0xfb, 0x30, 0x78, 0xab, 0xda, 0x40, 0x93, 0x0b, 0x38, 0xfa, 0x3e,
-	0xa0, 0x73, 0xd6, 0x74, 0x84, 0x08, 0xd2, 0xd3, 0xbb, 0xef, 0xb7, 0xb1, 0x5e, 0xdc, 0x42, 0x96,
-	0x52, 0x78, 0x05, 0xf2, 0xa9, 0x6a, 0x08, 0x4f, 0xff, 0x3d, 0x3f, 0x3f, 0xfb, 0x6b, 0xa7, 0xd2,
-	0xa9, 0x98, 0x8b, 0x65, 0xc6, 0x5f, 0x7a, 0xf9, 0x15, 0x00, 0x00, 0xff, 0xff, 0xf0, 0x10, 0x1d,
-	0x55, 0x76, 0x01, 0x00, 0x00,
+	// 347 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x52, 0x4f, 0x4f, 0xfa, 0x40,
+	0x10, 0xfd, 0x2d, 0x94, 0xb6, 0x0c, 0x3f, 0x89, 0xd9, 0x88, 0x29, 0xd5, 0x43, 0x43, 0x3c, 0x70,
+	0x02, 0x82, 0x27, 0xe3, 0xc9, 0x3f, 0x31, 0x72, 0x30, 0x31, 0xfd, 0x02, 0xa4, 0xb4, 0x5b, 0x68,
+	0x5a, 0x3a, 0x6b, 0xbb, 0x35, 0x7a, 0xd3, 0x93, 0x5f, 0xdb, 0xec, 0x6e, 0x51, 0xc1, 0x3f, 0xa7,
+	0xce, 0x7b, 0x3b, 0x6f, 0x66, 0xde, 0x4b, 0xc1, 0xe3, 0xe9, 0x72, 0xcc, 0x0b, 0x14, 0xb8, 0xa8,
+	0xe2, 0x71, 0xc4, 0x78, 0x86, 0xcf, 0xf5, 0x67, 0xa4, 0x68, 0x6a, 0x6a, 0x34, 0x78, 0x6b, 0xc0,
+	0xde, 0xb5, 0x2a, 0x7d, 0xf6, 0x50, 0xb1, 0x52, 0xd0, 0x09, 0x18, 0x49, 0x1e, 0xa3, 0x43, 0x3c,
+	0x32, 0xec, 0x4c, 0xdd, 0x51, 0x2d, 0xdb, 0x6a, 0x1a, 0xcd, 0xf2, 0x18, 0x6f, 0xff, 0xf9, 0xaa,
+	0x53, 0x2a, 0xe2, 0x24, 0x63, 0x4e, 0xe3, 0x2f, 0xc5, 0x4d, 0x92, 0x31, 0xa9, 0x90, 0x9d, 0x6e,
+	0x0a, 0x86, 0x9c, 0x40, 0xf7, 0xa1, 0x19, 0x70, 0xae, 0x56, 0xb5, 0x7d, 0x59, 0x52, 0x0f, 0x3a,
+	0x11, 0x2b, 0xc3, 0x22, 0xe1, 0x22, 0xc1, 0x5c, 0x8d, 0x6c, 0xfb, 0x5f, 0x29, 0x7a, 0x00, 0xad,
+	0x64, 0x1d, 0x2c, 0x99, 0xd3, 0x54, 0x6f, 0x1a, 0xd0, 0x3e, 0xd8, 0x39, 0xce, 0xc3, 0x20, 0x5c,
+	0x31, 0xc7, 0xf0, 0xc8, 0xd0, 0xf6, 0xad, 0x1c, 0xaf, 0x24, 0x74, 0x8f, 0xc1, 0x90, 0xcb, 0xa5,
+	0x30, 0x5c, 0x55, 0x79, 0xaa, 0xd6, 0xfd, 0xf7, 0x35, 0xb8, 0xb4, 0xa0, 0xf5, 0x18, 0x64, 0x15,
+	0x1b, 0x9c, 0x40, 0x77, 0x73, 0x71, 0xc9, 0x31, 0x2f, 0x19, 0xa5, 0x60, 0x08, 0xf6, 0x24, 0xea,
+	0xf3, 0x54, 0x3d, 0x78, 0x25, 0xd0, 0xbd, 0x2f, 0x70, 0x8d, 0x82, 0x6d, 0x02, 0xeb, 0x83, 0x1d,
+	0x17, 0xb8, 0x9e, 0x7f, 0x3a, 0xb1, 0x24, 0xbe, 0xe0, 0x9c, 0xf6, 0xc0, 0x14, 0xa8, 0x1e, 0xb4,
+	0x91, 0x96, 0x40, 0x49, 0x1f, 0x41, 0x5b, 0x67, 0x34, 0x4f, 0xa2, 0xda, 0x86, 0xad, 0x89, 0x59,
+	0xb4, 0x9b, 0x80, 0xf1, 0x2d, 0x81, 0xe9, 0x0b, 0x01, 0x53, 0x9f, 0x4a, 0xcf, 0xc0, 0xb8, 0x0b,
+	0x52, 0x46, 0x7b, 0x3f, 0x86, 0xee, 0x1e, 0xee, 0xd2, 0xda, 0xd9, 0x90, 0x4c, 0x08, 0x3d, 0x07,
+	0xab, 0x36, 0x42, 0x3f, 0xda, 0xb6, 0x9d, 0xfd, 0x26, 0x9f, 0x90, 0x85, 0xa9, 0xfe, 0xa2, 0xd3,
+	0xf7, 0x01, 0x00, 0x82, 0xf0, 0x1d, 0xcc, 0x69, 0x02, 0x00, 0x00,
 }
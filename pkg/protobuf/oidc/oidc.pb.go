@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go.
+// source: pkg/protobuf/oidc/oidc.proto
+// DO NOT EDIT!
+
+/*
+Package oidc is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/oidc/oidc.proto
+
+It has these top-level messages:
+	ConfigResponse
+	LoginRequest
+	LoginResponse
+	Empty
+*/
+package oidc
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ConfigResponse struct {
+	Issuer   string   `protobuf:"bytes,1,opt,name=issuer" json:"issuer,omitempty"`
+	ClientId string   `protobuf:"bytes,2,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Scopes   []string `protobuf:"bytes,3,rep,name=scopes" json:"scopes,omitempty"`
+}
+
+func (m *ConfigResponse) Reset()                    { *m = ConfigResponse{} }
+func (m *ConfigResponse) String() string            { return proto.CompactTextString(m) }
+func (*ConfigResponse) ProtoMessage()               {}
+func (*ConfigResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ConfigResponse) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *ConfigResponse) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *ConfigResponse) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type LoginRequest struct {
+	IdToken  string `protobuf:"bytes,1,opt,name=id_token,json=idToken" json:"id_token,omitempty"`
+	Nonce    string `protobuf:"bytes,2,opt,name=nonce" json:"nonce,omitempty"`
+	TotpCode string `protobuf:"bytes,3,opt,name=totp_code,json=totpCode" json:"totp_code,omitempty"`
+}
+
+func (m *LoginRequest) Reset()                    { *m = LoginRequest{} }
+func (m *LoginRequest) String() string            { return proto.CompactTextString(m) }
+func (*LoginRequest) ProtoMessage()               {}
+func (*LoginRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *LoginRequest) GetIdToken() string {
+	if m != nil {
+		return m.IdToken
+	}
+	return ""
+}
+
+func (m *LoginRequest) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *LoginRequest) GetTotpCode() string {
+	if m != nil {
+		return m.TotpCode
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	Token        string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	RefreshToken string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
+}
+
+func (m *LoginResponse) Reset()                    { *m = LoginResponse{} }
+func (m *LoginResponse) String() string            { return proto.CompactTextString(m) }
+func (*LoginResponse) ProtoMessage()               {}
+func (*LoginResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *LoginResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func init() {
+	proto.RegisterType((*ConfigResponse)(nil), "oidc.ConfigResponse")
+	proto.RegisterType((*LoginRequest)(nil), "oidc.LoginRequest")
+	proto.RegisterType((*LoginResponse)(nil), "oidc.LoginResponse")
+	proto.RegisterType((*Empty)(nil), "oidc.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for OIDC service
+
+type OIDCClient interface {
+	Config(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigResponse, error)
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+}
+
+type oIDCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOIDCClient(cc *grpc.ClientConn) OIDCClient {
+	return &oIDCClient{cc}
+}
+
+func (c *oIDCClient) Config(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigResponse, error) {
+	out := new(ConfigResponse)
+	err := grpc.Invoke(ctx, "/oidc.OIDC/Config", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oIDCClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/oidc.OIDC/Login", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for OIDC service
+
+type OIDCServer interface {
+	Config(context.Context, *Empty) (*ConfigResponse, error)
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+}
+
+func RegisterOIDCServer(s *grpc.Server, srv OIDCServer) {
+	s.RegisterService(&_OIDC_serviceDesc, srv)
+}
+
+func _OIDC_Config_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OIDCServer).Config(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/oidc.OIDC/Config",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OIDCServer).Config(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OIDC_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OIDCServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/oidc.OIDC/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OIDCServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _OIDC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "oidc.OIDC",
+	HandlerType: (*OIDCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Config",
+			Handler:    _OIDC_Config_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _OIDC_Login_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/oidc/oidc.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/oidc/oidc.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 273 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0xa9, 0x69, 0xd2, 0x76, 0x6c, 0x3d, 0xac, 0x41, 0x62, 0xf5, 0x50, 0xe2, 0x45, 0x10,
+	0x5a, 0xd1, 0x8f, 0x10, 0x3d, 0x54, 0x04, 0x21, 0x78, 0x54, 0x02, 0xdd, 0x9d, 0xc4, 0xa5, 0xba,
+	0xb3, 0x66, 0x37, 0x07, 0xbf, 0xbd, 0xec, 0x9f, 0x43, 0xf5, 0xb2, 0xec, 0xef, 0x0d, 0xf3, 0xde,
+	0xec, 0x2c, 0x5c, 0xea, 0x7d, 0xb7, 0xd1, 0x3d, 0x59, 0xda, 0x0d, 0xed, 0x86, 0xa4, 0xe0, 0xfe,
+	0x58, 0x7b, 0x89, 0x8d, 0xdd, 0xbd, 0x7c, 0x87, 0x93, 0x8a, 0x54, 0x2b, 0xbb, 0x1a, 0x8d, 0x26,
+	0x65, 0x90, 0x9d, 0x41, 0x26, 0x8d, 0x19, 0xb0, 0x2f, 0x46, 0xab, 0xd1, 0xf5, 0xac, 0x8e, 0xc4,
+	0x2e, 0x60, 0xc6, 0x3f, 0x25, 0x2a, 0xdb, 0x48, 0x51, 0x1c, 0xf9, 0xd2, 0x34, 0x08, 0x5b, 0xe1,
+	0x9a, 0x0c, 0x27, 0x8d, 0xa6, 0x48, 0x56, 0x89, 0x6b, 0x0a, 0x54, 0xbe, 0xc1, 0xfc, 0x99, 0x3a,
+	0xa9, 0x6a, 0xfc, 0x1e, 0xd0, 0x58, 0x76, 0x0e, 0x53, 0x29, 0x1a, 0x4b, 0x7b, 0x54, 0xd1, 0x7e,
+	0x22, 0xc5, 0xab, 0x43, 0x96, 0x43, 0xaa, 0x48, 0x71, 0x8c, 0xde, 0x01, 0x5c, 0xaa, 0x25, 0xab,
+	0x1b, 0x4e, 0x02, 0x8b, 0x24, 0xa4, 0x3a, 0xa1, 0x22, 0x81, 0xe5, 0x13, 0x2c, 0xa2, 0x7b, 0x9c,
+	0x3d, 0x87, 0xf4, 0xd0, 0x3b, 0x00, 0xbb, 0x82, 0x45, 0x8f, 0x6d, 0x8f, 0xe6, 0x23, 0x26, 0x87,
+	0x84, 0x79, 0x14, 0x7d, 0x7c, 0x39, 0x81, 0xf4, 0xf1, 0x4b, 0xdb, 0x9f, 0x3b, 0x84, 0xf1, 0xcb,
+	0xf6, 0xa1, 0x62, 0x37, 0x90, 0x85, 0xcd, 0xb0, 0xe3, 0xb5, 0x5f, 0x9b, 0x2f, 0x2f, 0xf3, 0x00,
+	0xff, 0x96, 0x76, 0x0b, 0xa9, 0x9f, 0x84, 0xb1, 0x50, 0x3e, 0x7c, 0xf4, 0xf2, 0xf4, 0x8f, 0x16,
+	0x3a, 0x76, 0x99, 0xff, 0x85, 0xfb, 0xdf, 0x01, 0x00, 0x40, 0xf7, 0x77, 0x55, 0xa5, 0x01, 0x00,
+	0x00,
+}
@@ -11,6 +11,10 @@ It is generated from these files:
 It has these top-level messages:
 	CreateRequest
 	AddUserRequest
+	RemoveUserRequest
+	DeleteRequest
+	UpdateRequest
+	SetRoleRequest
 	ListResponse
 	Empty
 */
@@ -71,6 +75,7 @@ func (m *CreateRequest) GetUrl() string {
 type AddUserRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
 }
 
 func (m *AddUserRequest) Reset()                    { *m = AddUserRequest{} }
@@ -92,6 +97,125 @@ func (m *AddUserRequest) GetUser() string {
 	return ""
 }
 
+func (m *AddUserRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type RemoveUserRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *RemoveUserRequest) Reset()                    { *m = RemoveUserRequest{} }
+func (m *RemoveUserRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveUserRequest) ProtoMessage()               {}
+func (*RemoveUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *RemoveUserRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RemoveUserRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Force bool   `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+}
+
+func (m *DeleteRequest) Reset()                    { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()               {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *DeleteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type UpdateRequest struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Url   string `protobuf:"bytes,3,opt,name=url" json:"url,omitempty"`
+}
+
+func (m *UpdateRequest) Reset()                    { *m = UpdateRequest{} }
+func (m *UpdateRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateRequest) ProtoMessage()               {}
+func (*UpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *UpdateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+type SetRoleRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+}
+
+func (m *SetRoleRequest) Reset()                    { *m = SetRoleRequest{} }
+func (m *SetRoleRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetRoleRequest) ProtoMessage()               {}
+func (*SetRoleRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *SetRoleRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type ListResponse struct {
 	Teams []*ListResponse_Team `protobuf:"bytes,1,rep,name=teams" json:"teams,omitempty"`
 }
@@ -99,7 +223,7 @@ type ListResponse struct {
 func (m *ListResponse) Reset()                    { *m = ListResponse{} }
 func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()               {}
-func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *ListResponse) GetTeams() []*ListResponse_Team {
 	if m != nil {
@@ -111,12 +235,13 @@ func (m *ListResponse) GetTeams() []*ListResponse_Team {
 type ListResponse_User struct {
 	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Role  string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
 }
 
 func (m *ListResponse_User) Reset()                    { *m = ListResponse_User{} }
 func (m *ListResponse_User) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse_User) ProtoMessage()               {}
-func (*ListResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
+func (*ListResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 0} }
 
 func (m *ListResponse_User) GetName() string {
 	if m != nil {
@@ -132,6 +257,13 @@ func (m *ListResponse_User) GetEmail() string {
 	return ""
 }
 
+func (m *ListResponse_User) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type ListResponse_Team struct {
 	Name  string               `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Email string               `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
@@ -142,7 +274,7 @@ type ListResponse_Team struct {
 func (m *ListResponse_Team) Reset()                    { *m = ListResponse_Team{} }
 func (m *ListResponse_Team) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse_Team) ProtoMessage()               {}
-func (*ListResponse_Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 1} }
+func (*ListResponse_Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 1} }
 
 func (m *ListResponse_Team) GetName() string {
 	if m != nil {
@@ -178,11 +310,15 @@ type Empty struct {
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func init() {
 	proto.RegisterType((*CreateRequest)(nil), "team.CreateRequest")
 	proto.RegisterType((*AddUserRequest)(nil), "team.AddUserRequest")
+	proto.RegisterType((*RemoveUserRequest)(nil), "team.RemoveUserRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "team.DeleteRequest")
+	proto.RegisterType((*UpdateRequest)(nil), "team.UpdateRequest")
+	proto.RegisterType((*SetRoleRequest)(nil), "team.SetRoleRequest")
 	proto.RegisterType((*ListResponse)(nil), "team.ListResponse")
 	proto.RegisterType((*ListResponse_User)(nil), "team.ListResponse.User")
 	proto.RegisterType((*ListResponse_Team)(nil), "team.ListResponse.Team")
@@ -203,6 +339,10 @@ type TeamClient interface {
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error)
 	AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*Empty, error)
 	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	RemoveUser(ctx context.Context, in *RemoveUserRequest, opts ...grpc.CallOption) (*Empty, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type teamClient struct {
@@ -240,12 +380,52 @@ func (c *teamClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOptio
 	return out, nil
 }
 
+func (c *teamClient) RemoveUser(ctx context.Context, in *RemoveUserRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/RemoveUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/Update", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/SetRole", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Team service
 
 type TeamServer interface {
 	Create(context.Context, *CreateRequest) (*Empty, error)
 	AddUser(context.Context, *AddUserRequest) (*Empty, error)
 	List(context.Context, *Empty) (*ListResponse, error)
+	RemoveUser(context.Context, *RemoveUserRequest) (*Empty, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+	Update(context.Context, *UpdateRequest) (*Empty, error)
+	SetRole(context.Context, *SetRoleRequest) (*Empty, error)
 }
 
 func RegisterTeamServer(s *grpc.Server, srv TeamServer) {
@@ -306,6 +486,78 @@ func _Team_List_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Team_RemoveUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).RemoveUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/RemoveUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).RemoveUser(ctx, req.(*RemoveUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).SetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/SetRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).SetRole(ctx, req.(*SetRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Team_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "team.Team",
 	HandlerType: (*TeamServer)(nil),
@@ -322,6 +574,22 @@ var _Team_serviceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Team_List_Handler,
 		},
+		{
+			MethodName: "RemoveUser",
+			Handler:    _Team_RemoveUser_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Team_Delete_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Team_Update_Handler,
+		},
+		{
+			MethodName: "SetRole",
+			Handler:    _Team_SetRole_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/protobuf/team/team.proto",
@@ -330,23 +598,29 @@ var _Team_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/team/team.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 276 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xc1, 0x4a, 0xc3, 0x40,
-	0x10, 0x86, 0x59, 0xb3, 0x69, 0x71, 0xaa, 0x22, 0x63, 0xc1, 0x25, 0x78, 0x28, 0xb9, 0x58, 0x44,
-	0x53, 0xa9, 0x17, 0xaf, 0x22, 0x9e, 0xf4, 0x14, 0xf4, 0x01, 0xb6, 0x74, 0x94, 0x62, 0xb7, 0x49,
-	0x77, 0x37, 0x07, 0x1f, 0xc1, 0xd7, 0xf4, 0x49, 0x64, 0x67, 0x73, 0x48, 0x10, 0x45, 0xe8, 0x25,
-	0xfc, 0xf9, 0xf9, 0x77, 0xe6, 0xcb, 0x9f, 0x85, 0xb3, 0xfa, 0xfd, 0x6d, 0x56, 0xdb, 0xca, 0x57,
-	0x8b, 0xe6, 0x75, 0xe6, 0x49, 0x1b, 0x7e, 0x14, 0x6c, 0xa1, 0x0c, 0x3a, 0x7f, 0x84, 0xc3, 0x7b,
-	0x4b, 0xda, 0x53, 0x49, 0xdb, 0x86, 0x9c, 0x47, 0x04, 0xb9, 0xd1, 0x86, 0x94, 0x98, 0x88, 0xe9,
-	0x7e, 0xc9, 0x1a, 0xc7, 0x90, 0x92, 0xd1, 0xab, 0xb5, 0xda, 0x63, 0x33, 0xbe, 0xe0, 0x31, 0x24,
-	0x8d, 0x5d, 0xab, 0x84, 0xbd, 0x20, 0xf3, 0x5b, 0x38, 0xba, 0x5b, 0x2e, 0x5f, 0x1c, 0xd9, 0xbf,
-	0xa6, 0x21, 0xc8, 0xc6, 0x91, 0x6d, 0x87, 0xb1, 0xce, 0xbf, 0x04, 0x1c, 0x3c, 0xad, 0x9c, 0x2f,
-	0xc9, 0xd5, 0xd5, 0xc6, 0x11, 0x5e, 0x41, 0x1a, 0xf8, 0x9c, 0x12, 0x93, 0x64, 0x3a, 0x9a, 0x9f,
-	0x16, 0x4c, 0xde, 0x8d, 0x14, 0xcf, 0xa4, 0x4d, 0x19, 0x53, 0xd9, 0x35, 0xc8, 0xb0, 0xf6, 0xff,
-	0xf4, 0xd9, 0x16, 0x64, 0x18, 0xb0, 0xcb, 0xf7, 0x06, 0xc8, 0x40, 0xef, 0x94, 0xfc, 0x15, 0x92,
-	0xcb, 0x88, 0xa9, 0x7c, 0x08, 0xe9, 0x83, 0xa9, 0xfd, 0xc7, 0xfc, 0x53, 0xb4, 0xcb, 0x2f, 0x60,
-	0x10, 0xdb, 0xc7, 0x93, 0x78, 0xb6, 0xf7, 0x2f, 0xb2, 0x51, 0x34, 0xf9, 0x10, 0x5e, 0xc2, 0xb0,
-	0x2d, 0x17, 0xc7, 0xd1, 0xef, 0x77, 0xdd, 0x4f, 0x9f, 0x83, 0x0c, 0x1c, 0xd8, 0x35, 0x33, 0xfc,
-	0x09, 0xb8, 0x18, 0xf0, 0x6d, 0xb8, 0xf9, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x15, 0xeb, 0x5d, 0x54,
-	0x2d, 0x02, 0x00, 0x00,
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x94, 0xcf, 0x4e, 0xea, 0x40,
+	0x14, 0xc6, 0x53, 0x28, 0x70, 0xef, 0xe1, 0x72, 0x73, 0xef, 0x91, 0xc4, 0xa6, 0x71, 0x41, 0xba,
+	0x91, 0x10, 0x2d, 0x09, 0xae, 0x8c, 0x2b, 0x23, 0xae, 0x64, 0x55, 0xe5, 0x01, 0x8a, 0x1c, 0x0c,
+	0xb1, 0x65, 0xca, 0xcc, 0xd4, 0xc4, 0xe7, 0xf3, 0x9d, 0x5c, 0x9b, 0xf9, 0x43, 0x64, 0x52, 0xff,
+	0x45, 0xd9, 0x90, 0x33, 0x5f, 0xbe, 0xf9, 0x38, 0xe7, 0xcc, 0x2f, 0x85, 0x83, 0xe2, 0xfe, 0x6e,
+	0x58, 0x70, 0x26, 0xd9, 0xac, 0x5c, 0x0c, 0x25, 0xa5, 0xb9, 0xfe, 0x89, 0xb5, 0x84, 0xbe, 0xaa,
+	0xa3, 0x2b, 0xe8, 0x5c, 0x70, 0x4a, 0x25, 0x25, 0xb4, 0x2e, 0x49, 0x48, 0x44, 0xf0, 0x57, 0x69,
+	0x4e, 0x81, 0xd7, 0xf3, 0xfa, 0xbf, 0x13, 0x5d, 0x63, 0x17, 0x1a, 0x94, 0xa7, 0xcb, 0x2c, 0xa8,
+	0x69, 0xd1, 0x1c, 0xf0, 0x1f, 0xd4, 0x4b, 0x9e, 0x05, 0x75, 0xad, 0xa9, 0x32, 0x9a, 0xc0, 0xdf,
+	0xf3, 0xf9, 0x7c, 0x2a, 0x88, 0x7f, 0x94, 0x86, 0xe0, 0x97, 0x82, 0xb8, 0x0d, 0xd3, 0xb5, 0xd2,
+	0x38, 0xcb, 0xc8, 0x86, 0xe9, 0x3a, 0x3a, 0x83, 0xff, 0x09, 0xe5, 0xec, 0x81, 0xbe, 0x11, 0x18,
+	0x9d, 0x42, 0x67, 0x4c, 0x19, 0x7d, 0x3a, 0xd7, 0x82, 0xf1, 0x5b, 0xd2, 0x37, 0x7f, 0x25, 0xe6,
+	0xa0, 0x56, 0x32, 0x2d, 0xe6, 0xbb, 0x5b, 0xc9, 0x35, 0xc9, 0x84, 0x65, 0xb4, 0x8b, 0x95, 0x3c,
+	0x7b, 0xf0, 0x67, 0xb2, 0x14, 0x32, 0x21, 0x51, 0xb0, 0x95, 0x20, 0x3c, 0x86, 0x86, 0x7a, 0x46,
+	0x11, 0x78, 0xbd, 0x7a, 0xbf, 0x3d, 0xda, 0x8f, 0xd5, 0x29, 0xde, 0xb6, 0xc4, 0x37, 0x94, 0xe6,
+	0x89, 0x71, 0x85, 0x63, 0xf0, 0xa7, 0x36, 0xfb, 0x8b, 0x13, 0xbd, 0xd1, 0x45, 0xb8, 0x06, 0x5f,
+	0x85, 0xfe, 0x64, 0x2f, 0xaa, 0x71, 0x35, 0xa5, 0x08, 0xfc, 0x77, 0x1b, 0xd7, 0xcf, 0x6e, 0x5c,
+	0x51, 0x0b, 0x1a, 0x97, 0x79, 0x21, 0x1f, 0x47, 0x4f, 0x35, 0xfb, 0xe7, 0x03, 0x68, 0x1a, 0x70,
+	0x71, 0xcf, 0xdc, 0x75, 0x30, 0x0e, 0xdb, 0x46, 0xd4, 0x97, 0xf0, 0x08, 0x5a, 0x96, 0x4b, 0xec,
+	0x1a, 0xdd, 0xc5, 0xd4, 0x75, 0x1f, 0x82, 0xaf, 0xfa, 0xc0, 0x6d, 0x31, 0xc4, 0x6a, 0x83, 0x38,
+	0x02, 0x78, 0x05, 0x14, 0xed, 0x08, 0x15, 0x64, 0xdd, 0xf0, 0x01, 0x34, 0x0d, 0x97, 0x9b, 0xb6,
+	0x1d, 0x4a, 0x2b, 0x5e, 0x03, 0xe2, 0xc6, 0xeb, 0x60, 0x59, 0x19, 0xd1, 0x72, 0xb6, 0x19, 0xd1,
+	0xc5, 0xce, 0x71, 0xcf, 0x9a, 0xfa, 0x13, 0x70, 0xf2, 0x32, 0x00, 0xb6, 0xc8, 0x61, 0xe2, 0x22,
+	0x04, 0x00, 0x00,
 }
@@ -0,0 +1,398 @@
+// Code generated by protoc-gen-go.
+// source: pkg/protobuf/token/token.proto
+// DO NOT EDIT!
+
+/*
+Package token is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/token/token.proto
+
+It has these top-level messages:
+	CreateRequest
+	CreateResponse
+	ListResponse
+	RevokeRequest
+	Empty
+*/
+package token
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CreateRequest struct {
+	Name    string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Apps    []string `protobuf:"bytes,2,rep,name=apps" json:"apps,omitempty"`
+	Teams   []string `protobuf:"bytes,3,rep,name=teams" json:"teams,omitempty"`
+	Actions []string `protobuf:"bytes,4,rep,name=actions" json:"actions,omitempty"`
+}
+
+func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
+func (m *CreateRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateRequest) ProtoMessage()               {}
+func (*CreateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *CreateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetTeams() []string {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+type CreateResponse struct {
+	Id    uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+}
+
+func (m *CreateResponse) Reset()                    { *m = CreateResponse{} }
+func (m *CreateResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateResponse) ProtoMessage()               {}
+func (*CreateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CreateResponse) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CreateResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Tokens []*ListResponse_Token `protobuf:"bytes,1,rep,name=tokens" json:"tokens,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ListResponse) GetTokens() []*ListResponse_Token {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type ListResponse_Token struct {
+	Id         uint64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Name       string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	User       string   `protobuf:"bytes,3,opt,name=user" json:"user,omitempty"`
+	Prefix     string   `protobuf:"bytes,4,opt,name=prefix" json:"prefix,omitempty"`
+	Apps       []string `protobuf:"bytes,5,rep,name=apps" json:"apps,omitempty"`
+	Teams      []string `protobuf:"bytes,6,rep,name=teams" json:"teams,omitempty"`
+	Actions    []string `protobuf:"bytes,7,rep,name=actions" json:"actions,omitempty"`
+	CreatedAt  string   `protobuf:"bytes,8,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	LastUsedAt string   `protobuf:"bytes,9,opt,name=last_used_at,json=lastUsedAt" json:"last_used_at,omitempty"`
+}
+
+func (m *ListResponse_Token) Reset()                    { *m = ListResponse_Token{} }
+func (m *ListResponse_Token) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Token) ProtoMessage()               {}
+func (*ListResponse_Token) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
+
+func (m *ListResponse_Token) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ListResponse_Token) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListResponse_Token) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListResponse_Token) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListResponse_Token) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetTeams() []string {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+func (m *ListResponse_Token) GetLastUsedAt() string {
+	if m != nil {
+		return m.LastUsedAt
+	}
+	return ""
+}
+
+type RevokeRequest struct {
+	Id uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *RevokeRequest) Reset()                    { *m = RevokeRequest{} }
+func (m *RevokeRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeRequest) ProtoMessage()               {}
+func (*RevokeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *RevokeRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func init() {
+	proto.RegisterType((*CreateRequest)(nil), "token.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "token.CreateResponse")
+	proto.RegisterType((*ListResponse)(nil), "token.ListResponse")
+	proto.RegisterType((*ListResponse_Token)(nil), "token.ListResponse.Token")
+	proto.RegisterType((*RevokeRequest)(nil), "token.RevokeRequest")
+	proto.RegisterType((*Empty)(nil), "token.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Token service
+
+type TokenClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type tokenClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTokenClient(cc *grpc.ClientConn) TokenClient {
+	return &tokenClient{cc}
+}
+
+func (c *tokenClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := grpc.Invoke(ctx, "/token.Token/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/token.Token/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/token.Token/Revoke", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Token service
+
+type TokenServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	List(context.Context, *Empty) (*ListResponse, error)
+	Revoke(context.Context, *RevokeRequest) (*Empty, error)
+}
+
+func RegisterTokenServer(s *grpc.Server, srv TokenServer) {
+	s.RegisterService(&_Token_serviceDesc, srv)
+}
+
+func _Token_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Token_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Token_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Token_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "token.Token",
+	HandlerType: (*TokenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Token_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Token_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _Token_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/token/token.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/token/token.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 356 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x92, 0xcd, 0x4e, 0xeb, 0x30,
+	0x10, 0x85, 0x95, 0xdf, 0xde, 0xce, 0x6d, 0xbb, 0x30, 0x05, 0x99, 0x48, 0x40, 0x94, 0x55, 0x91,
+	0x50, 0x2b, 0x8a, 0x60, 0x8f, 0x10, 0x3b, 0x56, 0x11, 0xac, 0x2b, 0xb7, 0x75, 0xab, 0xa8, 0x34,
+	0x31, 0xb1, 0x83, 0xe0, 0x49, 0xd8, 0xf2, 0x60, 0x3c, 0x0c, 0xf2, 0xd8, 0x89, 0x48, 0x29, 0x9b,
+	0xc8, 0xf3, 0x79, 0x32, 0x67, 0xe6, 0x8c, 0xe1, 0x54, 0x6c, 0xd6, 0x13, 0x51, 0x16, 0xaa, 0x98,
+	0x57, 0xab, 0x89, 0x2a, 0x36, 0x3c, 0x37, 0xdf, 0x31, 0x42, 0x12, 0x60, 0x90, 0xac, 0xa1, 0x7f,
+	0x57, 0x72, 0xa6, 0x78, 0xca, 0x5f, 0x2a, 0x2e, 0x15, 0x21, 0xe0, 0xe7, 0x6c, 0xcb, 0xa9, 0x13,
+	0x3b, 0xa3, 0x6e, 0x8a, 0x67, 0xcd, 0x98, 0x10, 0x92, 0xba, 0xb1, 0xa7, 0x99, 0x3e, 0x93, 0x21,
+	0x04, 0x8a, 0xb3, 0xad, 0xa4, 0x1e, 0x42, 0x13, 0x10, 0x0a, 0x1d, 0xb6, 0x50, 0x59, 0x91, 0x4b,
+	0xea, 0x23, 0xaf, 0xc3, 0xe4, 0x06, 0x06, 0xb5, 0x90, 0x14, 0x45, 0x2e, 0x39, 0x19, 0x80, 0x9b,
+	0x2d, 0x51, 0xc7, 0x4f, 0xdd, 0x6c, 0x89, 0x15, 0x75, 0x4f, 0xd4, 0x45, 0x69, 0xdb, 0xe0, 0xa7,
+	0x0b, 0xbd, 0x87, 0x4c, 0xaa, 0xe6, 0xb7, 0x4b, 0x08, 0xf1, 0x46, 0x52, 0x27, 0xf6, 0x46, 0xff,
+	0xa7, 0xc7, 0x63, 0x33, 0xd6, 0xcf, 0xa4, 0xf1, 0xa3, 0x46, 0xa9, 0x4d, 0x8c, 0xbe, 0x1c, 0x08,
+	0x90, 0xfc, 0xd2, 0xac, 0xa7, 0x75, 0xdb, 0xd3, 0x56, 0x92, 0x97, 0xd4, 0x33, 0x4c, 0x9f, 0xc9,
+	0x11, 0x84, 0xa2, 0xe4, 0xab, 0xec, 0x8d, 0xfa, 0x48, 0x6d, 0xd4, 0x38, 0x13, 0xec, 0x73, 0x26,
+	0xfc, 0xc3, 0x99, 0x4e, 0xcb, 0x19, 0x72, 0x02, 0xb0, 0x40, 0x67, 0x96, 0x33, 0xa6, 0xe8, 0x3f,
+	0xac, 0xdf, 0xb5, 0xe4, 0x56, 0x91, 0x18, 0x7a, 0xcf, 0x4c, 0xaa, 0x59, 0x25, 0x4d, 0x42, 0x17,
+	0x13, 0x40, 0xb3, 0x27, 0xa9, 0x33, 0x92, 0x33, 0xe8, 0xa7, 0xfc, 0xb5, 0xd8, 0x34, 0x3b, 0xdc,
+	0x99, 0x32, 0xe9, 0x40, 0x70, 0xbf, 0x15, 0xea, 0x7d, 0xfa, 0xd1, 0x18, 0x71, 0x0d, 0xa1, 0x59,
+	0x07, 0x19, 0x5a, 0xff, 0x5a, 0xcf, 0x20, 0x3a, 0xdc, 0xa1, 0xd6, 0xfc, 0x73, 0xf0, 0xb5, 0xcf,
+	0xa4, 0x67, 0xaf, 0xb1, 0x6c, 0x74, 0xb0, 0x67, 0x05, 0xe4, 0x02, 0x42, 0xd3, 0x55, 0xa3, 0xd0,
+	0x6a, 0x32, 0x6a, 0x95, 0x98, 0x87, 0xf8, 0x2a, 0xaf, 0xbe, 0x07, 0x00, 0x52, 0xbd, 0x99, 0x08,
+	0xb7, 0x02, 0x00, 0x00,
+}
@@ -14,6 +14,14 @@ It has these top-level messages:
 	SetPasswordRequest
 	DeleteRequest
 	CreateRequest
+	RefreshRequest
+	LogoutRequest
+	RevokeSessionsRequest
+	UnlockRequest
+	EnrollTOTPResponse
+	EnableTOTPRequest
+	EnableTOTPResponse
+	DisableTOTPRequest
 	Empty
 */
 package user
@@ -41,6 +49,7 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type LoginRequest struct {
 	Email    string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+	TotpCode string `protobuf:"bytes,3,opt,name=totp_code,json=totpCode" json:"totp_code,omitempty"`
 }
 
 func (m *LoginRequest) Reset()                    { *m = LoginRequest{} }
@@ -62,8 +71,16 @@ func (m *LoginRequest) GetPassword() string {
 	return ""
 }
 
+func (m *LoginRequest) GetTotpCode() string {
+	if m != nil {
+		return m.TotpCode
+	}
+	return ""
+}
+
 type LoginResponse struct {
-	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	Token        string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	RefreshToken string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
 }
 
 func (m *LoginResponse) Reset()                    { *m = LoginResponse{} }
@@ -78,6 +95,13 @@ func (m *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (m *LoginResponse) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
 type SetPasswordRequest struct {
 	Password string `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
 }
@@ -150,13 +174,157 @@ func (m *CreateRequest) GetAdmin() bool {
 	return false
 }
 
+type RefreshRequest struct {
+	RefreshToken string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
+}
+
+func (m *RefreshRequest) Reset()                    { *m = RefreshRequest{} }
+func (m *RefreshRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefreshRequest) ProtoMessage()               {}
+func (*RefreshRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *RefreshRequest) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type LogoutRequest struct {
+	RefreshToken string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
+}
+
+func (m *LogoutRequest) Reset()                    { *m = LogoutRequest{} }
+func (m *LogoutRequest) String() string            { return proto.CompactTextString(m) }
+func (*LogoutRequest) ProtoMessage()               {}
+func (*LogoutRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *LogoutRequest) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type RevokeSessionsRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *RevokeSessionsRequest) Reset()                    { *m = RevokeSessionsRequest{} }
+func (m *RevokeSessionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeSessionsRequest) ProtoMessage()               {}
+func (*RevokeSessionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *RevokeSessionsRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type UnlockRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *UnlockRequest) Reset()                    { *m = UnlockRequest{} }
+func (m *UnlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*UnlockRequest) ProtoMessage()               {}
+func (*UnlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *UnlockRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type EnrollTOTPResponse struct {
+	Secret          string `protobuf:"bytes,1,opt,name=secret" json:"secret,omitempty"`
+	ProvisioningUri string `protobuf:"bytes,2,opt,name=provisioning_uri,json=provisioningUri" json:"provisioning_uri,omitempty"`
+}
+
+func (m *EnrollTOTPResponse) Reset()                    { *m = EnrollTOTPResponse{} }
+func (m *EnrollTOTPResponse) String() string            { return proto.CompactTextString(m) }
+func (*EnrollTOTPResponse) ProtoMessage()               {}
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *EnrollTOTPResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EnrollTOTPResponse) GetProvisioningUri() string {
+	if m != nil {
+		return m.ProvisioningUri
+	}
+	return ""
+}
+
+type EnableTOTPRequest struct {
+	Code string `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *EnableTOTPRequest) Reset()                    { *m = EnableTOTPRequest{} }
+func (m *EnableTOTPRequest) String() string            { return proto.CompactTextString(m) }
+func (*EnableTOTPRequest) ProtoMessage()               {}
+func (*EnableTOTPRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *EnableTOTPRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type EnableTOTPResponse struct {
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes" json:"recovery_codes,omitempty"`
+}
+
+func (m *EnableTOTPResponse) Reset()                    { *m = EnableTOTPResponse{} }
+func (m *EnableTOTPResponse) String() string            { return proto.CompactTextString(m) }
+func (*EnableTOTPResponse) ProtoMessage()               {}
+func (*EnableTOTPResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *EnableTOTPResponse) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return nil
+}
+
+type DisableTOTPRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Code  string `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *DisableTOTPRequest) Reset()                    { *m = DisableTOTPRequest{} }
+func (m *DisableTOTPRequest) String() string            { return proto.CompactTextString(m) }
+func (*DisableTOTPRequest) ProtoMessage()               {}
+func (*DisableTOTPRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *DisableTOTPRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *DisableTOTPRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
 type Empty struct {
 }
 
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func init() {
 	proto.RegisterType((*LoginRequest)(nil), "user.LoginRequest")
@@ -164,6 +332,14 @@ func init() {
 	proto.RegisterType((*SetPasswordRequest)(nil), "user.SetPasswordRequest")
 	proto.RegisterType((*DeleteRequest)(nil), "user.DeleteRequest")
 	proto.RegisterType((*CreateRequest)(nil), "user.CreateRequest")
+	proto.RegisterType((*RefreshRequest)(nil), "user.RefreshRequest")
+	proto.RegisterType((*LogoutRequest)(nil), "user.LogoutRequest")
+	proto.RegisterType((*RevokeSessionsRequest)(nil), "user.RevokeSessionsRequest")
+	proto.RegisterType((*UnlockRequest)(nil), "user.UnlockRequest")
+	proto.RegisterType((*EnrollTOTPResponse)(nil), "user.EnrollTOTPResponse")
+	proto.RegisterType((*EnableTOTPRequest)(nil), "user.EnableTOTPRequest")
+	proto.RegisterType((*EnableTOTPResponse)(nil), "user.EnableTOTPResponse")
+	proto.RegisterType((*DisableTOTPRequest)(nil), "user.DisableTOTPRequest")
 	proto.RegisterType((*Empty)(nil), "user.Empty")
 }
 
@@ -182,6 +358,13 @@ type UserClient interface {
 	SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*Empty, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error)
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*Empty, error)
+	RevokeSessions(ctx context.Context, in *RevokeSessionsRequest, opts ...grpc.CallOption) (*Empty, error)
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*Empty, error)
+	EnrollTOTP(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error)
+	EnableTOTP(ctx context.Context, in *EnableTOTPRequest, opts ...grpc.CallOption) (*EnableTOTPResponse, error)
+	DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type userClient struct {
@@ -228,6 +411,69 @@ func (c *userClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *userClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/user.User/Refresh", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Logout", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RevokeSessions(ctx context.Context, in *RevokeSessionsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/RevokeSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Unlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) EnrollTOTP(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error) {
+	out := new(EnrollTOTPResponse)
+	err := grpc.Invoke(ctx, "/user.User/EnrollTOTP", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) EnableTOTP(ctx context.Context, in *EnableTOTPRequest, opts ...grpc.CallOption) (*EnableTOTPResponse, error) {
+	out := new(EnableTOTPResponse)
+	err := grpc.Invoke(ctx, "/user.User/EnableTOTP", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/DisableTOTP", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for User service
 
 type UserServer interface {
@@ -235,6 +481,13 @@ type UserServer interface {
 	SetPassword(context.Context, *SetPasswordRequest) (*Empty, error)
 	Delete(context.Context, *DeleteRequest) (*Empty, error)
 	Create(context.Context, *CreateRequest) (*Empty, error)
+	Refresh(context.Context, *RefreshRequest) (*LoginResponse, error)
+	Logout(context.Context, *LogoutRequest) (*Empty, error)
+	RevokeSessions(context.Context, *RevokeSessionsRequest) (*Empty, error)
+	Unlock(context.Context, *UnlockRequest) (*Empty, error)
+	EnrollTOTP(context.Context, *Empty) (*EnrollTOTPResponse, error)
+	EnableTOTP(context.Context, *EnableTOTPRequest) (*EnableTOTPResponse, error)
+	DisableTOTP(context.Context, *DisableTOTPRequest) (*Empty, error)
 }
 
 func RegisterUserServer(s *grpc.Server, srv UserServer) {
@@ -313,6 +566,132 @@ func _User_Create_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _User_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RevokeSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RevokeSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/RevokeSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RevokeSessions(ctx, req.(*RevokeSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).EnrollTOTP(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_EnableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).EnableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/EnableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).EnableTOTP(ctx, req.(*EnableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/DisableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DisableTOTP(ctx, req.(*DisableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _User_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "user.User",
 	HandlerType: (*UserServer)(nil),
@@ -333,6 +712,34 @@ var _User_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Create",
 			Handler:    _User_Create_Handler,
 		},
+		{
+			MethodName: "Refresh",
+			Handler:    _User_Refresh_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _User_Logout_Handler,
+		},
+		{
+			MethodName: "RevokeSessions",
+			Handler:    _User_RevokeSessions_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _User_Unlock_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _User_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "EnableTOTP",
+			Handler:    _User_EnableTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _User_DisableTOTP_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/protobuf/user/user.proto",
@@ -341,23 +748,40 @@ var _User_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/user/user.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 279 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xd1, 0x4a, 0xc3, 0x30,
-	0x14, 0x86, 0xe9, 0x6c, 0xe7, 0x3c, 0x73, 0x37, 0x47, 0x2f, 0x4a, 0xf1, 0x62, 0x14, 0x84, 0xe1,
-	0xc5, 0x36, 0xd4, 0x07, 0x10, 0xd4, 0x3b, 0x2f, 0xa4, 0xe2, 0x03, 0x64, 0xec, 0x38, 0x4a, 0xd7,
-	0x24, 0x26, 0x29, 0xe2, 0x0b, 0xfa, 0x5c, 0xd2, 0x24, 0x75, 0x0d, 0xc5, 0xdd, 0x94, 0xfc, 0x39,
-	0xff, 0xf9, 0x4f, 0xcf, 0x47, 0xe0, 0x4a, 0x56, 0xbb, 0x95, 0x54, 0xc2, 0x88, 0x4d, 0xf3, 0xb1,
-	0x6a, 0x34, 0x29, 0xfb, 0x59, 0xda, 0x2b, 0x8c, 0xdb, 0x73, 0xfe, 0x00, 0xe7, 0x2f, 0x62, 0x57,
-	0xf2, 0x82, 0x3e, 0x1b, 0xd2, 0x06, 0x2f, 0x21, 0xa1, 0x9a, 0x95, 0xfb, 0x34, 0x9a, 0x47, 0x8b,
-	0xb3, 0xc2, 0x09, 0xcc, 0x60, 0x22, 0x99, 0xd6, 0x5f, 0x42, 0x6d, 0xd3, 0x91, 0x2d, 0xfc, 0xe9,
-	0xfc, 0x1a, 0x66, 0x3e, 0x41, 0x4b, 0xc1, 0x35, 0xb5, 0x11, 0x46, 0x54, 0xc4, 0xbb, 0x08, 0x2b,
-	0xf2, 0x35, 0xe0, 0x1b, 0x99, 0x57, 0xdf, 0xd5, 0x8d, 0xeb, 0x07, 0x47, 0xc3, 0xe0, 0x27, 0xda,
-	0x93, 0xa1, 0xa3, 0xff, 0x96, 0x57, 0x30, 0x7b, 0x54, 0xc4, 0x0e, 0x36, 0x84, 0x98, 0xb3, 0x9a,
-	0xbc, 0xcb, 0x9e, 0x0f, 0xad, 0xa3, 0xff, 0xd6, 0x3a, 0x09, 0xa7, 0xb7, 0x1d, 0x6c, 0x5b, 0x97,
-	0x3c, 0x8d, 0xe7, 0xd1, 0x62, 0x52, 0x38, 0x91, 0x9f, 0x42, 0xf2, 0x5c, 0x4b, 0xf3, 0x7d, 0xfb,
-	0x13, 0x41, 0xfc, 0xae, 0x49, 0xe1, 0x1a, 0x12, 0xbb, 0x3e, 0xe2, 0xd2, 0xc2, 0xed, 0xd3, 0xcc,
-	0x2e, 0x82, 0x3b, 0xcf, 0xe7, 0x1e, 0xa6, 0x3d, 0x12, 0x98, 0x3a, 0xcf, 0x10, 0x4e, 0x36, 0x75,
-	0x15, 0x3b, 0x10, 0x6f, 0x60, 0xec, 0x68, 0xa0, 0x0f, 0x0d, 0xd8, 0x0c, 0xbc, 0x0e, 0x49, 0xe7,
-	0x0d, 0x00, 0x05, 0xde, 0xcd, 0xd8, 0xbe, 0x86, 0xbb, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4b,
-	0x8d, 0x15, 0xce, 0x2d, 0x02, 0x00, 0x00,
+	// 559 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x94, 0xdf, 0x6f, 0xd3, 0x30,
+	0x10, 0xc7, 0x95, 0xf5, 0xc7, 0xd6, 0xdb, 0x52, 0xe0, 0x28, 0x10, 0x75, 0x3c, 0x54, 0x41, 0x15,
+	0x65, 0x12, 0xdb, 0xc4, 0xca, 0x0b, 0x48, 0xf0, 0xb0, 0xf5, 0x05, 0x21, 0x31, 0x65, 0xad, 0x78,
+	0x42, 0x55, 0xda, 0xde, 0x4a, 0xd4, 0x34, 0x0e, 0x76, 0x5a, 0xb4, 0xff, 0x8d, 0x3f, 0x0e, 0xc5,
+	0x76, 0x9a, 0xb8, 0x29, 0x95, 0x78, 0xa9, 0xec, 0xbb, 0xaf, 0xfd, 0xbd, 0x3b, 0x7f, 0x52, 0x78,
+	0x19, 0x2f, 0xe6, 0x17, 0x31, 0x67, 0x09, 0x9b, 0xac, 0xee, 0x2f, 0x56, 0x82, 0xb8, 0xfc, 0x39,
+	0x97, 0x21, 0xac, 0xa6, 0x6b, 0xf7, 0x07, 0x9c, 0x7c, 0x65, 0xf3, 0x20, 0xf2, 0xe8, 0xd7, 0x8a,
+	0x44, 0x82, 0x2d, 0xa8, 0xd1, 0xd2, 0x0f, 0x42, 0xc7, 0xea, 0x58, 0xbd, 0x86, 0xa7, 0x36, 0xd8,
+	0x86, 0xa3, 0xd8, 0x17, 0xe2, 0x37, 0xe3, 0x33, 0xe7, 0x40, 0x26, 0x36, 0x7b, 0x3c, 0x85, 0x46,
+	0xc2, 0x92, 0x78, 0x3c, 0x65, 0x33, 0x72, 0x2a, 0x2a, 0x99, 0x06, 0xae, 0xd9, 0x8c, 0xdc, 0x2f,
+	0x60, 0xeb, 0xeb, 0x45, 0xcc, 0x22, 0x41, 0xe9, 0xfd, 0x09, 0x5b, 0x50, 0x94, 0xdd, 0x2f, 0x37,
+	0xf8, 0x0a, 0x6c, 0x4e, 0xf7, 0x9c, 0xc4, 0xcf, 0xb1, 0xca, 0x2a, 0x93, 0x13, 0x1d, 0x1c, 0xa6,
+	0x31, 0xf7, 0x12, 0xf0, 0x8e, 0x92, 0x5b, 0xed, 0x9b, 0x15, 0x5c, 0x2c, 0xcd, 0x32, 0x4b, 0x73,
+	0xbb, 0x60, 0xdf, 0x50, 0x48, 0x09, 0xed, 0xed, 0xce, 0x5d, 0x80, 0x7d, 0xcd, 0xc9, 0xcf, 0x65,
+	0x08, 0xd5, 0xc8, 0x5f, 0x92, 0x56, 0xc9, 0x75, 0x7e, 0xf4, 0xe0, 0x5f, 0x83, 0xa9, 0x6c, 0x0d,
+	0xa6, 0x05, 0x35, 0x7f, 0xb6, 0x0c, 0x22, 0xa7, 0xda, 0xb1, 0x7a, 0x47, 0x9e, 0xda, 0xb8, 0xef,
+	0xa1, 0xe9, 0xa9, 0xae, 0x32, 0xb7, 0x52, 0xf3, 0xd6, 0x8e, 0xe6, 0xfb, 0x72, 0x90, 0x6c, 0x95,
+	0xfc, 0xd7, 0xa9, 0xb7, 0xf0, 0xcc, 0xa3, 0x35, 0x5b, 0xd0, 0x1d, 0x09, 0x11, 0xb0, 0x48, 0xec,
+	0x1f, 0x44, 0x17, 0xec, 0x51, 0x14, 0xb2, 0xe9, 0x62, 0xbf, 0xec, 0x3b, 0xe0, 0x20, 0xe2, 0x2c,
+	0x0c, 0x87, 0xdf, 0x86, 0xb7, 0x9b, 0x97, 0x7d, 0x0e, 0x75, 0x41, 0x53, 0x4e, 0x89, 0x16, 0xeb,
+	0x1d, 0xbe, 0x81, 0xc7, 0x31, 0x67, 0xeb, 0x20, 0xf5, 0x0f, 0xa2, 0xf9, 0x78, 0xc5, 0x03, 0x3d,
+	0xc3, 0x47, 0xc5, 0xf8, 0x88, 0x07, 0xee, 0x6b, 0x78, 0x32, 0x88, 0xfc, 0x49, 0x48, 0xea, 0xe2,
+	0xcd, 0x63, 0x48, 0xb4, 0xf4, 0x63, 0xa4, 0x6b, 0xf7, 0x23, 0x60, 0x51, 0xa8, 0x2b, 0xe8, 0x42,
+	0x93, 0xd3, 0x94, 0xad, 0x89, 0x3f, 0x48, 0x1a, 0x85, 0x63, 0x75, 0x2a, 0xbd, 0x86, 0x67, 0x67,
+	0xd1, 0x14, 0x49, 0xe1, 0x7e, 0x02, 0xbc, 0x09, 0xc4, 0xb6, 0xcd, 0x6e, 0xf0, 0x33, 0xf3, 0x83,
+	0x82, 0xf9, 0x21, 0xd4, 0x06, 0xcb, 0x38, 0x79, 0x78, 0xf7, 0xa7, 0x0a, 0xd5, 0x91, 0x20, 0x8e,
+	0x97, 0x50, 0x93, 0x94, 0x23, 0x9e, 0xcb, 0x0f, 0xac, 0xf8, 0x45, 0xb5, 0x9f, 0x1a, 0x31, 0x5d,
+	0x6a, 0x1f, 0x8e, 0x0b, 0x2c, 0xa3, 0xa3, 0x34, 0x65, 0xbc, 0xdb, 0xc7, 0x2a, 0x23, 0x0d, 0xf1,
+	0x0c, 0xea, 0x8a, 0x67, 0xd4, 0x97, 0x1a, 0x74, 0x97, 0xb4, 0x0a, 0xea, 0x4c, 0x6b, 0x20, 0x6e,
+	0x6a, 0xfb, 0x70, 0xa8, 0x99, 0xc4, 0x96, 0x8a, 0x9b, 0x88, 0xee, 0xee, 0xe1, 0x0c, 0xea, 0x0a,
+	0x49, 0xcc, 0xd3, 0x39, 0xa0, 0xa6, 0xc3, 0x07, 0x68, 0x9a, 0x20, 0xe2, 0x69, 0x66, 0xb4, 0x03,
+	0xcf, 0x52, 0x27, 0x8a, 0xca, 0xcc, 0xc7, 0x60, 0xd4, 0xd4, 0x5e, 0x01, 0xe4, 0x68, 0x62, 0x31,
+	0xd5, 0xd6, 0x33, 0xde, 0x41, 0xee, 0xe7, 0xf4, 0x50, 0xc6, 0x03, 0xbe, 0xc8, 0x74, 0x5b, 0x84,
+	0xb4, 0x9d, 0x72, 0x22, 0x7f, 0xcd, 0x02, 0x51, 0xd9, 0x6b, 0x96, 0x21, 0x33, 0x6a, 0x9d, 0xd4,
+	0xe5, 0xff, 0xf0, 0xd5, 0xdf, 0x01, 0x00, 0xbc, 0xa7, 0xdc, 0xd1, 0xa7, 0x05, 0x00, 0x00,
 }
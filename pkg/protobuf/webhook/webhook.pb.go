@@ -0,0 +1,523 @@
+// Code generated by protoc-gen-go.
+// source: pkg/protobuf/webhook/webhook.proto
+// DO NOT EDIT!
+
+/*
+Package webhook is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/webhook/webhook.proto
+
+It has these top-level messages:
+	CreateRequest
+	CreateResponse
+	ListResponse
+	DeleteRequest
+	DeliveriesRequest
+	DeliveriesResponse
+	Empty
+*/
+package webhook
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CreateRequest struct {
+	Team   string   `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	App    string   `protobuf:"bytes,2,opt,name=app" json:"app,omitempty"`
+	Url    string   `protobuf:"bytes,3,opt,name=url" json:"url,omitempty"`
+	Secret string   `protobuf:"bytes,4,opt,name=secret" json:"secret,omitempty"`
+	Events []string `protobuf:"bytes,5,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
+func (m *CreateRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateRequest) ProtoMessage()               {}
+func (*CreateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *CreateRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type CreateResponse struct {
+	Id     uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Secret string `protobuf:"bytes,2,opt,name=secret" json:"secret,omitempty"`
+}
+
+func (m *CreateResponse) Reset()                    { *m = CreateResponse{} }
+func (m *CreateResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateResponse) ProtoMessage()               {}
+func (*CreateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CreateResponse) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CreateResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Webhooks []*ListResponse_Webhook `protobuf:"bytes,1,rep,name=webhooks" json:"webhooks,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ListResponse) GetWebhooks() []*ListResponse_Webhook {
+	if m != nil {
+		return m.Webhooks
+	}
+	return nil
+}
+
+type ListResponse_Webhook struct {
+	Id        uint64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Team      string   `protobuf:"bytes,2,opt,name=team" json:"team,omitempty"`
+	App       string   `protobuf:"bytes,3,opt,name=app" json:"app,omitempty"`
+	Url       string   `protobuf:"bytes,4,opt,name=url" json:"url,omitempty"`
+	Events    []string `protobuf:"bytes,5,rep,name=events" json:"events,omitempty"`
+	CreatedBy string   `protobuf:"bytes,6,opt,name=created_by,json=createdBy" json:"created_by,omitempty"`
+}
+
+func (m *ListResponse_Webhook) Reset()                    { *m = ListResponse_Webhook{} }
+func (m *ListResponse_Webhook) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Webhook) ProtoMessage()               {}
+func (*ListResponse_Webhook) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
+
+func (m *ListResponse_Webhook) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ListResponse_Webhook) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListResponse_Webhook) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListResponse_Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *ListResponse_Webhook) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListResponse_Webhook) GetCreatedBy() string {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	Id uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *DeleteRequest) Reset()                    { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()               {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *DeleteRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type DeliveriesRequest struct {
+	Id    uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Limit int64  `protobuf:"varint,2,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *DeliveriesRequest) Reset()                    { *m = DeliveriesRequest{} }
+func (m *DeliveriesRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeliveriesRequest) ProtoMessage()               {}
+func (*DeliveriesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *DeliveriesRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *DeliveriesRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type DeliveriesResponse struct {
+	Deliveries []*DeliveriesResponse_Delivery `protobuf:"bytes,1,rep,name=deliveries" json:"deliveries,omitempty"`
+}
+
+func (m *DeliveriesResponse) Reset()                    { *m = DeliveriesResponse{} }
+func (m *DeliveriesResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeliveriesResponse) ProtoMessage()               {}
+func (*DeliveriesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *DeliveriesResponse) GetDeliveries() []*DeliveriesResponse_Delivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+type DeliveriesResponse_Delivery struct {
+	Event      string `protobuf:"bytes,1,opt,name=event" json:"event,omitempty"`
+	Attempt    int32  `protobuf:"varint,2,opt,name=attempt" json:"attempt,omitempty"`
+	StatusCode int32  `protobuf:"varint,3,opt,name=status_code,json=statusCode" json:"status_code,omitempty"`
+	Error      string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	Success    bool   `protobuf:"varint,5,opt,name=success" json:"success,omitempty"`
+	CreatedAt  string `protobuf:"bytes,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *DeliveriesResponse_Delivery) Reset()                    { *m = DeliveriesResponse_Delivery{} }
+func (m *DeliveriesResponse_Delivery) String() string            { return proto.CompactTextString(m) }
+func (*DeliveriesResponse_Delivery) ProtoMessage()               {}
+func (*DeliveriesResponse_Delivery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
+
+func (m *DeliveriesResponse_Delivery) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *DeliveriesResponse_Delivery) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+func (m *DeliveriesResponse_Delivery) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *DeliveriesResponse_Delivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *DeliveriesResponse_Delivery) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *DeliveriesResponse_Delivery) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func init() {
+	proto.RegisterType((*CreateRequest)(nil), "webhook.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "webhook.CreateResponse")
+	proto.RegisterType((*ListResponse)(nil), "webhook.ListResponse")
+	proto.RegisterType((*ListResponse_Webhook)(nil), "webhook.ListResponse.Webhook")
+	proto.RegisterType((*DeleteRequest)(nil), "webhook.DeleteRequest")
+	proto.RegisterType((*DeliveriesRequest)(nil), "webhook.DeliveriesRequest")
+	proto.RegisterType((*DeliveriesResponse)(nil), "webhook.DeliveriesResponse")
+	proto.RegisterType((*DeliveriesResponse_Delivery)(nil), "webhook.DeliveriesResponse.Delivery")
+	proto.RegisterType((*Empty)(nil), "webhook.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Webhook service
+
+type WebhookClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	Deliveries(ctx context.Context, in *DeliveriesRequest, opts ...grpc.CallOption) (*DeliveriesResponse, error)
+}
+
+type webhookClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWebhookClient(cc *grpc.ClientConn) WebhookClient {
+	return &webhookClient{cc}
+}
+
+func (c *webhookClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := grpc.Invoke(ctx, "/webhook.Webhook/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/webhook.Webhook/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/webhook.Webhook/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookClient) Deliveries(ctx context.Context, in *DeliveriesRequest, opts ...grpc.CallOption) (*DeliveriesResponse, error) {
+	out := new(DeliveriesResponse)
+	err := grpc.Invoke(ctx, "/webhook.Webhook/Deliveries", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Webhook service
+
+type WebhookServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	List(context.Context, *Empty) (*ListResponse, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+	Deliveries(context.Context, *DeliveriesRequest) (*DeliveriesResponse, error)
+}
+
+func RegisterWebhookServer(s *grpc.Server, srv WebhookServer) {
+	s.RegisterService(&_Webhook_serviceDesc, srv)
+}
+
+func _Webhook_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/webhook.Webhook/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhook_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/webhook.Webhook/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhook_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/webhook.Webhook/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhook_Deliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServer).Deliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/webhook.Webhook/Deliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServer).Deliveries(ctx, req.(*DeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Webhook_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "webhook.Webhook",
+	HandlerType: (*WebhookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Webhook_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Webhook_List_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Webhook_Delete_Handler,
+		},
+		{
+			MethodName: "Deliveries",
+			Handler:    _Webhook_Deliveries_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/webhook/webhook.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/webhook/webhook.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 466 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x53, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x96, 0x7f, 0x93, 0x4e, 0x69, 0x04, 0x23, 0x28, 0x96, 0x51, 0xd5, 0xc8, 0xe2, 0x90, 0x0b,
+	0x29, 0x2a, 0x17, 0x72, 0x84, 0xa6, 0x37, 0x4e, 0x7b, 0xe1, 0x58, 0x39, 0xf6, 0x00, 0x56, 0x9d,
+	0xda, 0xec, 0xae, 0x5b, 0xf9, 0x09, 0xe0, 0x59, 0x78, 0x1e, 0x1e, 0x83, 0x87, 0x40, 0xde, 0x5d,
+	0xbb, 0x9b, 0x38, 0x70, 0xb2, 0xbf, 0x6f, 0xe7, 0x67, 0xe7, 0xfb, 0x66, 0x21, 0xa9, 0x6f, 0xbf,
+	0x5e, 0xd4, 0xbc, 0x92, 0xd5, 0xa6, 0xf9, 0x72, 0xf1, 0x40, 0x9b, 0x6f, 0x55, 0x75, 0xdb, 0x7f,
+	0x97, 0xea, 0x00, 0x27, 0x06, 0x26, 0x0f, 0x70, 0x72, 0xc5, 0x29, 0x95, 0xc4, 0xe8, 0x7b, 0x43,
+	0x42, 0x22, 0x82, 0x2f, 0x29, 0xdd, 0x46, 0xce, 0xdc, 0x59, 0x1c, 0x31, 0xf5, 0x8f, 0x4f, 0xc1,
+	0x4b, 0xeb, 0x3a, 0x72, 0x15, 0xd5, 0xfd, 0x76, 0x4c, 0xc3, 0xcb, 0xc8, 0xd3, 0x4c, 0xc3, 0x4b,
+	0x3c, 0x85, 0x50, 0x50, 0xc6, 0x49, 0x46, 0xbe, 0x22, 0x0d, 0xea, 0x78, 0xba, 0xa7, 0x3b, 0x29,
+	0xa2, 0x60, 0xee, 0x75, 0xbc, 0x46, 0xc9, 0x7b, 0x98, 0xf5, 0x8d, 0x45, 0x5d, 0xdd, 0x09, 0xc2,
+	0x19, 0xb8, 0x45, 0xae, 0xfa, 0xfa, 0xcc, 0x2d, 0x72, 0xab, 0xa2, 0x6b, 0x57, 0x4c, 0x7e, 0x3b,
+	0xf0, 0xe4, 0x53, 0x21, 0xe4, 0x90, 0xb8, 0x82, 0xa9, 0x19, 0x47, 0x44, 0xce, 0xdc, 0x5b, 0x1c,
+	0x5f, 0x9e, 0x2d, 0xfb, 0x71, 0xed, 0xc0, 0xe5, 0x67, 0x4d, 0xb2, 0x21, 0x3c, 0xfe, 0xe9, 0xc0,
+	0xc4, 0xb0, 0xa3, 0xfe, 0xbd, 0x12, 0xee, 0x58, 0x09, 0x6f, 0xa4, 0x84, 0xbf, 0xa3, 0xc4, 0xa1,
+	0x89, 0xf1, 0x0c, 0x20, 0x53, 0x13, 0xe7, 0x37, 0x9b, 0x36, 0x0a, 0x55, 0xc2, 0x91, 0x61, 0x3e,
+	0xb6, 0xc9, 0x39, 0x9c, 0xac, 0xa9, 0xa4, 0x47, 0x27, 0xf6, 0xee, 0x93, 0xac, 0xe0, 0xd9, 0x9a,
+	0xca, 0xe2, 0x9e, 0x78, 0x41, 0xe2, 0x1f, 0x41, 0xf8, 0x1c, 0x82, 0xb2, 0xd8, 0x16, 0x5a, 0x33,
+	0x8f, 0x69, 0x90, 0xfc, 0x70, 0x01, 0xed, 0x5c, 0x23, 0xdc, 0x1a, 0x20, 0x1f, 0x58, 0x23, 0xdd,
+	0xeb, 0x41, 0xba, 0x71, 0x42, 0x4f, 0xb5, 0xcc, 0xca, 0x8b, 0x7f, 0x39, 0x30, 0xed, 0x0f, 0xba,
+	0xfe, 0x6a, 0x5c, 0xb3, 0x3f, 0x1a, 0x60, 0x04, 0x93, 0x54, 0x4a, 0xda, 0xd6, 0xfa, 0x5e, 0x01,
+	0xeb, 0x21, 0x9e, 0xc3, 0xb1, 0x90, 0xa9, 0x6c, 0xc4, 0x4d, 0x56, 0xe5, 0xa4, 0x84, 0x0d, 0x18,
+	0x68, 0xea, 0xaa, 0xca, 0x49, 0x15, 0xe4, 0xbc, 0xe2, 0x46, 0x61, 0x0d, 0xba, 0x82, 0xa2, 0xc9,
+	0x32, 0x12, 0x9d, 0xc8, 0xce, 0x62, 0xca, 0x7a, 0x68, 0xab, 0x9c, 0xca, 0x3d, 0x95, 0x3f, 0xc8,
+	0x64, 0x02, 0xc1, 0xf5, 0xb6, 0x96, 0xed, 0xe5, 0x1f, 0xcb, 0xf9, 0x15, 0x84, 0x7a, 0x17, 0xf1,
+	0x74, 0x98, 0x7e, 0xe7, 0x55, 0xc4, 0x2f, 0x47, 0xbc, 0x91, 0xf0, 0x0d, 0xf8, 0xdd, 0x8a, 0xe1,
+	0x6c, 0x08, 0x50, 0xe5, 0xe3, 0x17, 0x07, 0x37, 0x10, 0xdf, 0x42, 0xa8, 0x4d, 0xb6, 0x3a, 0xed,
+	0xb8, 0x1e, 0xef, 0x15, 0xc2, 0x6b, 0x80, 0x47, 0x23, 0x30, 0x3e, 0xe8, 0x8e, 0xce, 0x7c, 0xf5,
+	0x1f, 0xe7, 0x36, 0xa1, 0x7a, 0xf7, 0xef, 0xfe, 0x0e, 0x00, 0x86, 0x96, 0xd0, 0x33, 0x1d, 0x04,
+	0x00, 0x00,
+}
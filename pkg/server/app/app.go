@@ -5,16 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
 
 	"github.com/luizalabs/teresa-api/models/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/labels"
 	"github.com/luizalabs/teresa-api/pkg/server/slug"
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/team"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 )
 
 type Operations interface {
@@ -23,9 +26,12 @@ type Operations interface {
 	Info(user *storage.User, appName string) (*Info, error)
 	TeamName(appName string) (string, error)
 	Get(appName string) (*App, error)
-	HasPermission(user *storage.User, appName string) bool
+	HasPermission(user *storage.User, appName, role string) bool
 	SetEnv(user *storage.User, appName string, evs []*EnvVar) error
 	UnsetEnv(user *storage.User, appName string, evs []string) error
+	Events(user *storage.User, appName string, follow bool) (<-chan *Event, func(), error)
+	Metrics(user *storage.User, appName string) (*Metrics, error)
+	ClearBuildCache(user *storage.User, appName string) error
 }
 
 type K8sOperations interface {
@@ -35,7 +41,6 @@ type K8sOperations interface {
 	PodLogs(namespace, podName string, lines int64, follow bool) (io.ReadCloser, error)
 	CreateNamespace(app *App, userEmail string) error
 	CreateQuota(app *App) error
-	CreateSecret(appName, secretName string, data map[string][]byte) error
 	CreateAutoScale(app *App) error
 	AddressList(namespace string) ([]*Address, error)
 	Status(namespace string) (*Status, error)
@@ -46,46 +51,45 @@ type K8sOperations interface {
 	SetNamespaceAnnotations(namespace string, annotations map[string]string) error
 	DeleteDeployEnvVars(namespace, name string, evNames []string) error
 	CreateOrUpdateDeployEnvVars(namespace, name string, evs []*EnvVar) error
+	Events(namespace string, follow bool) (<-chan *Event, func(), error)
+	PodMetrics(namespace string) ([]*PodMetrics, error)
 }
 
 type AppOperations struct {
 	tops team.Operations
 	kops K8sOperations
 	st   st.Storage
+	wh   webhook.Notifier
 }
 
 const (
-	limitsName       = "limits"
-	TeresaAnnotation = "teresa.io/app"
-	TeresaTeamLabel  = "teresa.io/team"
-	TeresaLastUser   = "teresa.io/last-user"
+	buildCachePathTmpl = "deploys/%s/cache.tgz"
+	limitsName         = "limits"
+	TeresaAnnotation   = "teresa.io/app"
+	TeresaTeamLabel    = labels.Team
+	TeresaLastUser     = "teresa.io/last-user"
 )
 
-func (ops *AppOperations) hasPerm(user *storage.User, team string) bool {
-	teams, err := ops.tops.ListByUser(user.Email)
+// hasPerm reports whether the user is a member of the team with at least
+// the given role.
+func (ops *AppOperations) hasPerm(user *storage.User, teamName, role string) bool {
+	userRole, err := ops.tops.Role(teamName, user.Email)
 	if err != nil {
 		return false
 	}
-	var found bool
-	for _, t := range teams {
-		if t.Name == team {
-			found = true
-			break
-		}
-	}
-	return found
+	return team.RoleAllows(userRole, role)
 }
 
-func (ops *AppOperations) HasPermission(user *storage.User, appName string) bool {
+func (ops *AppOperations) HasPermission(user *storage.User, appName, role string) bool {
 	teamName, err := ops.TeamName(appName)
 	if err != nil {
 		return false
 	}
-	return ops.hasPerm(user, teamName)
+	return ops.hasPerm(user, teamName, role)
 }
 
 func (ops *AppOperations) Create(user *storage.User, app *App) error {
-	if !ops.hasPerm(user, app.Team) {
+	if !ops.hasPerm(user, app.Team, team.RoleMember) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -100,21 +104,16 @@ func (ops *AppOperations) Create(user *storage.User, app *App) error {
 		return teresa_errors.NewInternalServerError(err)
 	}
 
-	secretName := ops.st.K8sSecretName()
-	data := ops.st.AccessData()
-	if err := ops.kops.CreateSecret(app.Name, secretName, data); err != nil {
-		return teresa_errors.NewInternalServerError(err)
-	}
-
 	if err := ops.kops.CreateAutoScale(app); err != nil {
 		return teresa_errors.NewInternalServerError(err)
 	}
 
+	ops.notify(webhook.AppCreated, app, user, nil)
 	return nil
 }
 
 func (ops *AppOperations) Logs(user *storage.User, appName string, lines int64, follow bool) (io.ReadCloser, error) {
-	team, err := ops.kops.NamespaceLabel(appName, TeresaTeamLabel)
+	teamName, err := ops.kops.NamespaceLabel(appName, TeresaTeamLabel)
 	if err != nil {
 		if ops.kops.IsNotFound(err) {
 			return nil, ErrNotFound
@@ -122,7 +121,7 @@ func (ops *AppOperations) Logs(user *storage.User, appName string, lines int64,
 		return nil, teresa_errors.NewInternalServerError(err)
 	}
 
-	if !ops.hasPerm(user, team) {
+	if !ops.hasPerm(user, teamName, team.RoleViewer) {
 		return nil, auth.ErrPermissionDenied
 	}
 
@@ -168,7 +167,7 @@ func (ops *AppOperations) Info(user *storage.User, appName string) (*Info, error
 		return nil, err
 	}
 
-	if !ops.hasPerm(user, teamName) {
+	if !ops.hasPerm(user, teamName, team.RoleViewer) {
 		return nil, auth.ErrPermissionDenied
 	}
 
@@ -236,17 +235,22 @@ func (ops *AppOperations) Get(appName string) (*App, error) {
 	return a, nil
 }
 
-func (ops *AppOperations) checkPermAndGet(user *storage.User, appName string) (*App, error) {
-	team, err := ops.TeamName(appName)
+func (ops *AppOperations) checkPermAndGet(user *storage.User, appName, role string) (*App, error) {
+	teamName, err := ops.TeamName(appName)
 	if err != nil {
 		return nil, err
 	}
 
-	if !ops.hasPerm(user, team) {
+	if !ops.hasPerm(user, teamName, role) {
 		return nil, auth.ErrPermissionDenied
 	}
 
-	return ops.Get(appName)
+	app, err := ops.Get(appName)
+	if err != nil {
+		return nil, err
+	}
+	app.Team = teamName
+	return app, nil
 }
 
 func (ops *AppOperations) saveApp(app *App, lastUser string) error {
@@ -272,7 +276,7 @@ func (ops *AppOperations) SetEnv(user *storage.User, appName string, evs []*EnvV
 		return err
 	}
 
-	app, err := ops.checkPermAndGet(user, appName)
+	app, err := ops.checkPermAndGet(user, appName, team.RoleMember)
 	if err != nil {
 		return err
 	}
@@ -283,12 +287,11 @@ func (ops *AppOperations) SetEnv(user *storage.User, appName string, evs []*EnvV
 		return teresa_errors.NewInternalServerError(err)
 	}
 
-	if err = ops.kops.CreateOrUpdateDeployEnvVars(appName, appName, evs); err != nil {
-		if ops.kops.IsNotFound(err) {
-			return nil
-		}
+	if err = ops.kops.CreateOrUpdateDeployEnvVars(appName, appName, evs); err != nil && !ops.kops.IsNotFound(err) {
 		return teresa_errors.NewInternalServerError(err)
 	}
+
+	ops.notify(webhook.AppEnvChanged, app, user, map[string]string{"set": strings.Join(evNames, ",")})
 	return nil
 }
 
@@ -297,7 +300,7 @@ func (ops *AppOperations) UnsetEnv(user *storage.User, appName string, evNames [
 		return err
 	}
 
-	app, err := ops.checkPermAndGet(user, appName)
+	app, err := ops.checkPermAndGet(user, appName, team.RoleMember)
 	if err != nil {
 		return err
 	}
@@ -308,15 +311,78 @@ func (ops *AppOperations) UnsetEnv(user *storage.User, appName string, evNames [
 		return teresa_errors.NewInternalServerError(err)
 	}
 
-	if err = ops.kops.DeleteDeployEnvVars(appName, appName, evNames); err != nil {
+	if err = ops.kops.DeleteDeployEnvVars(appName, appName, evNames); err != nil && !ops.kops.IsNotFound(err) {
+		return teresa_errors.NewInternalServerError(err)
+	}
+
+	ops.notify(webhook.AppEnvChanged, app, user, map[string]string{"unset": strings.Join(evNames, ",")})
+	return nil
+}
+
+func (ops *AppOperations) Events(user *storage.User, appName string, follow bool) (<-chan *Event, func(), error) {
+	teamName, err := ops.TeamName(appName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !ops.hasPerm(user, teamName, team.RoleMember) {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+
+	ch, stop, err := ops.kops.Events(appName, follow)
+	if err != nil {
+		return nil, nil, teresa_errors.NewInternalServerError(err)
+	}
+	return ch, stop, nil
+}
+
+func (ops *AppOperations) Metrics(user *storage.User, appName string) (*Metrics, error) {
+	teamName, err := ops.TeamName(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ops.hasPerm(user, teamName, team.RoleMember) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	pods, err := ops.kops.PodMetrics(appName)
+	if err != nil {
 		if ops.kops.IsNotFound(err) {
-			return nil
+			return nil, teresa_errors.New(ErrMetricsNotFound, err)
 		}
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+
+	lim, err := ops.kops.Limits(appName, limitsName)
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+
+	return &Metrics{Pods: pods, Limits: lim}, nil
+}
+
+func (ops *AppOperations) ClearBuildCache(user *storage.User, appName string) error {
+	teamName, err := ops.TeamName(appName)
+	if err != nil {
+		return err
+	}
+
+	if !ops.hasPerm(user, teamName, team.RoleMember) {
+		return auth.ErrPermissionDenied
+	}
+
+	if err := ops.st.Delete(BuildCachePath(appName)); err != nil {
 		return teresa_errors.NewInternalServerError(err)
 	}
 	return nil
 }
 
+// BuildCachePath returns the storage path of the build cache of the app.
+func BuildCachePath(appName string) string {
+	return fmt.Sprintf(buildCachePathTmpl, appName)
+}
+
 func checkForProtectedEnvVars(evsNames []string) error {
 	for _, name := range slug.ProtectedEnvVars {
 		for _, item := range evsNames {
@@ -328,6 +394,15 @@ func checkForProtectedEnvVars(evsNames []string) error {
 	return nil
 }
 
-func NewOperations(tops team.Operations, kops K8sOperations, st st.Storage) Operations {
-	return &AppOperations{tops: tops, kops: kops, st: st}
+// notify sends the event to the app webhooks; only the names of the env
+// vars go in the payload, never their values.
+func (ops *AppOperations) notify(event string, app *App, user *storage.User, data map[string]string) {
+	if ops.wh == nil {
+		return
+	}
+	ops.wh.Notify(webhook.NewEvent(event, app.Team, app.Name, user.Email, data))
+}
+
+func NewOperations(tops team.Operations, kops K8sOperations, st st.Storage, wh webhook.Notifier) Operations {
+	return &AppOperations{tops: tops, kops: kops, st: st, wh: wh}
 }
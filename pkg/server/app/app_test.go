@@ -17,6 +17,7 @@ import (
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/team"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 )
 
 type fakeK8sOperations struct{}
@@ -25,7 +26,6 @@ type errK8sOperations struct {
 	Err          error
 	NamespaceErr error
 	QuotaErr     error
-	SecretErr    error
 	AutoScaleErr error
 }
 
@@ -37,10 +37,6 @@ func (*fakeK8sOperations) CreateQuota(app *App) error {
 	return nil
 }
 
-func (*fakeK8sOperations) CreateSecret(appName, secretName string, data map[string][]byte) error {
-	return nil
-}
-
 func (*fakeK8sOperations) PodList(namespace string) ([]*Pod, error) {
 	pl := []*Pod{
 		{Name: "pod 1", State: string(api.PodRunning)},
@@ -120,6 +116,22 @@ func (*fakeK8sOperations) CreateOrUpdateDeployEnvVars(namespace, name string, ev
 	return nil
 }
 
+func (*fakeK8sOperations) Events(namespace string, follow bool) (<-chan *Event, func(), error) {
+	ch := make(chan *Event, 2)
+	ch <- &Event{Type: "Normal", Reason: "Pulled", Object: "pod/pod 1"}
+	ch <- &Event{Type: "Warning", Reason: "BackOff", Object: "pod/pod 2"}
+	close(ch)
+	return ch, func() {}, nil
+}
+
+func (*fakeK8sOperations) PodMetrics(namespace string) ([]*PodMetrics, error) {
+	pm := []*PodMetrics{
+		{Name: "pod 1", CPU: 100, Memory: 64 * 1024 * 1024},
+		{Name: "pod 2", CPU: 200, Memory: 128 * 1024 * 1024},
+	}
+	return pm, nil
+}
+
 func (e *errK8sOperations) CreateNamespace(app *App, user string) error {
 	return e.NamespaceErr
 }
@@ -128,10 +140,6 @@ func (e *errK8sOperations) CreateQuota(app *App) error {
 	return e.QuotaErr
 }
 
-func (e *errK8sOperations) CreateSecret(appName, secretName string, data map[string][]byte) error {
-	return e.SecretErr
-}
-
 func (e *errK8sOperations) CreateAutoScale(app *App) error {
 	return e.AutoScaleErr
 }
@@ -188,10 +196,18 @@ func (e *errK8sOperations) CreateOrUpdateDeployEnvVars(namespace, name string, e
 	return e.Err
 }
 
+func (e *errK8sOperations) Events(namespace string, follow bool) (<-chan *Event, func(), error) {
+	return nil, nil, e.Err
+}
+
+func (e *errK8sOperations) PodMetrics(namespace string) ([]*PodMetrics, error) {
+	return nil, e.Err
+}
+
 func TestAppOperationsCreate(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
+	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -208,7 +224,7 @@ func TestAppOperationsCreate(t *testing.T) {
 func TestAppOperationsCreateErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
+	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -221,7 +237,7 @@ func TestAppOperationsCreateErrPermissionDenied(t *testing.T) {
 func TestAppOperationsCreateErrAppAlreadyExists(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
+	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -237,7 +253,7 @@ func TestAppOperationsCreateErrAppAlreadyExists(t *testing.T) {
 }
 
 func TestAppTeamName(t *testing.T) {
-	ops := NewOperations(team.NewFakeOperations(), &fakeK8sOperations{}, st.NewFake())
+	ops := NewOperations(team.NewFakeOperations(), &fakeK8sOperations{}, st.NewFake(), nil)
 	teamName, err := ops.TeamName("teresa")
 	if err != nil {
 		t.Error("got error on get teamName:", err)
@@ -248,7 +264,7 @@ func TestAppTeamName(t *testing.T) {
 }
 
 func TestAppMeta(t *testing.T) {
-	ops := NewOperations(team.NewFakeOperations(), &fakeK8sOperations{}, st.NewFake())
+	ops := NewOperations(team.NewFakeOperations(), &fakeK8sOperations{}, st.NewFake(), nil)
 	a, err := ops.Get("teresa")
 	if err != nil {
 		t.Errorf("got error on get app Meta:", err)
@@ -263,7 +279,7 @@ func TestAppOperationsHasPermission(t *testing.T) {
 	goodUserEmail := "teresa@luizalabs.com"
 
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	teamName := "luizalabs"
 	user := &storage.User{Email: goodUserEmail}
 	tops.(*team.FakeOperations).Storage[teamName] = &storage.Team{
@@ -281,7 +297,7 @@ func TestAppOperationsHasPermission(t *testing.T) {
 
 	for _, tc := range testCases {
 		u := &storage.User{Email: tc.email}
-		actual := ops.HasPermission(u, appName)
+		actual := ops.HasPermission(u, appName, team.RoleMember)
 		if tc.expected != actual {
 			t.Errorf("expected %v, got %v", tc.expected, actual)
 		}
@@ -290,7 +306,7 @@ func TestAppOperationsHasPermission(t *testing.T) {
 
 func TestAppOperationsLogs(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -325,7 +341,7 @@ func TestAppOperationsLogs(t *testing.T) {
 
 func TestAppOperationsLogsErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if _, err := ops.Logs(user, "teresa", 10, false); err != auth.ErrPermissionDenied {
@@ -335,7 +351,7 @@ func TestAppOperationsLogsErrPermissionDenied(t *testing.T) {
 
 func TestAppOperationsLogsErrNotFound(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil)
+	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if _, err := ops.Logs(user, "teresa", 10, false); err != ErrNotFound {
@@ -346,7 +362,7 @@ func TestAppOperationsLogsErrNotFound(t *testing.T) {
 func TestAppOperationsCreateErrQuota(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
+	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -361,28 +377,10 @@ func TestAppOperationsCreateErrQuota(t *testing.T) {
 	}
 }
 
-func TestAppOperationsCreateErrSecret(t *testing.T) {
-	tops := team.NewFakeOperations()
-	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
-	name := "luizalabs"
-	user := &storage.User{Email: "teresa@luizalabs.com"}
-	app := &App{Name: "teresa", Team: name}
-	tops.(*team.FakeOperations).Storage[name] = &storage.Team{
-		Name:  name,
-		Users: []storage.User{*user},
-	}
-	ops.(*AppOperations).kops = &errK8sOperations{SecretErr: errors.New("Secret Error")}
-
-	if ops.Create(user, app) == nil {
-		t.Errorf("expected error, got nil")
-	}
-}
-
 func TestAppOperationsCreateErrAutoScale(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
-	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt)
+	ops := NewOperations(tops, &fakeK8sOperations{}, fakeSt, nil)
 	name := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: name}
@@ -399,7 +397,7 @@ func TestAppOperationsCreateErrAutoScale(t *testing.T) {
 
 func TestAppOperationsInfo(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	teamName := "luizalabs"
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: teamName}
@@ -442,7 +440,7 @@ func TestAppOperationsInfo(t *testing.T) {
 
 func TestAppOperationsInfoErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if _, err := ops.Info(user, "teresa"); teresa_errors.Get(err) != auth.ErrPermissionDenied {
@@ -452,7 +450,7 @@ func TestAppOperationsInfoErrPermissionDenied(t *testing.T) {
 
 func TestAppOperationsInfoErrNotFound(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil)
+	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if _, err := ops.Info(user, "teresa"); teresa_errors.Get(err) != ErrNotFound {
@@ -462,7 +460,7 @@ func TestAppOperationsInfoErrNotFound(t *testing.T) {
 
 func TestAppOperationsSetEnv(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: "luizalabs"}
 	tops.(*team.FakeOperations).Storage[app.Name] = &storage.Team{
@@ -479,9 +477,44 @@ func TestAppOperationsSetEnv(t *testing.T) {
 	}
 }
 
+func TestAppOperationsSetEnvNotifyWebhooks(t *testing.T) {
+	tops := team.NewFakeOperations()
+	wh := webhook.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, wh)
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+	app := &App{Name: "teresa", Team: "luizalabs"}
+	tops.(*team.FakeOperations).Storage[app.Name] = &storage.Team{
+		Name:  app.Team,
+		Users: []storage.User{*user},
+	}
+	evs := []*EnvVar{
+		{Key: "key1", Value: "value1"},
+		{Key: "key2", Value: "value2"},
+	}
+
+	if err := ops.SetEnv(user, app.Name, evs); err != nil {
+		t.Fatal("error setting env vars: ", err)
+	}
+
+	events := wh.(*webhook.FakeOperations).Events
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.Type != webhook.AppEnvChanged {
+		t.Errorf("expected %s, got %s", webhook.AppEnvChanged, ev.Type)
+	}
+	if ev.Team != app.Team || ev.User != user.Email {
+		t.Errorf("expected event of team %s by %s, got %+v", app.Team, user.Email, ev)
+	}
+	if set := ev.Data["set"]; set != "key1,key2" {
+		t.Errorf("expected key1,key2, got %s", set)
+	}
+}
+
 func TestAppOperationsSetEnvErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if err := ops.SetEnv(user, "teresa", nil); err != auth.ErrPermissionDenied {
@@ -491,7 +524,7 @@ func TestAppOperationsSetEnvErrPermissionDenied(t *testing.T) {
 
 func TestAppOperationsSetEnvErrNotFound(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil)
+	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if err := ops.SetEnv(user, "teresa", nil); teresa_errors.Get(err) != ErrNotFound {
@@ -501,7 +534,7 @@ func TestAppOperationsSetEnvErrNotFound(t *testing.T) {
 
 func TestAppOperationsUnsetEnv(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: "luizalabs"}
 	tops.(*team.FakeOperations).Storage[app.Name] = &storage.Team{
@@ -517,7 +550,7 @@ func TestAppOperationsUnsetEnv(t *testing.T) {
 
 func TestAppOperationsUnsetEnvErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if err := ops.UnsetEnv(user, "teresa", nil); err != auth.ErrPermissionDenied {
@@ -527,7 +560,7 @@ func TestAppOperationsUnsetEnvErrPermissionDenied(t *testing.T) {
 
 func TestAppOperationsUnsetEnvErrNotFound(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil)
+	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 
 	if err := ops.UnsetEnv(user, "teresa", nil); teresa_errors.Get(err) != ErrNotFound {
@@ -537,7 +570,7 @@ func TestAppOperationsUnsetEnvErrNotFound(t *testing.T) {
 
 func TestAppOperationsSetEnvProtectedVar(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: "luizalabs"}
 	tops.(*team.FakeOperations).Storage[app.Name] = &storage.Team{
@@ -556,7 +589,7 @@ func TestAppOperationsSetEnvProtectedVar(t *testing.T) {
 
 func TestAppOperationsUnSetEnvProtectedVar(t *testing.T) {
 	tops := team.NewFakeOperations()
-	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
 	user := &storage.User{Email: "teresa@luizalabs.com"}
 	app := &App{Name: "teresa", Team: "luizalabs"}
 	tops.(*team.FakeOperations).Storage[app.Name] = &storage.Team{
@@ -568,3 +601,152 @@ func TestAppOperationsUnSetEnvProtectedVar(t *testing.T) {
 		t.Errorf("expected error, got nil")
 	}
 }
+
+func TestAppOperationsEvents(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
+	name := "luizalabs"
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage[name] = &storage.Team{
+		Name:  name,
+		Users: []storage.User{*user},
+	}
+
+	ch, stop, err := ops.Events(user, "teresa", false)
+	if err != nil {
+		t.Fatal("error on get events: ", err)
+	}
+	defer stop()
+
+	count := 0
+	for ev := range ch {
+		if !strings.HasPrefix(ev.Object, "pod/") {
+			t.Errorf("expected pod object, got %s", ev.Object)
+		}
+		count++
+	}
+	if count != 2 { // see fakeK8sOperations.Events
+		t.Errorf("expected 2, got %d", count)
+	}
+}
+
+func TestAppOperationsEventsErrPermissionDenied(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+
+	if _, _, err := ops.Events(user, "teresa", false); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestAppOperationsEventsErrNotFound(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &errK8sOperations{Err: ErrNotFound}, nil, nil)
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+
+	if _, _, err := ops.Events(user, "teresa", false); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAppOperationsMetrics(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
+	name := "luizalabs"
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage[name] = &storage.Team{
+		Name:  name,
+		Users: []storage.User{*user},
+	}
+
+	m, err := ops.Metrics(user, "teresa")
+	if err != nil {
+		t.Fatal("error on get metrics: ", err)
+	}
+	if len(m.Pods) != 2 { // see fakeK8sOperations.PodMetrics
+		t.Errorf("expected 2, got %d", len(m.Pods))
+	}
+	if m.Limits == nil {
+		t.Error("expected limits, got nil")
+	}
+}
+
+func TestAppOperationsMetricsErrPermissionDenied(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil, nil)
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+
+	if _, err := ops.Metrics(user, "teresa"); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestAppOperationsClearBuildCache(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, st.NewFake(), nil)
+	name := "luizalabs"
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage[name] = &storage.Team{
+		Name:  name,
+		Users: []storage.User{*user},
+	}
+
+	if err := ops.ClearBuildCache(user, "teresa"); err != nil {
+		t.Error("error clearing build cache: ", err)
+	}
+}
+
+func TestAppOperationsClearBuildCacheErrPermissionDenied(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, st.NewFake(), nil)
+	user := &storage.User{Email: "teresa@luizalabs.com"}
+
+	if err := ops.ClearBuildCache(user, "teresa"); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestBuildCachePath(t *testing.T) {
+	if p := BuildCachePath("teresa"); p != "deploys/teresa/cache.tgz" {
+		t.Errorf("expected deploys/teresa/cache.tgz, got %s", p)
+	}
+}
+
+func TestAppOperationsViewerRole(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, st.NewFake(), nil)
+	user := &storage.User{Email: "viewer@luizalabs.com"}
+	app := &App{Name: "teresa", Team: "luizalabs"}
+	tops.(*team.FakeOperations).Storage[app.Team] = &storage.Team{
+		Name:  app.Team,
+		Users: []storage.User{*user},
+	}
+	tops.(*team.FakeOperations).Roles[app.Team] = map[string]string{user.Email: team.RoleViewer}
+
+	if _, err := ops.Info(user, app.Name); err != nil {
+		t.Errorf("expected no error on Info, got %v", err)
+	}
+	rc, err := ops.Logs(user, app.Name, 10, false)
+	if err != nil {
+		t.Fatal("expected no error on Logs, got ", err)
+	}
+	rc.Close()
+
+	evs := []*EnvVar{{Key: "key1", Value: "value1"}}
+	if err := ops.SetEnv(user, app.Name, evs); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on SetEnv, got %v", err)
+	}
+	if err := ops.UnsetEnv(user, app.Name, []string{"key1"}); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on UnsetEnv, got %v", err)
+	}
+	if err := ops.Create(user, app); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on Create, got %v", err)
+	}
+	if err := ops.ClearBuildCache(user, app.Name); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on ClearBuildCache, got %v", err)
+	}
+	if ops.HasPermission(user, app.Name, team.RoleMember) {
+		t.Error("expected viewer to not have the member permission")
+	}
+}
@@ -9,4 +9,5 @@ var (
 	ErrAlreadyExists   = status.Errorf(codes.AlreadyExists, "App already exists")
 	ErrNotFound        = status.Errorf(codes.NotFound, "App not found")
 	ErrProtectedEnvVar = status.Errorf(codes.InvalidArgument, "Can't change protected env vars")
+	ErrMetricsNotFound = status.Errorf(codes.Unavailable, "Metrics API not available in the cluster")
 )
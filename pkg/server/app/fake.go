@@ -20,7 +20,7 @@ func hasPerm(email string) bool {
 	return email != "bad-user@luizalabs.com"
 }
 
-func (f *FakeOperations) HasPermission(user *storage.User, appName string) bool {
+func (f *FakeOperations) HasPermission(user *storage.User, appName, role string) bool {
 	return hasPerm(user.Email)
 }
 
@@ -124,6 +124,55 @@ func (f *FakeOperations) UnsetEnv(user *storage.User, appName string, envVars []
 	return nil
 }
 
+func (f *FakeOperations) Events(user *storage.User, appName string, follow bool) (<-chan *Event, func(), error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[appName]; !found {
+		return nil, nil, ErrNotFound
+	}
+
+	if !hasPerm(user.Email) {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+
+	ch := make(chan *Event, 2)
+	ch <- &Event{Type: "Normal", Reason: "Scheduled", Object: "pod/" + appName}
+	ch <- &Event{Type: "Warning", Reason: "BackOff", Object: "pod/" + appName}
+	close(ch)
+	return ch, func() {}, nil
+}
+
+func (f *FakeOperations) Metrics(user *storage.User, appName string) (*Metrics, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if !hasPerm(user.Email) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	if _, found := f.Storage[appName]; !found {
+		return nil, ErrNotFound
+	}
+
+	return &Metrics{Pods: []*PodMetrics{{Name: appName, CPU: 100, Memory: 1024}}}, nil
+}
+
+func (f *FakeOperations) ClearBuildCache(user *storage.User, appName string) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if !hasPerm(user.Email) {
+		return auth.ErrPermissionDenied
+	}
+
+	if _, found := f.Storage[appName]; !found {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 func NewFakeOperations() Operations {
 	return &FakeOperations{
 		mutex:   &sync.RWMutex{},
@@ -6,6 +6,7 @@ import (
 
 	"github.com/luizalabs/teresa-api/models/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
 )
 
@@ -195,7 +196,7 @@ func TestFakeHasPermission(t *testing.T) {
 	fake := NewFakeOperations()
 
 	for _, tc := range testCases {
-		actual := fake.HasPermission(&storage.User{Email: tc.email}, "teresa")
+		actual := fake.HasPermission(&storage.User{Email: tc.email}, "teresa", team.RoleMember)
 		if actual != tc.expected {
 			t.Errorf("expected %v, got %v", tc.expected, actual)
 		}
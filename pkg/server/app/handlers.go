@@ -92,6 +92,52 @@ func (s *Service) UnsetEnv(ctx context.Context, req *appb.UnsetEnvRequest) (*app
 	return &appb.Empty{}, nil
 }
 
+func (s *Service) Events(req *appb.EventsRequest, stream appb.App_EventsServer) error {
+	ctx := stream.Context()
+	user := ctx.Value("user").(*storage.User)
+
+	ch, stop, err := s.ops.Events(user, req.Name, req.Follow)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(newEventsResponse(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Service) Metrics(ctx context.Context, req *appb.MetricsRequest) (*appb.MetricsResponse, error) {
+	user := ctx.Value("user").(*storage.User)
+
+	m, err := s.ops.Metrics(user, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsResponse(m), nil
+}
+
+func (s *Service) ClearBuildCache(ctx context.Context, req *appb.ClearBuildCacheRequest) (*appb.Empty, error) {
+	user := ctx.Value("user").(*storage.User)
+
+	if err := s.ops.ClearBuildCache(user, req.Name); err != nil {
+		return nil, err
+	}
+
+	return &appb.Empty{}, nil
+}
+
 func (s *Service) RegisterService(grpcServer *grpc.Server) {
 	appb.RegisterAppServer(grpcServer, s)
 }
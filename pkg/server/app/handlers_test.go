@@ -28,6 +28,21 @@ func (lsw *LogsStreamWrapper) Send(msg *appb.LogsResponse) error {
 	return nil
 }
 
+type EventsStreamWrapper struct {
+	appb.App_EventsServer
+	ctx    context.Context
+	events []*appb.EventsResponse
+}
+
+func (esw *EventsStreamWrapper) Context() context.Context {
+	return esw.ctx
+}
+
+func (esw *EventsStreamWrapper) Send(msg *appb.EventsResponse) error {
+	esw.events = append(esw.events, msg)
+	return nil
+}
+
 func TestCreateSuccess(t *testing.T) {
 	fake := NewFakeOperations()
 	user := &storage.User{Email: "gopher@luizalabs.com"}
@@ -231,3 +246,83 @@ func TestUnsetEnvPermissionDenied(t *testing.T) {
 		t.Errorf("expected ErrPermissionDenied, got %v", err)
 	}
 }
+
+func TestEventsSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+
+	name := "teresa"
+	fake.(*FakeOperations).Storage[name] = &App{Name: name}
+	s := NewService(fake)
+
+	ctx := context.WithValue(context.Background(), "user", user)
+	req := &appb.EventsRequest{Name: name}
+
+	wrap := &EventsStreamWrapper{ctx: ctx}
+	if err := s.Events(req, wrap); err != nil {
+		t.Fatal("error getting events:", err)
+	}
+	if len(wrap.events) != 2 { // see FakeOperations.Events
+		t.Errorf("expected 2, got %d", len(wrap.events))
+	}
+}
+
+func TestEventsPermissionDenied(t *testing.T) {
+	fake := NewFakeOperations()
+	user := &storage.User{Email: "bad-user@luizalabs.com"}
+
+	name := "teresa"
+	fake.(*FakeOperations).Storage[name] = &App{Name: name}
+	s := NewService(fake)
+
+	ctx := context.WithValue(context.Background(), "user", user)
+	req := &appb.EventsRequest{Name: name}
+
+	wrap := &EventsStreamWrapper{ctx: ctx}
+	if err := s.Events(req, wrap); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestMetricsSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	name := "teresa"
+	fake.(*FakeOperations).Storage[name] = &App{Name: name}
+	s := NewService(fake)
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	resp, err := s.Metrics(ctx, &appb.MetricsRequest{Name: name})
+	if err != nil {
+		t.Fatal("Got error on metrics: ", err)
+	}
+	if len(resp.Pods) != 1 { // see FakeOperations.Metrics
+		t.Errorf("expected 1, got %d", len(resp.Pods))
+	}
+}
+
+func TestMetricsPermissionDenied(t *testing.T) {
+	fake := NewFakeOperations()
+	name := "teresa"
+	fake.(*FakeOperations).Storage[name] = &App{Name: name}
+	s := NewService(fake)
+	user := &storage.User{Email: "bad-user@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	if _, err := s.Metrics(ctx, &appb.MetricsRequest{Name: name}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestClearBuildCacheSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	name := "teresa"
+	fake.(*FakeOperations).Storage[name] = &App{Name: name}
+	s := NewService(fake)
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	if _, err := s.ClearBuildCache(ctx, &appb.ClearBuildCacheRequest{Name: name}); err != nil {
+		t.Error("Got error on clear build cache: ", err)
+	}
+}
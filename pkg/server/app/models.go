@@ -1,7 +1,11 @@
 package app
 
 import (
+	"time"
+
 	appb "github.com/luizalabs/teresa-api/pkg/protobuf/app"
+
+	"k8s.io/client-go/pkg/api/resource"
 )
 
 const (
@@ -43,8 +47,15 @@ type Pod struct {
 	State string
 }
 
+type Port struct {
+	Name     string
+	Port     int32
+	Protocol string
+}
+
 type Address struct {
 	Hostname string
+	Ports    []*Port
 }
 
 type Status struct {
@@ -52,6 +63,26 @@ type Status struct {
 	Pods []*Pod
 }
 
+type Event struct {
+	Type     string
+	Reason   string
+	Object   string
+	Message  string
+	Count    int32
+	LastSeen time.Time
+}
+
+type PodMetrics struct {
+	Name   string
+	CPU    int64 // millicores
+	Memory int64 // bytes
+}
+
+type Metrics struct {
+	Pods   []*PodMetrics
+	Limits *Limits
+}
+
 type Info struct {
 	Team      string
 	Addresses []*Address
@@ -134,6 +165,13 @@ func newInfoResponse(info *Info) *appb.InfoResponse {
 			continue
 		}
 		addr := &appb.InfoResponse_Address{Hostname: item.Hostname}
+		for _, p := range item.Ports {
+			addr.Ports = append(addr.Ports, &appb.InfoResponse_Address_Port{
+				Name:     p.Name,
+				Port:     p.Port,
+				Protocol: p.Protocol,
+			})
+		}
 		addrs = append(addrs, addr)
 	}
 
@@ -197,6 +235,98 @@ func newInfoResponse(info *Info) *appb.InfoResponse {
 	}
 }
 
+func newEventsResponse(ev *Event) *appb.EventsResponse {
+	var lastSeen string
+	if !ev.LastSeen.IsZero() {
+		lastSeen = ev.LastSeen.Format(time.RFC3339)
+	}
+	return &appb.EventsResponse{
+		Type:     ev.Type,
+		Reason:   ev.Reason,
+		Object:   ev.Object,
+		Message:  ev.Message,
+		Count:    ev.Count,
+		LastSeen: lastSeen,
+	}
+}
+
+func limitQuantity(lim *Limits, res string) *resource.Quantity {
+	if lim == nil {
+		return nil
+	}
+	for _, item := range lim.Default {
+		if item == nil || item.Resource != res {
+			continue
+		}
+		q, err := resource.ParseQuantity(item.Quantity)
+		if err != nil {
+			return nil
+		}
+		return &q
+	}
+	return nil
+}
+
+func usagePercentage(usage, limit int64) int32 {
+	if limit <= 0 {
+		return 0
+	}
+	return int32(usage * 100 / limit)
+}
+
+func newMetricsResponse(m *Metrics) *appb.MetricsResponse {
+	if m == nil {
+		return nil
+	}
+
+	cpuLim := limitQuantity(m.Limits, "cpu")
+	memLim := limitQuantity(m.Limits, "memory")
+
+	pods := []*appb.MetricsResponse_Pod{}
+	for _, item := range m.Pods {
+		if item == nil {
+			continue
+		}
+		pod := &appb.MetricsResponse_Pod{
+			Name:   item.Name,
+			Cpu:    resource.NewMilliQuantity(item.CPU, resource.DecimalSI).String(),
+			Memory: resource.NewQuantity(item.Memory, resource.BinarySI).String(),
+		}
+		if cpuLim != nil {
+			pod.CpuLimitPercentage = usagePercentage(item.CPU, cpuLim.MilliValue())
+		}
+		if memLim != nil {
+			pod.MemoryLimitPercentage = usagePercentage(item.Memory, memLim.Value())
+		}
+		pods = append(pods, pod)
+	}
+
+	var lim *appb.MetricsResponse_Limits
+	if m.Limits != nil {
+		lim = &appb.MetricsResponse_Limits{
+			Default:        newMetricsResponseLrq(m.Limits.Default),
+			DefaultRequest: newMetricsResponseLrq(m.Limits.DefaultRequest),
+		}
+	}
+
+	return &appb.MetricsResponse{Pods: pods, Limits: lim}
+}
+
+func newMetricsResponseLrq(s []*LimitRangeQuantity) []*appb.MetricsResponse_Limits_LimitRangeQuantity {
+	var t []*appb.MetricsResponse_Limits_LimitRangeQuantity
+	for _, tmp := range s {
+		if tmp == nil {
+			continue
+		}
+		lrq := &appb.MetricsResponse_Limits_LimitRangeQuantity{
+			Quantity: tmp.Quantity,
+			Resource: tmp.Resource,
+		}
+		t = append(t, lrq)
+	}
+	return t
+}
+
 func newEnvVars(req *appb.SetEnvRequest) []*EnvVar {
 	tmp := []*EnvVar{}
 	for _, ev := range req.EnvVars {
@@ -154,6 +154,41 @@ func TestNewInfoResponse(t *testing.T) {
 	}
 }
 
+func TestNewMetricsResponse(t *testing.T) {
+	m := &Metrics{
+		Pods: []*PodMetrics{
+			{Name: "pod 1", CPU: 250, Memory: 256 * 1024 * 1024},
+		},
+		Limits: &Limits{
+			Default: []*LimitRangeQuantity{
+				{Quantity: "500m", Resource: "cpu"},
+				{Quantity: "1Gi", Resource: "memory"},
+			},
+		},
+	}
+
+	resp := newMetricsResponse(m)
+	if len(resp.Pods) != 1 {
+		t.Fatalf("expected 1 pod, got %d", len(resp.Pods))
+	}
+	pod := resp.Pods[0]
+	if pod.Cpu != "250m" {
+		t.Errorf("expected 250m, got %s", pod.Cpu)
+	}
+	if pod.Memory != "256Mi" {
+		t.Errorf("expected 256Mi, got %s", pod.Memory)
+	}
+	if pod.CpuLimitPercentage != 50 {
+		t.Errorf("expected 50, got %d", pod.CpuLimitPercentage)
+	}
+	if pod.MemoryLimitPercentage != 25 {
+		t.Errorf("expected 25, got %d", pod.MemoryLimitPercentage)
+	}
+	if len(resp.Limits.Default) != 2 {
+		t.Errorf("expected 2 default limits, got %d", len(resp.Limits.Default))
+	}
+}
+
 func TestSetEnvVars(t *testing.T) {
 	app := &App{Name: "teresa", Team: "luizalabs"}
 	var testCases = []struct {
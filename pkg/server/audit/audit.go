@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/app"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/pkg/errors"
+)
+
+const defaultLimit = 50
+
+type Operations interface {
+	Record(entry *storage.AuditEntry) error
+	List(user *storage.User, f *Filter) ([]*storage.AuditEntry, error)
+}
+
+type K8sOperations interface {
+	NamespaceLabel(namespace, label string) (string, error)
+}
+
+// Filter selects the audit entries, the empty fields match everything.
+type Filter struct {
+	User   string
+	Team   string
+	App    string
+	Method string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+type DatabaseOperations struct {
+	DB   *gorm.DB
+	tops team.Operations
+	kops K8sOperations
+}
+
+// Record saves the entry, filling its team from the app when missing.
+func (ops *DatabaseOperations) Record(entry *storage.AuditEntry) error {
+	if entry.App != "" && entry.Team == "" {
+		if teamName, err := ops.kops.NamespaceLabel(entry.App, app.TeresaTeamLabel); err == nil {
+			entry.Team = teamName
+		}
+	}
+
+	if err := ops.DB.Create(entry).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("saving audit entry of %s", entry.Method)),
+		)
+	}
+	return nil
+}
+
+// List returns the entries matching the filter, newest first. Admins see
+// every entry and the owners of teams only the entries of their teams.
+func (ops *DatabaseOperations) List(user *storage.User, f *Filter) ([]*storage.AuditEntry, error) {
+	q := ops.DB
+	if !user.IsAdmin {
+		teams, err := ops.ownedTeams(user)
+		if err != nil {
+			return nil, err
+		}
+		if len(teams) == 0 {
+			return nil, auth.ErrPermissionDenied
+		}
+		q = q.Where("team in (?)", teams)
+	}
+
+	q = q.Where(&storage.AuditEntry{User: f.User, Team: f.Team, App: f.App, Method: f.Method})
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at <= ?", f.Until)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var entries []*storage.AuditEntry
+	if err := q.Order("id desc").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "finding audit entries"),
+		)
+	}
+	return entries, nil
+}
+
+func (ops *DatabaseOperations) ownedTeams(user *storage.User) ([]string, error) {
+	teams, err := ops.tops.ListByUser(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range teams {
+		role, err := ops.tops.Role(t.Name, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		if team.RoleAllows(role, team.RoleOwner) {
+			names = append(names, t.Name)
+		}
+	}
+	return names, nil
+}
+
+func NewDatabaseOperations(db *gorm.DB, tops team.Operations, kops K8sOperations) Operations {
+	db.AutoMigrate(&storage.AuditEntry{})
+	return &DatabaseOperations{DB: db, tops: tops, kops: kops}
+}
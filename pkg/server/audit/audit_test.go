@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	apppb "github.com/luizalabs/teresa-api/pkg/protobuf/app"
+	deploypb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
+	tokenpb "github.com/luizalabs/teresa-api/pkg/protobuf/token"
+	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+)
+
+type fakeK8sOperations struct{}
+
+func (f *fakeK8sOperations) NamespaceLabel(namespace, label string) (string, error) {
+	if namespace == "teresa" {
+		return "luizalabs", nil
+	}
+	return "", errors.New("not found")
+}
+
+func newTestOperations(t *testing.T) (*DatabaseOperations, func()) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+
+	tops := team.NewFakeOperations()
+	for _, name := range []string{"luizalabs", "gophers"} {
+		tops.(*team.FakeOperations).Storage[name] = &storage.Team{
+			Name:  name,
+			Users: []storage.User{{Email: "owner@luizalabs.com"}, {Email: "member@luizalabs.com"}},
+		}
+	}
+	tops.(*team.FakeOperations).Roles["luizalabs"] = map[string]string{"owner@luizalabs.com": team.RoleOwner}
+
+	ops := NewDatabaseOperations(db, tops, &fakeK8sOperations{}).(*DatabaseOperations)
+	return ops, func() { db.Close() }
+}
+
+func TestMethodName(t *testing.T) {
+	var testCases = []struct {
+		fullMethod string
+		expected   string
+	}{
+		{"/app.App/SetEnv", "App.SetEnv"},
+		{"/deploy.Deploy/Make", "Deploy.Make"},
+		{"Test", "Test"},
+	}
+
+	for _, tc := range testCases {
+		if actual := MethodName(tc.fullMethod); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestIsAudited(t *testing.T) {
+	var testCases = []struct {
+		fullMethod string
+		expected   bool
+	}{
+		{"/app.App/SetEnv", true},
+		{"/deploy.Deploy/Make", true},
+		{"/team.Team/AddUser", true},
+		{"/app.App/Logs", false},
+		{"/team.Team/List", false},
+		{"/user.User/Login", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := IsAudited(tc.fullMethod); actual != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.fullMethod, actual)
+		}
+	}
+}
+
+func TestIsAuditedFailure(t *testing.T) {
+	var testCases = []struct {
+		fullMethod string
+		expected   bool
+	}{
+		{"/app.App/SetEnv", true},
+		{"/user.User/Login", true},
+		{"/oidc.OIDC/Login", true},
+		{"/app.App/Logs", false},
+		{"/user.User/Refresh", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := IsAuditedFailure(tc.fullMethod); actual != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.fullMethod, actual)
+		}
+	}
+}
+
+func TestNewEntryFailedLogin(t *testing.T) {
+	req := &userpb.LoginRequest{Email: "gopher@luizalabs.com", Password: "s3cr3t"}
+
+	e := NewEntry(nil, "/user.User/Login", req, auth.ErrPermissionDenied)
+	if e.Method != "User.Login" || e.User != req.Email || e.Success {
+		t.Errorf("got unexpected entry %+v", e)
+	}
+	if strings.Contains(e.Params, "s3cr3t") {
+		t.Errorf("expected the password masked, got %s", e.Params)
+	}
+}
+
+func TestNewEntryMasksSecrets(t *testing.T) {
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	req := &apppb.SetEnvRequest{
+		Name:    "teresa",
+		EnvVars: []*apppb.SetEnvRequest_EnvVar{{Key: "DB_PASSWORD", Value: "s3cr3t"}},
+	}
+
+	e := NewEntry(u, "/app.App/SetEnv", req, nil)
+	if e.Method != "App.SetEnv" || e.App != "teresa" || e.User != u.Email || !e.Success {
+		t.Errorf("got unexpected entry %+v", e)
+	}
+
+	var params struct {
+		EnvVars []map[string]string `json:"env_vars"`
+	}
+	if err := json.Unmarshal([]byte(e.Params), &params); err != nil {
+		t.Fatal("error decoding params: ", err)
+	}
+	if len(params.EnvVars) != 1 {
+		t.Fatalf("expected 1 env var, got %d", len(params.EnvVars))
+	}
+	if params.EnvVars[0]["key"] != "DB_PASSWORD" {
+		t.Errorf("expected DB_PASSWORD, got %s", params.EnvVars[0]["key"])
+	}
+	if params.EnvVars[0]["value"] != maskedValue {
+		t.Errorf("expected the value masked, got %s", params.EnvVars[0]["value"])
+	}
+
+	e = NewEntry(u, "/user.User/SetPassword", &userpb.SetPasswordRequest{Password: "s3cr3t"}, auth.ErrPermissionDenied)
+	if e.Params != `{"password":"***"}` {
+		t.Errorf("expected the password masked, got %s", e.Params)
+	}
+	if e.Success || e.Error != "Permission Denied" {
+		t.Errorf("expected the error message, got %v %s", e.Success, e.Error)
+	}
+}
+
+func TestNewEntryDeployTarget(t *testing.T) {
+	req := &deploypb.DeployRequest{
+		Value: &deploypb.DeployRequest_Info_{
+			Info: &deploypb.DeployRequest_Info{App: "teresa", Description: "release 1.0"},
+		},
+	}
+
+	e := NewEntry(&storage.User{}, "/deploy.Deploy/Make", req, nil)
+	if e.App != "teresa" {
+		t.Errorf("expected teresa, got %s", e.App)
+	}
+
+	e = NewEntry(&storage.User{}, "/deploy.Deploy/Promote", &deploypb.PromoteRequest{FromApp: "a", ToApp: "b"}, nil)
+	if e.App != "b" {
+		t.Errorf("expected b, got %s", e.App)
+	}
+}
+
+func TestNewEntryTokenTarget(t *testing.T) {
+	e := NewEntry(&storage.User{}, "/token.Token/Create", &tokenpb.CreateRequest{Name: "ci"}, nil)
+	if e.Target != "ci" {
+		t.Errorf("expected ci, got %s", e.Target)
+	}
+
+	e = NewEntry(&storage.User{}, "/token.Token/Revoke", &tokenpb.RevokeRequest{Id: 42}, nil)
+	if e.Target != "42" {
+		t.Errorf("expected 42, got %s", e.Target)
+	}
+}
+
+func TestDatabaseOperationsRecordFillsTeam(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	entry := &storage.AuditEntry{User: "gopher@luizalabs.com", Method: "App.SetEnv", App: "teresa"}
+	if err := ops.Record(entry); err != nil {
+		t.Fatal("error recording entry: ", err)
+	}
+	if entry.Team != "luizalabs" {
+		t.Errorf("expected luizalabs, got %s", entry.Team)
+	}
+}
+
+func TestDatabaseOperationsList(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	entries := []*storage.AuditEntry{
+		{User: "owner@luizalabs.com", Method: "App.SetEnv", Team: "luizalabs", App: "teresa"},
+		{User: "member@luizalabs.com", Method: "Deploy.Make", Team: "luizalabs", App: "teresa"},
+		{User: "member@luizalabs.com", Method: "Deploy.Make", Team: "gophers", App: "gopher"},
+	}
+	for _, e := range entries {
+		if err := ops.Record(e); err != nil {
+			t.Fatal("error recording entry: ", err)
+		}
+	}
+
+	var testCases = []struct {
+		user     *storage.User
+		filter   *Filter
+		expected int
+	}{
+		{&storage.User{IsAdmin: true}, &Filter{}, 3},
+		{&storage.User{IsAdmin: true}, &Filter{Method: "Deploy.Make"}, 2},
+		{&storage.User{IsAdmin: true}, &Filter{User: "member@luizalabs.com", Team: "gophers"}, 1},
+		{&storage.User{IsAdmin: true}, &Filter{Limit: 1}, 1},
+		{&storage.User{IsAdmin: true}, &Filter{Since: time.Now().Add(time.Hour)}, 0},
+		{&storage.User{Email: "owner@luizalabs.com"}, &Filter{}, 2},
+		{&storage.User{Email: "owner@luizalabs.com"}, &Filter{Team: "gophers"}, 0},
+	}
+
+	for _, tc := range testCases {
+		actual, err := ops.List(tc.user, tc.filter)
+		if err != nil {
+			t.Fatal("error listing entries: ", err)
+		}
+		if len(actual) != tc.expected {
+			t.Errorf("expected %d entries for %+v, got %d", tc.expected, tc.filter, len(actual))
+		}
+	}
+}
+
+func TestDatabaseOperationsListErrPermissionDenied(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	if _, err := ops.List(&storage.User{Email: "member@luizalabs.com"}, &Filter{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
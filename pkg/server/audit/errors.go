@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidTime = status.Errorf(codes.InvalidArgument, "Invalid time, use the RFC3339 format")
+)
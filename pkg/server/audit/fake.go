@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"sync"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+)
+
+type FakeOperations struct {
+	mutex   *sync.RWMutex
+	Entries []*storage.AuditEntry
+}
+
+func (f *FakeOperations) Record(entry *storage.AuditEntry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.Entries = append(f.Entries, entry)
+	return nil
+}
+
+func (f *FakeOperations) List(user *storage.User, filter *Filter) ([]*storage.AuditEntry, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if !user.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	var entries []*storage.AuditEntry
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		e := f.Entries[i]
+		if filter.User != "" && e.User != filter.User ||
+			filter.Team != "" && e.Team != filter.Team ||
+			filter.App != "" && e.App != filter.App ||
+			filter.Method != "" && e.Method != filter.Method {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func NewFakeOperations() Operations {
+	return &FakeOperations{mutex: &sync.RWMutex{}}
+}
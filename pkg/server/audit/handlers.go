@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	auditpb "github.com/luizalabs/teresa-api/pkg/protobuf/audit"
+	"google.golang.org/grpc"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) List(ctx context.Context, request *auditpb.ListRequest) (*auditpb.ListResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	f := &Filter{
+		User:   request.User,
+		Team:   request.Team,
+		App:    request.App,
+		Method: request.Method,
+		Limit:  int(request.Limit),
+	}
+	var err error
+	if f.Since, err = parseTime(request.Since); err != nil {
+		return nil, err
+	}
+	if f.Until, err = parseTime(request.Until); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ops.List(u, f)
+	if err != nil {
+		return nil, err
+	}
+	return newListResponse(entries), nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidTime
+	}
+	return t, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	auditpb.RegisterAuditServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
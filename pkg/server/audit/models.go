@@ -0,0 +1,223 @@
+package audit
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	auditpb "github.com/luizalabs/teresa-api/pkg/protobuf/audit"
+	"google.golang.org/grpc/status"
+)
+
+const maskedValue = "***"
+
+// readOnlyMethods are the rpcs that change nothing and are not audited,
+// every other rpc is.
+var readOnlyMethods = map[string]bool{
+	"App.Logs":           true,
+	"App.Info":           true,
+	"App.Events":         true,
+	"App.Metrics":        true,
+	"Team.List":          true,
+	"Webhook.List":       true,
+	"Webhook.Deliveries": true,
+	"Audit.List":         true,
+	"Token.List":         true,
+	"User.Login":         true,
+	"User.Refresh":       true,
+	"OIDC.Config":        true,
+	"OIDC.Login":         true,
+}
+
+// loginMethods are the read-only rpcs whose failed calls are audited, with
+// the e-mail of the request as the user.
+var loginMethods = map[string]bool{
+	"User.Login": true,
+	"OIDC.Login": true,
+}
+
+// sensitiveFields are the request fields whose values are masked: env var
+// values, passwords, secrets and deploy file chunks.
+var sensitiveFields = map[string]bool{
+	"value":         true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"refresh_token": true,
+	"code":          true,
+	"totp_code":     true,
+	"chunk":         true,
+}
+
+type targetField struct {
+	app    []string
+	team   []string
+	target []string
+}
+
+// targetFields are the request fields naming the app and the team targeted
+// by the rpcs of each service, or the other resource they target.
+var targetFields = map[string]targetField{
+	"App":     {app: []string{"name"}, team: []string{"team"}},
+	"Deploy":  {app: []string{"app", "to_app"}},
+	"GitPush": {app: []string{"app"}},
+	"Team":    {team: []string{"name"}},
+	"Token":   {target: []string{"name", "id"}},
+	"Webhook": {app: []string{"app"}, team: []string{"team"}},
+}
+
+// MethodName turns the full method of a grpc call, like /app.App/SetEnv,
+// into Service.Method, like App.SetEnv.
+func MethodName(fullMethod string) string {
+	parts := strings.Split(strings.TrimPrefix(fullMethod, "/"), "/")
+	if len(parts) != 2 {
+		return fullMethod
+	}
+	service := parts[0]
+	if idx := strings.LastIndex(service, "."); idx >= 0 {
+		service = service[idx+1:]
+	}
+	return service + "." + parts[1]
+}
+
+// IsAudited reports whether the calls of the rpc are recorded.
+func IsAudited(fullMethod string) bool {
+	return !readOnlyMethods[MethodName(fullMethod)]
+}
+
+// IsAuditedFailure reports whether the failed calls of the rpc are recorded.
+func IsAuditedFailure(fullMethod string) bool {
+	return IsAudited(fullMethod) || loginMethods[MethodName(fullMethod)]
+}
+
+// NewEntry builds the audit entry of a call of the rpc by the user with
+// the request req and its result err.
+func NewEntry(user *storage.User, fullMethod string, req interface{}, err error) *storage.AuditEntry {
+	method := MethodName(fullMethod)
+	entry := &storage.AuditEntry{Method: method, Success: err == nil}
+	if user != nil {
+		entry.User = user.Email
+	}
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			entry.Error = s.Message()
+		} else {
+			entry.Error = err.Error()
+		}
+	}
+
+	params := sanitize(req)
+	if params == nil {
+		return entry
+	}
+	if b, err := json.Marshal(params); err == nil {
+		entry.Params = string(b)
+	}
+
+	if entry.User == "" && loginMethods[method] {
+		entry.User = findField(params, []string{"email"})
+	}
+	entry.App, entry.Team = target(method, params)
+	entry.Target = findField(params, targetFields[service(method)].target)
+	return entry
+}
+
+// Target returns the app and the team targeted by a call of the rpc with
+// the request req, empty when the request names none.
+func Target(fullMethod string, req interface{}) (app, team string) {
+	params := sanitize(req)
+	if params == nil {
+		return "", ""
+	}
+	return target(MethodName(fullMethod), params)
+}
+
+func target(method string, params map[string]interface{}) (app, team string) {
+	t := targetFields[service(method)]
+	return findField(params, t.app), findField(params, t.team)
+}
+
+func service(method string) string {
+	return strings.Split(method, ".")[0]
+}
+
+// sanitize returns the request as a JSON object with the sensitive fields
+// masked.
+func sanitize(req interface{}) map[string]interface{} {
+	if req == nil {
+		return nil
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	params := make(map[string]interface{})
+	if err := json.Unmarshal(b, &params); err != nil {
+		return nil
+	}
+	mask(params)
+	return params
+}
+
+func mask(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			if sensitiveFields[k] {
+				t[k] = maskedValue
+				continue
+			}
+			mask(item)
+		}
+	case []interface{}:
+		for _, item := range t {
+			mask(item)
+		}
+	}
+}
+
+// findField returns the first non empty string (or non zero number) value
+// of one of the fields, looking into the nested objects (like the oneof of
+// the deploy request).
+func findField(params map[string]interface{}, fields []string) string {
+	for _, f := range fields {
+		switch v := params[f].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			if v != 0 {
+				return strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+	}
+	for _, v := range params {
+		if m, ok := v.(map[string]interface{}); ok {
+			if s := findField(m, fields); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func newListResponse(entries []*storage.AuditEntry) *auditpb.ListResponse {
+	resp := &auditpb.ListResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &auditpb.ListResponse_Entry{
+			User:      e.User,
+			Method:    e.Method,
+			Team:      e.Team,
+			App:       e.App,
+			Target:    e.Target,
+			Params:    e.Params,
+			Success:   e.Success,
+			Error:     e.Error,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
@@ -1,49 +1,169 @@
 package auth
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
+	"encoding/hex"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+	tokenIDSize      = 16
+)
+
 type Auth interface {
-	GenerateToken(email string) (string, error)
-	ValidateToken(token string) (string, error)
+	GenerateToken(email string, version int) (string, error)
+	GenerateRefreshToken(email string, version int) (string, error)
+	ValidateToken(token string) (*Claims, error)
+	ValidateRefreshToken(token string) (*Claims, error)
+}
+
+// Options are the lifetimes of the tokens, read from the TERESA_AUTH_*
+// env vars.
+type Options struct {
+	TokenExpiration        time.Duration `split_words:"true" default:"1h"`
+	RefreshTokenExpiration time.Duration `split_words:"true" default:"336h"`
+	// KeysDir is a directory of key pairs used instead of the single key
+	// pair of the secrets, see KeyDir.
+	KeysDir            string        `split_words:"true"`
+	KeysReloadInterval time.Duration `split_words:"true" default:"1m"`
+}
+
+// Claims are the fields of a valid token: Version must match the token
+// version of the user and ID is used to revoke a single token.
+type Claims struct {
+	Email     string
+	ID        string
+	Version   int
+	ExpiresAt time.Time
 }
 
 type tokenClaim struct {
-	Email string `json:"email"`
+	Email   string `json:"email"`
+	Type    string `json:"typ"`
+	Version int    `json:"ver"`
 	jwt.StandardClaims
 }
 
 type JWTAuth struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	keys KeySource
+	opts *Options
+}
+
+func (a *JWTAuth) GenerateToken(email string, version int) (string, error) {
+	return a.generate(email, version, accessTokenType, a.opts.TokenExpiration)
+}
+
+func (a *JWTAuth) GenerateRefreshToken(email string, version int) (string, error) {
+	return a.generate(email, version, refreshTokenType, a.opts.RefreshTokenExpiration)
+}
+
+func (a *JWTAuth) generate(email string, version int, typ string, exp time.Duration) (string, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &tokenClaim{
+		Email:   email,
+		Type:    typ,
+		Version: version,
+		StandardClaims: jwt.StandardClaims{
+			Id:        id,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(exp).Unix(),
+		},
+	}
+	keys := a.keys.KeySet()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if keys.SigningKeyID != "" {
+		token.Header["kid"] = keys.SigningKeyID
+	}
+	return token.SignedString(keys.SigningKey)
 }
 
-func (a *JWTAuth) GenerateToken(email string) (string, error) {
-	jwtClaims := jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(time.Hour * 24 * 14).Unix()}
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwtClaims)
-	return token.SignedString(a.privateKey)
+func (a *JWTAuth) ValidateToken(token string) (*Claims, error) {
+	return a.validate(token, accessTokenType)
 }
 
-func (a *JWTAuth) ValidateToken(token string) (string, error) {
-	parsedToken, err := jwt.ParseWithClaims(token, &tokenClaim{}, func(*jwt.Token) (interface{}, error) {
-		return a.publicKey, nil
+func (a *JWTAuth) ValidateRefreshToken(token string) (*Claims, error) {
+	return a.validate(token, refreshTokenType)
+}
+
+func (a *JWTAuth) validate(token, typ string) (*Claims, error) {
+	claims := new(tokenClaim)
+	if err := a.parse(token, claims); err != nil || claims.Type != typ {
+		return nil, ErrPermissionDenied
+	}
+	return &Claims{
+		Email:     claims.Email,
+		ID:        claims.Id,
+		Version:   claims.Version,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// parse validates the token with the public key of its kid; tokens without
+// kid (signed before the keys had ids) are validated against all the keys.
+func (a *JWTAuth) parse(token string, claims *tokenClaim) error {
+	keys := a.keys.KeySet()
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrPermissionDenied
+		}
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errNoKeyID
+		}
+		key, found := keys.PublicKeys[kid]
+		if !found {
+			return nil, ErrPermissionDenied
+		}
+		return key, nil
 	})
-	if err != nil || !parsedToken.Valid {
-		return "", ErrPermissionDenied
+	if vErr, ok := err.(*jwt.ValidationError); !ok || vErr.Inner != errNoKeyID {
+		return err
+	}
+	for _, key := range keys.PublicKeys {
+		_, err = jwt.ParseWithClaims(token, claims, rsaKeyFunc(key))
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func rsaKeyFunc(key *rsa.PublicKey) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrPermissionDenied
+		}
+		return key, nil
 	}
-	claims, ok := parsedToken.Claims.(*tokenClaim)
-	if !ok {
-		return "", ErrPermissionDenied
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, tokenIDSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// New returns an Auth using a single key pair, its tokens have no kid.
+func New(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, opts *Options) Auth {
+	keys := &KeySet{
+		SigningKey: privateKey,
+		PublicKeys: map[string]*rsa.PublicKey{"": publicKey},
 	}
-	return claims.Email, nil
+	return NewWithKeys(&staticKeys{keys}, opts)
 }
 
-func New(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Auth {
-	return &JWTAuth{privateKey: privateKey, publicKey: publicKey}
+// NewWithKeys returns an Auth using the keys of the source, like a KeyDir.
+func NewWithKeys(keys KeySource, opts *Options) Auth {
+	return &JWTAuth{keys: keys, opts: opts}
 }
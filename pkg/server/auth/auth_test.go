@@ -4,16 +4,18 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"testing"
+	"time"
 )
 
 var (
 	privateKey, _ = rsa.GenerateKey(rand.Reader, 1024)
 	publicKey     = &privateKey.PublicKey
+	opts          = &Options{TokenExpiration: time.Hour, RefreshTokenExpiration: 24 * time.Hour}
 )
 
 func TestJWTAuthGenerateToken(t *testing.T) {
-	a := New(privateKey, publicKey)
-	token, err := a.GenerateToken("gopher@luizalabs.com")
+	a := New(privateKey, publicKey, opts)
+	token, err := a.GenerateToken("gopher@luizalabs.com", 0)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
@@ -23,27 +25,67 @@ func TestJWTAuthGenerateToken(t *testing.T) {
 }
 
 func TestJWTAuthValidateTokenSuccess(t *testing.T) {
-	a := New(privateKey, publicKey)
-
+	a := New(privateKey, publicKey, opts)
 	expectedEmail := "gopher@luizalabs.com"
-	token, err := a.GenerateToken(expectedEmail)
+	expectedVersion := 2
+	token, err := a.GenerateToken(expectedEmail, expectedVersion)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
-
-	email, err := a.ValidateToken(token)
+	claims, err := a.ValidateToken(token)
 	if err != nil {
 		t.Fatal("error on validate token: ", err)
 	}
-
-	if email != expectedEmail {
-		t.Errorf("expected %s, got %s", expectedEmail, email)
+	if claims.Email != expectedEmail {
+		t.Errorf("expected %s, got %s", expectedEmail, claims.Email)
+	}
+	if claims.Version != expectedVersion {
+		t.Errorf("expected version %d, got %d", expectedVersion, claims.Version)
+	}
+	if claims.ID == "" {
+		t.Error("expected a token id")
+	}
+	if claims.ExpiresAt.After(time.Now().Add(opts.TokenExpiration)) {
+		t.Errorf("expected the token to expire in %v, got %v", opts.TokenExpiration, claims.ExpiresAt)
 	}
 }
 
 func TestJWTAuthValidateTokenForInvalidToken(t *testing.T) {
-	a := New(privateKey, publicKey)
+	a := New(privateKey, publicKey, opts)
 	if _, err := a.ValidateToken("invalid@foo.com"); err != ErrPermissionDenied {
 		t.Error("expected ErrPermissionDenied, got nil")
 	}
 }
+
+func TestJWTAuthValidateTokenExpired(t *testing.T) {
+	a := New(privateKey, publicKey, &Options{TokenExpiration: -time.Minute})
+	token, err := a.GenerateToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	if _, err := a.ValidateToken(token); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestJWTAuthRefreshToken(t *testing.T) {
+	a := New(privateKey, publicKey, opts)
+	refresh, err := a.GenerateRefreshToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate refresh token: ", err)
+	}
+	if _, err := a.ValidateToken(refresh); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied using a refresh token as access token, got %v", err)
+	}
+	if _, err := a.ValidateRefreshToken(refresh); err != nil {
+		t.Error("error on validate refresh token: ", err)
+	}
+
+	access, err := a.GenerateToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	if _, err := a.ValidateRefreshToken(access); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied using an access token as refresh token, got %v", err)
+	}
+}
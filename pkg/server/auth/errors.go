@@ -1,8 +1,14 @@
 package auth
 
 import (
+	"errors"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-var ErrPermissionDenied = status.Errorf(codes.PermissionDenied, "Permission Denied")
+var (
+	ErrPermissionDenied = status.Errorf(codes.PermissionDenied, "Permission Denied")
+
+	errNoKeyID = errors.New("token without kid")
+)
@@ -1,13 +1,31 @@
 package auth
 
+import "time"
+
 type Fake struct{}
 
-func (*Fake) GenerateToken(email string) (string, error) {
+func (*Fake) GenerateToken(email string, version int) (string, error) {
 	return "good token", nil
 }
 
-func (*Fake) ValidateToken(token string) (string, error) {
-	return "gopher@luizalabs.com", nil
+func (*Fake) GenerateRefreshToken(email string, version int) (string, error) {
+	return "good refresh token", nil
+}
+
+func (*Fake) ValidateToken(token string) (*Claims, error) {
+	return fakeClaims(), nil
+}
+
+func (*Fake) ValidateRefreshToken(token string) (*Claims, error) {
+	return fakeClaims(), nil
+}
+
+func fakeClaims() *Claims {
+	return &Claims{
+		Email:     "gopher@luizalabs.com",
+		ID:        "fake",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
 }
 
 func NewFake() Auth {
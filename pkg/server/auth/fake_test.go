@@ -4,7 +4,7 @@ import "testing"
 
 func TestFakeGenerateToken(t *testing.T) {
 	fake := NewFake()
-	token, err := fake.GenerateToken("gopher@luizalabs.com")
+	token, err := fake.GenerateToken("gopher@luizalabs.com", 0)
 	if err != nil {
 		t.Fatal("error on generate fake token: ", err)
 	}
@@ -15,11 +15,11 @@ func TestFakeGenerateToken(t *testing.T) {
 
 func TestFakeValidateToken(t *testing.T) {
 	fake := NewFake()
-	email, err := fake.ValidateToken("foo")
+	claims, err := fake.ValidateToken("foo")
 	if err != nil {
 		t.Fatal("error on validate a fake token: ", err)
 	}
-	if email != "gopher@luizalabs.com" {
-		t.Errorf("expected gopher@luizalabs.com, got %s", email)
+	if claims.Email != "gopher@luizalabs.com" {
+		t.Errorf("expected gopher@luizalabs.com, got %s", claims.Email)
 	}
 }
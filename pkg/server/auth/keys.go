@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	privateKeySuffix = ".rsa"
+	publicKeySuffix  = ".rsa.pub"
+)
+
+// KeySet are the keys used to sign the tokens and the keys used to validate
+// them, by key id (the kid header of the tokens).
+type KeySet struct {
+	SigningKeyID string
+	SigningKey   *rsa.PrivateKey
+	PublicKeys   map[string]*rsa.PublicKey
+}
+
+// KeySource returns the current key set, which may change over time.
+type KeySource interface {
+	KeySet() *KeySet
+}
+
+type staticKeys struct {
+	keys *KeySet
+}
+
+func (s *staticKeys) KeySet() *KeySet {
+	return s.keys
+}
+
+// KeyDir is a directory of key pairs, named <kid>.rsa and <kid>.rsa.pub,
+// like a mounted kubernetes secret. The tokens are signed with the key of the
+// greatest kid (use dates, like 2017-06-01) and validated against all public
+// keys; a key is retired by removing it from the directory.
+type KeyDir struct {
+	path  string
+	mutex sync.RWMutex
+	keys  *KeySet
+}
+
+func (k *KeyDir) KeySet() *KeySet {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return k.keys
+}
+
+// Reload reads the directory again, keeping the current keys on errors.
+func (k *KeyDir) Reload() error {
+	keys, err := loadKeySet(k.path)
+	if err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	k.keys = keys
+	k.mutex.Unlock()
+	return nil
+}
+
+// Run reloads the keys every interval.
+func (k *KeyDir) Run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := k.Reload(); err != nil {
+			log.WithError(err).WithField("dir", k.path).Error("Reloading the token keys")
+		}
+	}
+}
+
+func loadKeySet(dir string) (*KeySet, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := &KeySet{PublicKeys: make(map[string]*rsa.PublicKey)}
+	private := make(map[string]*rsa.PrivateKey)
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, publicKeySuffix):
+			b, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			pub, err := jwt.ParseRSAPublicKeyFromPEM(b)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %v", name, err)
+			}
+			keys.PublicKeys[strings.TrimSuffix(name, publicKeySuffix)] = pub
+		case strings.HasSuffix(name, privateKeySuffix):
+			b, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			pk, err := jwt.ParseRSAPrivateKeyFromPEM(b)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %v", name, err)
+			}
+			private[strings.TrimSuffix(name, privateKeySuffix)] = pk
+		}
+	}
+
+	ids := make([]string, 0, len(private))
+	for id, pk := range private {
+		if _, found := keys.PublicKeys[id]; !found {
+			keys.PublicKeys[id] = &pk.PublicKey
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no private key found in %s", dir)
+	}
+	sort.Strings(ids)
+	keys.SigningKeyID = ids[len(ids)-1]
+	keys.SigningKey = private[keys.SigningKeyID]
+	return keys, nil
+}
+
+// NewKeyDir loads the keys of the directory dir.
+func NewKeyDir(dir string) (*KeyDir, error) {
+	k := &KeyDir{path: dir}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKeyPair(t *testing.T, dir, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal("error generating key: ", err)
+	}
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(filepath.Join(dir, kid+privateKeySuffix), private, 0600); err != nil {
+		t.Fatal("error writing private key: ", err)
+	}
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal("error marshaling public key: ", err)
+	}
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})
+	if err := ioutil.WriteFile(filepath.Join(dir, kid+publicKeySuffix), public, 0644); err != nil {
+		t.Fatal("error writing public key: ", err)
+	}
+	return key
+}
+
+func TestKeyDirRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teresa-keys")
+	if err != nil {
+		t.Fatal("error creating temp dir: ", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeKeyPair(t, dir, "2017-01-01")
+	keys, err := NewKeyDir(dir)
+	if err != nil {
+		t.Fatal("error loading keys: ", err)
+	}
+	a := NewWithKeys(keys, opts)
+	oldToken, err := a.GenerateToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+
+	writeKeyPair(t, dir, "2017-06-01")
+	if err := keys.Reload(); err != nil {
+		t.Fatal("error reloading keys: ", err)
+	}
+	if kid := keys.KeySet().SigningKeyID; kid != "2017-06-01" {
+		t.Errorf("expected the newest key to sign, got %s", kid)
+	}
+	newToken, err := a.GenerateToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	for _, tk := range []string{oldToken, newToken} {
+		if _, err := a.ValidateToken(tk); err != nil {
+			t.Error("error on validate token: ", err)
+		}
+	}
+
+	os.Remove(filepath.Join(dir, "2017-01-01"+privateKeySuffix))
+	os.Remove(filepath.Join(dir, "2017-01-01"+publicKeySuffix))
+	if err := keys.Reload(); err != nil {
+		t.Fatal("error reloading keys: ", err)
+	}
+	if _, err := a.ValidateToken(oldToken); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a retired key, got %v", err)
+	}
+	if _, err := a.ValidateToken(newToken); err != nil {
+		t.Error("error on validate token: ", err)
+	}
+}
+
+func TestKeyDirTokensWithoutKeyID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teresa-keys")
+	if err != nil {
+		t.Fatal("error creating temp dir: ", err)
+	}
+	defer os.RemoveAll(dir)
+
+	legacy := writeKeyPair(t, dir, "2017-01-01")
+	writeKeyPair(t, dir, "2017-06-01")
+	token, err := New(legacy, &legacy.PublicKey, opts).GenerateToken("gopher@luizalabs.com", 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+
+	keys, err := NewKeyDir(dir)
+	if err != nil {
+		t.Fatal("error loading keys: ", err)
+	}
+	if _, err := NewWithKeys(keys, opts).ValidateToken(token); err != nil {
+		t.Error("error on validate a token without kid: ", err)
+	}
+}
+
+func TestKeyDirErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teresa-keys")
+	if err != nil {
+		t.Fatal("error creating temp dir: ", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := NewKeyDir(dir); err == nil {
+		t.Error("expected an error for a directory without keys")
+	}
+
+	writeKeyPair(t, dir, "2017-01-01")
+	keys, err := NewKeyDir(dir)
+	if err != nil {
+		t.Fatal("error loading keys: ", err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "2017-06-01"+privateKeySuffix), []byte("invalid"), 0600)
+	if err := keys.Reload(); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+	if kid := keys.KeySet().SigningKeyID; kid != "2017-01-01" {
+		t.Errorf("expected the keys to be kept on errors, got signing key %s", kid)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/luizalabs/teresa-api/pkg/server/deploy"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete old deploy artifacts from the storage",
+	Long: `Delete the tarballs and slugs of the deploys no longer referenced by
+the last revisions (TERESA_DEPLOY_REVISION_HISTORY_LIMIT) of each app.`,
+	Run: runGC,
+}
+
+func init() {
+	RootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().Bool("dry-run", false, "only show the artifacts to be deleted")
+}
+
+func runGC(cmd *cobra.Command, args []string) {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		log.WithError(err).Fatal("invalid dry-run parameter")
+	}
+
+	st, err := getStorage()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure storage")
+	}
+
+	k8s, err := getK8s()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure k8s client")
+	}
+
+	deployOpt, err := getDeployOpt()
+	if err != nil {
+		log.Fatal("Error getting deploy configuration:", err)
+	}
+
+	gc := deploy.NewGarbageCollector(k8s, st, deployOpt)
+	deleted, err := gc.Collect(dryRun)
+	if err != nil {
+		log.WithError(err).Fatal("failed to collect deploy artifacts")
+	}
+
+	for _, path := range deleted {
+		fmt.Println(path)
+	}
+	if dryRun {
+		fmt.Printf("%d artifacts would be deleted\n", len(deleted))
+	} else {
+		fmt.Printf("%d artifacts deleted\n", len(deleted))
+	}
+}
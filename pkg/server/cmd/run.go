@@ -8,9 +8,14 @@ import (
 	"github.com/luizalabs/teresa-api/pkg/server"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	"github.com/luizalabs/teresa-api/pkg/server/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/gitpush"
 	"github.com/luizalabs/teresa-api/pkg/server/k8s"
+	"github.com/luizalabs/teresa-api/pkg/server/ldap"
+	"github.com/luizalabs/teresa-api/pkg/server/oidc"
 	"github.com/luizalabs/teresa-api/pkg/server/secrets"
 	"github.com/luizalabs/teresa-api/pkg/server/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 	"github.com/spf13/cobra"
 )
 
@@ -75,14 +80,44 @@ func runServer(cmd *cobra.Command, args []string) {
 		log.Fatal("Error getting deploy configuration:", err)
 	}
 
+	webhookOpt, err := getWebhookOpt()
+	if err != nil {
+		log.Fatal("Error getting webhook configuration:", err)
+	}
+
+	gitPushOpt, err := getGitPushOpt()
+	if err != nil {
+		log.Fatal("Error getting git push configuration:", err)
+	}
+
+	oidcOpt, err := getOIDCOpt()
+	if err != nil {
+		log.Fatal("Error getting OpenID Connect configuration:", err)
+	}
+
+	ldapOpt, err := getLDAPOpt()
+	if err != nil {
+		log.Fatal("Error getting LDAP configuration:", err)
+	}
+
+	userOpt, err := getUserOpt()
+	if err != nil {
+		log.Fatal("Error getting user configuration:", err)
+	}
+
 	s, err := server.New(server.Options{
-		Port:      port,
-		Auth:      a,
-		DB:        db,
-		TLSCert:   tlsCert,
-		Storage:   st,
-		K8s:       k8s,
-		DeployOpt: deployOpt,
+		Port:       port,
+		Auth:       a,
+		DB:         db,
+		TLSCert:    tlsCert,
+		Storage:    st,
+		K8s:        k8s,
+		DeployOpt:  deployOpt,
+		WebhookOpt: webhookOpt,
+		GitPushOpt: gitPushOpt,
+		OIDCOpt:    oidcOpt,
+		LDAPOpt:    ldapOpt,
+		UserOpt:    userOpt,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("failed to create server")
@@ -101,6 +136,19 @@ func getSecrets() (secrets.Secrets, error) {
 }
 
 func getAuth(s secrets.Secrets) (auth.Auth, error) {
+	conf := new(auth.Options)
+	if err := envconfig.Process("teresa_auth", conf); err != nil {
+		return nil, err
+	}
+	if conf.KeysDir != "" {
+		keys, err := auth.NewKeyDir(conf.KeysDir)
+		if err != nil {
+			return nil, err
+		}
+		go keys.Run(conf.KeysReloadInterval)
+		return auth.NewWithKeys(keys, conf), nil
+	}
+
 	private, err := s.PrivateKey()
 	if err != nil {
 		return nil, err
@@ -109,7 +157,7 @@ func getAuth(s secrets.Secrets) (auth.Auth, error) {
 	if err != nil {
 		return nil, err
 	}
-	return auth.New(private, public), nil
+	return auth.New(private, public, conf), nil
 }
 
 func getStorage() (storage.Storage, error) {
@@ -135,3 +183,43 @@ func getDeployOpt() (*deploy.Options, error) {
 	}
 	return conf, nil
 }
+
+func getWebhookOpt() (*webhook.Options, error) {
+	conf := new(webhook.Options)
+	if err := envconfig.Process("teresa_webhook", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func getOIDCOpt() (*oidc.Options, error) {
+	conf := new(oidc.Options)
+	if err := envconfig.Process("teresa_oidc", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func getLDAPOpt() (*ldap.Options, error) {
+	conf := new(ldap.Options)
+	if err := envconfig.Process("teresa_ldap", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func getUserOpt() (*user.Options, error) {
+	conf := new(user.Options)
+	if err := envconfig.Process("teresa_user", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func getGitPushOpt() (*gitpush.Options, error) {
+	conf := new(gitpush.Options)
+	if err := envconfig.Process("teresa_gitpush", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
@@ -52,7 +52,12 @@ func createSuperUser(cmd *cobra.Command, args []string) {
 		client.PrintErrorAndExit("Error on connect to Database: %v", err)
 	}
 
-	uOps := user.NewDatabaseOperations(db, auth.NewFake())
+	uOpt, err := getUserOpt()
+	if err != nil {
+		client.PrintErrorAndExit("Error getting user configuration: %v", err)
+	}
+
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), uOpt)
 	if err := uOps.Create(name, email, pass, true); err != nil {
 		client.PrintErrorAndExit("Error on create super user: %s", client.GetErrorMsg(err))
 	}
@@ -3,13 +3,18 @@ package deploy
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/app"
-	st "github.com/luizalabs/teresa-api/pkg/server/storage"
 )
 
 const (
-	DefaultPort = 5000
+	DefaultPort            = appconfig.DefaultPort
+	registryCredsMountPath = "/kaniko/.docker"
+	// legacyStorageSecret is the secret with the storage credentials copied
+	// to the app namespaces before the pods got presigned URLs
+	legacyStorageSecret = "storage-keys"
 )
 
 type PodVolumeMountsSpec struct {
@@ -30,81 +35,142 @@ type PodSpec struct {
 	Env          map[string]string
 	VolumeMounts []*PodVolumeMountsSpec
 	Volume       []*PodVolumeSpec
+	Command      []string
 	Args         []string
 }
 
 type DeploySpec struct {
 	PodSpec
-	TeresaYaml
+	appconfig.TeresaYaml
 	RevisionHistoryLimit int
 	Description          string
-	SlugURL              string
+	SlugPath             string
+	Annotations          map[string]string
 }
 
-func newPodSpec(name, image string, a *app.App, envVars map[string]string, fileStorage st.Storage) *PodSpec {
+func newPodSpec(name, image string, a *app.App, envVars map[string]string) *PodSpec {
 	ps := &PodSpec{
 		Name:      name,
 		Namespace: a.Name,
 		Image:     image,
-		VolumeMounts: []*PodVolumeMountsSpec{{
-			Name:      "storage-keys",
-			MountPath: "/var/run/secrets/deis/objectstore/creds",
-			ReadOnly:  true,
-		}},
-		Volume: []*PodVolumeSpec{{
-			Name:       "storage-keys",
-			SecretName: fileStorage.K8sSecretName(),
-		}},
-		Env: envVars,
+		Env:       envVars,
 	}
 	for _, e := range a.EnvVars {
 		ps.Env[e.Key] = e.Value
 	}
-	for k, v := range fileStorage.PodEnvVars() {
-		ps.Env[k] = v
-	}
 	return ps
 }
 
-func newBuildSpec(a *app.App, deployId, tarBallLocation, buildDest string, fileStorage st.Storage, opts *Options) *PodSpec {
+// buildURLs are the presigned URLs used by the build pod to read the app
+// tarball and to write the slug and the build cache; the cache ones are
+// empty when the cache is disabled.
+type buildURLs struct {
+	TarBall  string
+	Slug     string
+	CacheGet string
+	CachePut string
+}
+
+func newBuildSpec(a *app.App, deployId string, urls *buildURLs, opts *Options) *PodSpec {
+	env := map[string]string{
+		"TAR_URL": urls.TarBall,
+		"PUT_URL": urls.Slug,
+	}
+	if urls.CacheGet != "" && urls.CachePut != "" {
+		env["CACHE_GET_URL"] = urls.CacheGet
+		env["CACHE_PUT_URL"] = urls.CachePut
+	}
 	return newPodSpec(
 		fmt.Sprintf("build-%s", deployId),
 		opts.SlugBuilderImage,
 		a,
+		env,
+	)
+}
+
+// newImageBuildSpec creates a kaniko-style build pod: it reads the tarball
+// from the storage like the slug builder and pushes the image built from the
+// app Dockerfile to the configured registry.
+func newImageBuildSpec(a *app.App, deployId, tarBallURL, image string, opts *Options) *PodSpec {
+	ps := newPodSpec(
+		fmt.Sprintf("build-%s", deployId),
+		opts.ImageBuilderImage,
+		a,
 		map[string]string{
-			"TAR_PATH":        tarBallLocation,
-			"PUT_PATH":        buildDest,
-			"BUILDER_STORAGE": fileStorage.Type(),
+			"TAR_URL":    tarBallURL,
+			"IMAGE_NAME": image,
 		},
-		fileStorage,
 	)
+	ps.Args = []string{
+		fmt.Sprintf("--dockerfile=%s", appconfig.DockerfileFileName),
+		fmt.Sprintf("--destination=%s", image),
+	}
+	if opts.RegistrySecretName != "" {
+		ps.VolumeMounts = append(ps.VolumeMounts, &PodVolumeMountsSpec{
+			Name:      "registry-creds",
+			MountPath: registryCredsMountPath,
+			ReadOnly:  true,
+		})
+		ps.Volume = append(ps.Volume, &PodVolumeSpec{
+			Name:       "registry-creds",
+			SecretName: opts.RegistrySecretName,
+		})
+	}
+	return ps
+}
+
+func imageName(registry, appName, deployId string) string {
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registry, "/"), appName, deployId)
 }
 
-func newDeploySpec(a *app.App, tYaml *TeresaYaml, fileStorage st.Storage, description, slugURL, processType string, opts *Options) *DeploySpec {
+// newDeploySpec creates the deploy of a slug; slugPath is where the slug is
+// kept in the storage and slugURL the signed URL the pods download it from.
+func newDeploySpec(a *app.App, tYaml *appconfig.TeresaYaml, description, slugPath, slugURL, processType string, opts *Options) *DeploySpec {
 	ps := newPodSpec(
 		a.Name,
 		opts.SlugRunnerImage,
 		a,
 		map[string]string{
-			"APP":             a.Name,
-			"PORT":            strconv.Itoa(DefaultPort),
-			"SLUG_URL":        slugURL,
-			"BUILDER_STORAGE": fileStorage.Type(),
+			"APP":      a.Name,
+			"PORT":     strconv.Itoa(int(tYaml.AppPort())),
+			"SLUG_URL": slugURL,
 		},
-		fileStorage,
 	)
 	ps.Args = []string{"start", processType}
 
+	return newDeploySpecFromPodSpec(ps, tYaml, description, slugPath, processType, opts)
+}
+
+func newImageDeploySpec(a *app.App, tYaml *appconfig.TeresaYaml, description, image, processCmd string, opts *Options) *DeploySpec {
+	ps := newPodSpec(
+		a.Name,
+		image,
+		a,
+		map[string]string{
+			"APP":  a.Name,
+			"PORT": strconv.Itoa(int(tYaml.AppPort())),
+		},
+	)
+	if processCmd != "" {
+		ps.Command = shellCommand(processCmd)
+	}
+
+	return newDeploySpecFromPodSpec(ps, tYaml, description, "", a.ProcessType, opts)
+}
+
+func newDeploySpecFromPodSpec(ps *PodSpec, tYaml *appconfig.TeresaYaml, description, slugPath, processType string, opts *Options) *DeploySpec {
 	ds := &DeploySpec{
 		Description:          description,
-		SlugURL:              slugURL,
+		SlugPath:             slugPath,
 		PodSpec:              *ps,
 		RevisionHistoryLimit: opts.RevisionHistoryLimit,
 	}
 
 	if tYaml != nil {
-		ds.TeresaYaml = TeresaYaml{
-			HealthCheck:   tYaml.HealthCheck,
+		ds.TeresaYaml = appconfig.TeresaYaml{
+			Port:          tYaml.Port,
+			Ports:         tYaml.Ports,
+			HealthCheck:   tYaml.HealthCheck.ForProcess(processType),
 			RollingUpdate: tYaml.RollingUpdate,
 			Lifecycle:     tYaml.Lifecycle,
 		}
@@ -113,19 +179,66 @@ func newDeploySpec(a *app.App, tYaml *TeresaYaml, fileStorage st.Storage, descri
 	return ds
 }
 
-func newRunCommandSpec(a *app.App, deployId, command, slugURL string, fileStorage st.Storage, opts *Options) *PodSpec {
+// ServicePort is a port exposed by the app service.
+type ServicePort struct {
+	Name       string
+	Port       int32
+	TargetPort int32
+	Protocol   string
+}
+
+// newServicePorts returns the ports of the app service: the app port on 80
+// and the extra ports of the teresa.yaml on their own numbers.
+func newServicePorts(tYaml *appconfig.TeresaYaml) []*ServicePort {
+	ports := []*ServicePort{{
+		Name:       appconfig.AppPortName,
+		Port:       80,
+		TargetPort: tYaml.AppPort(),
+		Protocol:   appconfig.ProtocolTCP,
+	}}
+	if tYaml == nil {
+		return ports
+	}
+	for _, p := range tYaml.Ports {
+		ports = append(ports, &ServicePort{
+			Name:       p.Name,
+			Port:       p.Port,
+			TargetPort: p.Port,
+			Protocol:   p.ProtocolOrDefault(),
+		})
+	}
+	return ports
+}
+
+func newRunCommandSpec(a *app.App, deployId, command, slugURL string, opts *Options) *PodSpec {
 	ps := newPodSpec(
 		fmt.Sprintf("release-%s-%s", a.Name, deployId),
 		opts.SlugRunnerImage,
 		a,
 		map[string]string{
-			"APP":             a.Name,
-			"PORT":            strconv.Itoa(DefaultPort),
-			"SLUG_URL":        slugURL,
-			"BUILDER_STORAGE": fileStorage.Type(),
+			"APP":      a.Name,
+			"PORT":     strconv.Itoa(DefaultPort),
+			"SLUG_URL": slugURL,
 		},
-		fileStorage,
 	)
 	ps.Args = []string{"start", command}
 	return ps
 }
+
+func newImageRunCommandSpec(a *app.App, deployId, image, command string, opts *Options) *PodSpec {
+	ps := newPodSpec(
+		fmt.Sprintf("release-%s-%s", a.Name, deployId),
+		image,
+		a,
+		map[string]string{
+			"APP":  a.Name,
+			"PORT": strconv.Itoa(DefaultPort),
+		},
+	)
+	ps.Command = shellCommand(command)
+	return ps
+}
+
+func shellCommand(command string) []string {
+	return []string{"/bin/sh", "-c", command}
+}
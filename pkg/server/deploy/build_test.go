@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/app"
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
 )
@@ -21,7 +22,7 @@ func TestNewPodSpec(t *testing.T) {
 	expectedName := "test"
 	expectedImage := "docker/teresa-test:0.0.1"
 
-	ps := newPodSpec(expectedName, expectedImage, a, ev, st.NewFake())
+	ps := newPodSpec(expectedName, expectedImage, a, ev)
 	if ps.Name != expectedName {
 		t.Errorf("expected %s, got %s", expectedName, ps.Name)
 	}
@@ -43,22 +44,18 @@ func TestNewPodSpec(t *testing.T) {
 			t.Errorf("expected %s, got %s for key %s", v, ps.Env[k], k)
 		}
 	}
+
+	if len(ps.Volume) != 0 || len(ps.VolumeMounts) != 0 {
+		t.Errorf("expected no volumes, got %v", ps.Volume)
+	}
 }
 
 func TestNewBuildSpec(t *testing.T) {
 	expectedDeployId := "123"
-	expectedTarBallLocation := "narnia"
-	expectedBuildDest := "nowhere"
+	urls := &buildURLs{TarBall: "https://narnia/in", Slug: "https://narnia/out"}
 	opts := &Options{SlugBuilderImage: "image"}
 
-	ps := newBuildSpec(
-		&app.App{},
-		expectedDeployId,
-		expectedTarBallLocation,
-		expectedBuildDest,
-		st.NewFake(),
-		opts,
-	)
+	ps := newBuildSpec(&app.App{}, expectedDeployId, urls, opts)
 
 	if !strings.HasSuffix(ps.Name, expectedDeployId) {
 		t.Errorf("expected build-%s, got %s", expectedDeployId, ps.Name)
@@ -69,8 +66,8 @@ func TestNewBuildSpec(t *testing.T) {
 	}
 
 	ev := map[string]string{
-		"TAR_PATH": expectedTarBallLocation,
-		"PUT_PATH": expectedBuildDest,
+		"TAR_URL": urls.TarBall,
+		"PUT_URL": urls.Slug,
 	}
 	for k, v := range ev {
 		if ps.Env[k] != v {
@@ -84,10 +81,9 @@ func TestNewCommandRunSpec(t *testing.T) {
 	expectedCommand := "python manage.py migrate"
 	expectedBuildId := "1234"
 	a := &app.App{Name: "teresa"}
-	s := st.NewFake()
 	opts := &Options{SlugRunnerImage: "image"}
 
-	ps := newRunCommandSpec(a, expectedBuildId, expectedCommand, expectedSlugURL, s, opts)
+	ps := newRunCommandSpec(a, expectedBuildId, expectedCommand, expectedSlugURL, opts)
 	if !strings.HasSuffix(ps.Name, fmt.Sprintf("%s-%s", a.Name, expectedBuildId)) {
 		t.Errorf("expected release-%s-%s, got %s", a.Name, expectedBuildId, ps.Name)
 	}
@@ -96,29 +92,24 @@ func TestNewCommandRunSpec(t *testing.T) {
 		t.Errorf("expected %s, got %s", opts.SlugRunnerImage, ps.Image)
 	}
 
-	ev := map[string]string{
-		"SLUG_URL":        expectedSlugURL,
-		"BUILDER_STORAGE": s.Type(),
-	}
-	for k, v := range ev {
-		if ps.Env[k] != v {
-			t.Errorf("expected %s, got %s for key %s", v, ps.Env[k], k)
-		}
+	if ps.Env["SLUG_URL"] != expectedSlugURL {
+		t.Errorf("expected %s, got %s", expectedSlugURL, ps.Env["SLUG_URL"])
 	}
 }
 
 func TestNewDeploySpec(t *testing.T) {
 	expectedDescription := "test"
-	expectedSlugURL := "http://teresa.io/slug.tgz"
+	expectedSlugPath := "deploys/deploy-test/123/out/slug.tgz"
+	expectedSlugURL := "http://teresa.io/slugs/deploys/deploy-test/123/out/slug.tgz?signature=x"
 	expectedProcessType := "worker"
 	expectedName := "deploy-test"
 	opts := &Options{RevisionHistoryLimit: 5}
 
 	ds := newDeploySpec(
 		&app.App{Name: expectedName},
-		&TeresaYaml{},
-		st.NewFake(),
+		&appconfig.TeresaYaml{},
 		expectedDescription,
+		expectedSlugPath,
 		expectedSlugURL,
 		expectedProcessType,
 		opts,
@@ -128,8 +119,12 @@ func TestNewDeploySpec(t *testing.T) {
 		t.Errorf("expected [start %s], got %v", expectedProcessType, ds.Args)
 	}
 
-	if ds.SlugURL != expectedSlugURL {
-		t.Errorf("expected %s, got %s", expectedSlugURL, ds.SlugURL)
+	if ds.SlugPath != expectedSlugPath {
+		t.Errorf("expected %s, got %s", expectedSlugPath, ds.SlugPath)
+	}
+
+	if ds.Env["SLUG_URL"] != expectedSlugURL {
+		t.Errorf("expected %s, got %s", expectedSlugURL, ds.Env["SLUG_URL"])
 	}
 
 	if ds.Description != expectedDescription {
@@ -144,3 +139,125 @@ func TestNewDeploySpec(t *testing.T) {
 		t.Errorf("expected %d, got %d", opts.RevisionHistoryLimit, ds.RevisionHistoryLimit)
 	}
 }
+
+func TestNewImageDeploySpec(t *testing.T) {
+	expectedImage := "luizalabs/teresa:1.0.0"
+	expectedCommand := "python app.py"
+	opts := &Options{RevisionHistoryLimit: 5}
+
+	ds := newImageDeploySpec(
+		&app.App{Name: "deploy-test"},
+		&appconfig.TeresaYaml{},
+		"test",
+		expectedImage,
+		expectedCommand,
+		opts,
+	)
+
+	if ds.Image != expectedImage {
+		t.Errorf("expected %s, got %s", expectedImage, ds.Image)
+	}
+	if ds.SlugPath != "" {
+		t.Errorf("expected empty slug path, got %s", ds.SlugPath)
+	}
+	if _, found := ds.Env["SLUG_URL"]; found {
+		t.Error("expected no SLUG_URL env var")
+	}
+	if len(ds.Args) != 0 {
+		t.Errorf("expected no args, got %v", ds.Args)
+	}
+	if len(ds.Command) != 3 || ds.Command[2] != expectedCommand {
+		t.Errorf("expected [/bin/sh -c %s], got %v", expectedCommand, ds.Command)
+	}
+	if ds.RevisionHistoryLimit != opts.RevisionHistoryLimit {
+		t.Errorf("expected %d, got %d", opts.RevisionHistoryLimit, ds.RevisionHistoryLimit)
+	}
+}
+
+func TestNewImageRunCommandSpec(t *testing.T) {
+	expectedImage := "luizalabs/teresa:1.0.0"
+	expectedCommand := "python manage.py migrate"
+	a := &app.App{Name: "teresa"}
+
+	ps := newImageRunCommandSpec(a, "1234", expectedImage, expectedCommand, &Options{})
+	if ps.Image != expectedImage {
+		t.Errorf("expected %s, got %s", expectedImage, ps.Image)
+	}
+	if len(ps.Command) != 3 || ps.Command[2] != expectedCommand {
+		t.Errorf("expected [/bin/sh -c %s], got %v", expectedCommand, ps.Command)
+	}
+}
+
+func TestNewImageBuildSpec(t *testing.T) {
+	expectedImage := "registry.luizalabs.com/teresa:123"
+	opts := &Options{ImageBuilderImage: "image", RegistrySecretName: "registry"}
+
+	ps := newImageBuildSpec(&app.App{Name: "teresa"}, "123", "https://narnia", expectedImage, opts)
+	if ps.Image != opts.ImageBuilderImage {
+		t.Errorf("expected %s, got %s", opts.ImageBuilderImage, ps.Image)
+	}
+	if ps.Env["TAR_URL"] != "https://narnia" {
+		t.Errorf("expected https://narnia, got %s", ps.Env["TAR_URL"])
+	}
+	expectedArg := fmt.Sprintf("--destination=%s", expectedImage)
+	if len(ps.Args) != 2 || ps.Args[1] != expectedArg {
+		t.Errorf("expected %s, got %v", expectedArg, ps.Args)
+	}
+
+	var found bool
+	for _, v := range ps.Volume {
+		if v.SecretName == opts.RegistrySecretName {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected volume of secret %s", opts.RegistrySecretName)
+	}
+}
+
+func TestImageName(t *testing.T) {
+	var testCases = []struct {
+		registry string
+		expected string
+	}{
+		{"registry.luizalabs.com", "registry.luizalabs.com/teresa:123"},
+		{"registry.luizalabs.com/apps/", "registry.luizalabs.com/apps/teresa:123"},
+	}
+
+	for _, tc := range testCases {
+		if actual := imageName(tc.registry, "teresa", "123"); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestNewBuildSpecCache(t *testing.T) {
+	a := &app.App{Name: "teresa"}
+	ops := &DeployOperations{fileStorage: st.NewFake()}
+	var testCases = []struct {
+		noCache     bool
+		expectedGet string
+		expectedPut string
+	}{
+		{false, "https://bucket/deploys/teresa/cache.tgz?method=GET", "https://bucket/deploys/teresa/cache.tgz?method=PUT"},
+		{true, "", ""},
+	}
+
+	for _, tc := range testCases {
+		opts := &Options{NoCache: tc.noCache}
+		urls, err := ops.newBuildURLs(a, "https://narnia", "nowhere", opts)
+		if err != nil {
+			t.Fatal("error creating build urls:", err)
+		}
+		ps := newBuildSpec(a, "123", urls, opts)
+		if ps.Env["CACHE_GET_URL"] != tc.expectedGet {
+			t.Errorf("expected %q, got %q", tc.expectedGet, ps.Env["CACHE_GET_URL"])
+		}
+		if ps.Env["CACHE_PUT_URL"] != tc.expectedPut {
+			t.Errorf("expected %q, got %q", tc.expectedPut, ps.Env["CACHE_PUT_URL"])
+		}
+		if ps.Env["PUT_URL"] != "https://bucket/nowhere/slug.tgz?method=PUT" {
+			t.Errorf("expected slug put url, got %q", ps.Env["PUT_URL"])
+		}
+	}
+}
@@ -3,68 +3,25 @@ package deploy
 import (
 	"archive/tar"
 	"compress/gzip"
-	"fmt"
 	"io"
 	"io/ioutil"
 
-	yaml "gopkg.in/yaml.v2"
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 )
 
-const (
-	maxDrainTimeoutSeconds = 30
-)
-
-type HealthCheckProbe struct {
-	FailureThreshold    int32  `yaml:"failureThreshold"`
-	InitialDelaySeconds int32  `yaml:"initialDelaySeconds"`
-	PeriodSeconds       int32  `yaml:"periodSeconds"`
-	SuccessThreshold    int32  `yaml:"successThreshold"`
-	TimeoutSeconds      int32  `yaml:"timeoutSeconds"`
-	Path                string `yaml:"path"`
-}
-
-type HealthCheck struct {
-	Liveness  *HealthCheckProbe
-	Readiness *HealthCheckProbe
-}
-
-type RollingUpdate struct {
-	MaxSurge       string `yaml:"maxSurge,omitempty"`
-	MaxUnavailable string `yaml:"maxUnavailable,omitempty"`
-}
-
-type PreStop struct {
-	DrainTimeoutSeconds int `yaml:"drainTimeoutSeconds,omitempty"`
-}
-
-type Lifecycle struct {
-	PreStop *PreStop `yaml:"preStop,omitempty"`
-}
-
-type TeresaYaml struct {
-	HealthCheck   *HealthCheck   `yaml:"healthCheck,omitempty"`
-	RollingUpdate *RollingUpdate `yaml:"rollingUpdate,omitempty"`
-	Lifecycle     *Lifecycle     `yaml:"lifecycle,omitempty"`
-}
-
-type Procfile map[string]string
-
 type DeployConfigFiles struct {
-	TeresaYaml *TeresaYaml
-	Procfile   Procfile
+	TeresaYaml    *appconfig.TeresaYaml
+	Procfile      appconfig.Procfile
+	HasDockerfile bool
 }
 
-const (
-	TeresaYamlFileName = "teresa.yaml"
-	ProcfileFileName   = "Procfile"
-)
-
-func readFileFromTarBall(r io.Reader, t interface{}) error {
-	b, err := ioutil.ReadAll(r)
-	if err != nil {
-		return err
+// IsDockerBuild reports whether the app should be built from its
+// Dockerfile instead of with the slug builder.
+func (d *DeployConfigFiles) IsDockerBuild() bool {
+	if d.TeresaYaml != nil && d.TeresaYaml.Build != "" {
+		return d.TeresaYaml.Build == appconfig.BuildDocker
 	}
-	return yaml.Unmarshal(b, t)
+	return d.HasDockerfile
 }
 
 func getDeployConfigFilesFromTarBall(tarBall io.ReadSeeker) (*DeployConfigFiles, error) {
@@ -75,6 +32,7 @@ func getDeployConfigFilesFromTarBall(tarBall io.ReadSeeker) (*DeployConfigFiles,
 	defer gReader.Close()
 
 	deployFiles := new(DeployConfigFiles)
+	var teresaYaml, procfile []byte
 	tarReader := tar.NewReader(gReader)
 	for {
 		hdr, err := tarReader.Next()
@@ -85,38 +43,29 @@ func getDeployConfigFilesFromTarBall(tarBall io.ReadSeeker) (*DeployConfigFiles,
 			return nil, err
 		}
 
-		if hdr.Name != TeresaYamlFileName && hdr.Name != ProcfileFileName {
-			continue
-		}
-
-		if hdr.Name == TeresaYamlFileName {
-			deployFiles.TeresaYaml = new(TeresaYaml)
-			if err := readFileFromTarBall(tarReader, deployFiles.TeresaYaml); err != nil {
-				return nil, err
-			}
-			if err := validateTeresaYaml(deployFiles.TeresaYaml); err != nil {
+		switch hdr.Name {
+		case appconfig.DockerfileFileName:
+			deployFiles.HasDockerfile = true
+		case appconfig.TeresaYamlFileName:
+			if teresaYaml, err = ioutil.ReadAll(tarReader); err != nil {
 				return nil, err
 			}
-		} else {
-			deployFiles.Procfile = make(map[string]string)
-			if err := readFileFromTarBall(tarReader, deployFiles.Procfile); err != nil {
+		case appconfig.ProcfileFileName:
+			if procfile, err = ioutil.ReadAll(tarReader); err != nil {
 				return nil, err
 			}
+		default:
+			continue
 		}
 
-		if deployFiles.TeresaYaml != nil && deployFiles.Procfile != nil {
-			return deployFiles, nil
+		if teresaYaml != nil && procfile != nil && deployFiles.HasDockerfile {
+			break
 		}
 	}
 
-	return deployFiles, nil
-}
-
-func validateTeresaYaml(tYaml *TeresaYaml) error {
-	if tYaml.Lifecycle != nil && tYaml.Lifecycle.PreStop != nil {
-		if tYaml.Lifecycle.PreStop.DrainTimeoutSeconds > maxDrainTimeoutSeconds || tYaml.Lifecycle.PreStop.DrainTimeoutSeconds < 0 {
-			return fmt.Errorf("Invalid drainTimeoutSeconds: %d", tYaml.Lifecycle.PreStop.DrainTimeoutSeconds)
-		}
+	deployFiles.TeresaYaml, deployFiles.Procfile, err = appconfig.Parse(teresaYaml, procfile)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return deployFiles, nil
 }
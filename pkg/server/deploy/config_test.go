@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 )
 
 func TestGetTeresaYamlFromDeployTarBall(t *testing.T) {
@@ -111,3 +113,43 @@ func TestGetDeployConfigFromTarBall(t *testing.T) {
 		t.Errorf("expected %s, got %s", expectedText, actual)
 	}
 }
+
+func TestGetDeployConfigFilesFromTarBallWithDockerfile(t *testing.T) {
+	tarBall, err := os.Open(filepath.Join("testdata", "dockerfile.tgz"))
+	if err != nil {
+		t.Fatal("error getting tarBall:", err)
+	}
+	defer tarBall.Close()
+
+	deployConfig, err := getDeployConfigFilesFromTarBall(tarBall)
+	if err != nil {
+		t.Fatal("error getting deploy config file from tarball:", err)
+	}
+	if !deployConfig.HasDockerfile {
+		t.Error("expected HasDockerfile true, got false")
+	}
+	if deployConfig.Procfile["release"] != "./migrate" {
+		t.Errorf("expected ./migrate, got %s", deployConfig.Procfile["release"])
+	}
+}
+
+func TestDeployConfigFilesIsDockerBuild(t *testing.T) {
+	var testCases = []struct {
+		tYaml         *appconfig.TeresaYaml
+		hasDockerfile bool
+		expected      bool
+	}{
+		{nil, false, false},
+		{nil, true, true},
+		{&appconfig.TeresaYaml{}, true, true},
+		{&appconfig.TeresaYaml{Build: appconfig.BuildSlug}, true, false},
+		{&appconfig.TeresaYaml{Build: appconfig.BuildDocker}, false, true},
+	}
+
+	for _, tc := range testCases {
+		d := &DeployConfigFiles{TeresaYaml: tc.tYaml, HasDockerfile: tc.hasDockerfile}
+		if actual := d.IsDockerBuild(); actual != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, actual)
+		}
+	}
+}
@@ -3,14 +3,18 @@ package deploy
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 
 	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/app"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 	"github.com/pborman/uuid"
 )
 
@@ -18,77 +22,203 @@ const ProcfileReleaseCmd = "release"
 
 type Operations interface {
 	Deploy(user *storage.User, appName string, tarBall io.ReadSeeker, description string, opts *Options) (io.ReadCloser, error)
+	DeployImage(user *storage.User, appName, image string, tarBall io.ReadSeeker, description string, opts *Options) (io.ReadCloser, error)
+	Promote(user *storage.User, fromApp, toApp, deployId, description string, opts *Options) (io.ReadCloser, error)
 }
 
 type K8sOperations interface {
 	PodRun(podSpec *PodSpec) (io.ReadCloser, <-chan int, error)
 	CreateOrUpdateDeploy(deploySpec *DeploySpec) error
 	HasService(namespace, name string) (bool, error)
-	CreateService(namespace, name string) error
+	CreateService(namespace, name string, ports []*ServicePort) error
+	UpdateServicePorts(namespace, name string, ports []*ServicePort) error
+	DeployAnnotations(namespace, deployId string) (map[string]string, error)
+	DeployIds(namespace string) ([]string, error)
+	SlugURLs() ([]string, error)
+	DeleteSecret(namespace, name string) error
+	IsNotFound(err error) bool
 }
 
 type DeployOperations struct {
 	appOps      app.Operations
 	fileStorage st.Storage
 	k8s         K8sOperations
+	wh          webhook.Notifier
 }
 
-func (ops *DeployOperations) Deploy(user *storage.User, appName string, tarBall io.ReadSeeker, description string, opts *Options) (io.ReadCloser, error) {
+func (ops *DeployOperations) prepareDeploy(user *storage.User, appName string, tarBall io.ReadSeeker) (*app.App, *DeployConfigFiles, error) {
 	a, err := ops.appOps.Get(appName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	teamName, err := ops.appOps.TeamName(appName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	a.Team = teamName
 
-	if !ops.appOps.HasPermission(user, appName) {
-		return nil, auth.ErrPermissionDenied
+	if !ops.appOps.HasPermission(user, appName, team.RoleMember) {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+
+	if tarBall == nil {
+		return a, new(DeployConfigFiles), nil
 	}
 
 	confFiles, err := getDeployConfigFilesFromTarBall(tarBall)
 	if err != nil {
-		return nil, teresa_errors.New(ErrInvalidTeresaYamlFile, err)
+		if vErr, ok := err.(*appconfig.ValidationError); ok {
+			return nil, nil, teresa_errors.New(newInvalidConfigErr(vErr), err)
+		}
+		return nil, nil, teresa_errors.New(ErrInvalidTeresaYamlFile, err)
+	}
+	return a, confFiles, nil
+}
+
+func (ops *DeployOperations) Deploy(user *storage.User, appName string, tarBall io.ReadSeeker, description string, opts *Options) (io.ReadCloser, error) {
+	a, confFiles, err := ops.prepareDeploy(user, appName, tarBall)
+	if err != nil {
+		return nil, err
 	}
 
 	deployId := genDeployId()
+	if confFiles.IsDockerBuild() {
+		if opts.Registry == "" {
+			return nil, ErrRegistryNotConfigured
+		}
+		return ops.deployDockerfile(user, a, confFiles, tarBall, deployId, description, opts), nil
+	}
+	if !slugConfigured(opts) {
+		return nil, ErrInvalidSlugConfig
+	}
 	buildDest := fmt.Sprintf("deploys/%s/%s/out", appName, deployId)
 
+	ops.notify(webhook.DeployStarted, user, a, deployId, description, nil)
 	r, w := io.Pipe()
 	go func() {
 		defer w.Close()
-		if err = ops.buildApp(tarBall, a, deployId, buildDest, w, opts); err != nil {
+		err := ops.buildApp(tarBall, a, deployId, buildDest, w, opts)
+		if err != nil {
 			log.WithError(err).WithField("id", deployId).Errorf("Building app %s", appName)
-			return
+		} else {
+			slugPath := fmt.Sprintf("%s/slug.tgz", buildDest)
+			err = ops.releaseAndDeploySlug(a, confFiles, deployId, slugPath, description, w, opts)
 		}
+		ops.notifyResult(user, a, deployId, description, err)
+	}()
+	return r, nil
+}
 
-		slugURL := fmt.Sprintf("%s/slug.tgz", buildDest)
-		releaseCmd := confFiles.Procfile[ProcfileReleaseCmd]
-		if confFiles.Procfile != nil && releaseCmd != "" {
-			if err := ops.runReleaseCmd(a, deployId, slugURL, w, opts); err != nil {
-				log.WithError(err).WithField("id", deployId).Errorf("Running release command %s in app %s", releaseCmd, appName)
-				return
-			}
+func (ops *DeployOperations) releaseAndDeploySlug(a *app.App, confFiles *DeployConfigFiles, deployId, slugPath, description string, w io.Writer, opts *Options) error {
+	if releaseCmd := confFiles.Procfile[ProcfileReleaseCmd]; releaseCmd != "" {
+		if err := ops.runReleaseCmd(a, deployId, slugPath, w, opts); err != nil {
+			log.WithError(err).WithField("id", deployId).Errorf("Running release command %s in app %s", releaseCmd, a.Name)
+			return err
 		}
+	}
 
-		if err := ops.createDeploy(a, confFiles.TeresaYaml, description, slugURL, opts); err != nil {
-			log.WithError(err).Errorf("Creating deploy app %s", appName)
-			return
-		}
+	if err := ops.createDeploy(a, deployId, confFiles, description, slugPath, opts); err != nil {
+		log.WithError(err).Errorf("Creating deploy app %s", a.Name)
+		return err
+	}
 
-		if err := ops.exposeService(a, w); err != nil {
-			log.WithError(err).Errorf("Exposing service %s", appName)
-		}
-		fmt.Fprintln(w, fmt.Sprintf("The app %s has been successfully deployed", appName))
+	ops.finishDeploy(a, confFiles, deployId, w)
+	return nil
+}
+
+func (ops *DeployOperations) finishDeploy(a *app.App, confFiles *DeployConfigFiles, deployId string, w io.Writer) {
+	if err := ops.exposeService(a, confFiles.TeresaYaml, w); err != nil {
+		log.WithError(err).Errorf("Exposing service %s", a.Name)
+	}
+	if err := ops.k8s.DeleteSecret(a.Name, legacyStorageSecret); err != nil && !ops.k8s.IsNotFound(err) {
+		log.WithError(err).Errorf("Deleting the storage credentials of app %s", a.Name)
+	}
+	fmt.Fprintln(w, fmt.Sprintf("The app %s has been successfully deployed (deploy id: %s)", a.Name, deployId))
+}
+
+// DeployImage creates the deployment straight from an already built image,
+// skipping the slug build. The tarBall, when not nil, only needs to carry the
+// teresa.yaml and Procfile of the app.
+func (ops *DeployOperations) DeployImage(user *storage.User, appName, image string, tarBall io.ReadSeeker, description string, opts *Options) (io.ReadCloser, error) {
+	if !isValidImage(image) {
+		return nil, ErrInvalidImage
+	}
+
+	a, confFiles, err := ops.prepareDeploy(user, appName, tarBall)
+	if err != nil {
+		return nil, err
+	}
+
+	deployId := genDeployId()
+
+	ops.notify(webhook.DeployStarted, user, a, deployId, description, nil)
+	r, w := io.Pipe()
+	go func() {
+		defer w.Close()
+		err := ops.releaseAndDeployImage(a, confFiles, deployId, image, description, w, opts)
+		ops.notifyResult(user, a, deployId, description, err)
 	}()
 	return r, nil
 }
 
-func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugPath string, stream io.Writer, opts *Options) error {
-	runCommandSpec := newRunCommandSpec(a, deployId, ProcfileReleaseCmd, slugPath, ops.fileStorage, opts)
+func (ops *DeployOperations) deployDockerfile(user *storage.User, a *app.App, confFiles *DeployConfigFiles, tarBall io.ReadSeeker, deployId, description string, opts *Options) io.ReadCloser {
+	image := imageName(opts.Registry, a.Name, deployId)
+
+	ops.notify(webhook.DeployStarted, user, a, deployId, description, nil)
+	r, w := io.Pipe()
+	go func() {
+		defer w.Close()
+		err := ops.buildImage(tarBall, a, deployId, image, w, opts)
+		if err != nil {
+			log.WithError(err).WithField("id", deployId).Errorf("Building image of app %s", a.Name)
+		} else {
+			err = ops.releaseAndDeployImage(a, confFiles, deployId, image, description, w, opts)
+		}
+		ops.notifyResult(user, a, deployId, description, err)
+	}()
+	return r
+}
+
+func (ops *DeployOperations) releaseAndDeployImage(a *app.App, confFiles *DeployConfigFiles, deployId, image, description string, w io.Writer, opts *Options) error {
+	if releaseCmd := confFiles.Procfile[ProcfileReleaseCmd]; releaseCmd != "" {
+		if err := ops.runImageReleaseCmd(a, deployId, image, releaseCmd, w, opts); err != nil {
+			log.WithError(err).WithField("id", deployId).Errorf("Running release command %s in app %s", releaseCmd, a.Name)
+			return err
+		}
+	}
+
+	if err := ops.createImageDeploy(a, deployId, confFiles, description, image, opts); err != nil {
+		log.WithError(err).Errorf("Creating deploy app %s", a.Name)
+		return err
+	}
+
+	ops.finishDeploy(a, confFiles, deployId, w)
+	return nil
+}
+
+func (ops *DeployOperations) notify(event string, user *storage.User, a *app.App, deployId, description string, data map[string]string) {
+	if ops.wh == nil {
+		return
+	}
+	if data == nil {
+		data = make(map[string]string)
+	}
+	data["deploy_id"] = deployId
+	data["description"] = description
+	ops.wh.Notify(webhook.NewEvent(event, a.Team, a.Name, user.Email, data))
+}
+
+func (ops *DeployOperations) notifyResult(user *storage.User, a *app.App, deployId, description string, err error) {
+	if err != nil {
+		ops.notify(webhook.DeployFailed, user, a, deployId, description, map[string]string{"error": err.Error()})
+		return
+	}
+	ops.notify(webhook.DeploySucceeded, user, a, deployId, description, nil)
+}
+
+func (ops *DeployOperations) runImageReleaseCmd(a *app.App, deployId, image, releaseCmd string, stream io.Writer, opts *Options) error {
+	runCommandSpec := newImageRunCommandSpec(a, deployId, image, releaseCmd, opts)
 
 	fmt.Fprintln(stream, "Running release command")
 	err := ops.podRun(runCommandSpec, stream)
@@ -101,12 +231,37 @@ func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugPath string
 	return nil
 }
 
-func (ops *DeployOperations) createDeploy(a *app.App, tYaml *TeresaYaml, description, slugPath string, opts *Options) error {
-	deploySpec := newDeploySpec(a, tYaml, ops.fileStorage, description, slugPath, a.ProcessType, opts)
+func (ops *DeployOperations) createImageDeploy(a *app.App, deployId string, confFiles *DeployConfigFiles, description, image string, opts *Options) error {
+	processCmd := confFiles.Procfile[a.ProcessType]
+	deploySpec := newImageDeploySpec(a, confFiles.TeresaYaml, description, image, processCmd, opts)
+	deploySpec.Annotations = newDeployAnnotations(deployId, confFiles, "", image)
+	return ops.k8s.CreateOrUpdateDeploy(deploySpec)
+}
+
+func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugPath string, stream io.Writer, opts *Options) error {
+	u, err := ops.fileStorage.PresignGet(slugPath, opts.PresignExpiry)
+	if err != nil {
+		return err
+	}
+	runCommandSpec := newRunCommandSpec(a, deployId, ProcfileReleaseCmd, u, opts)
+
+	fmt.Fprintln(stream, "Running release command")
+	if err := ops.podRun(runCommandSpec, stream); err != nil {
+		if err == ErrPodRunFail {
+			return ErrReleaseFail
+		}
+		return err
+	}
+	return nil
+}
+
+func (ops *DeployOperations) createDeploy(a *app.App, deployId string, confFiles *DeployConfigFiles, description, slugPath string, opts *Options) error {
+	deploySpec := newDeploySpec(a, confFiles.TeresaYaml, description, slugPath, slugURL(slugPath, opts), a.ProcessType, opts)
+	deploySpec.Annotations = newDeployAnnotations(deployId, confFiles, slugPath, "")
 	return ops.k8s.CreateOrUpdateDeploy(deploySpec)
 }
 
-func (ops *DeployOperations) exposeService(a *app.App, w io.Writer) error {
+func (ops *DeployOperations) exposeService(a *app.App, tYaml *appconfig.TeresaYaml, w io.Writer) error {
 	if a.ProcessType != app.ProcessTypeWeb {
 		return nil
 	}
@@ -114,23 +269,71 @@ func (ops *DeployOperations) exposeService(a *app.App, w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	if !hasSrv {
-		fmt.Fprintln(w, "Exposing service")
-		if err := ops.k8s.CreateService(a.Name, a.Name); err != nil {
-			return err
-		}
+	ports := newServicePorts(tYaml)
+	if hasSrv {
+		return ops.k8s.UpdateServicePorts(a.Name, a.Name, ports)
 	}
-	return nil // already exposed
+	fmt.Fprintln(w, "Exposing service")
+	return ops.k8s.CreateService(a.Name, a.Name, ports)
 }
 
-func (ops *DeployOperations) buildApp(tarBall io.ReadSeeker, a *app.App, deployId, buildDest string, stream io.Writer, opts *Options) error {
+// uploadTarBall uploads the app tarball and returns a presigned URL to
+// download it.
+func (ops *DeployOperations) uploadTarBall(tarBall io.ReadSeeker, a *app.App, deployId string, opts *Options) (string, error) {
 	tarBall.Seek(0, 0)
 	tarBallLocation := fmt.Sprintf("deploys/%s/%s/in/app.tar.gz", a.Name, deployId)
 	if err := ops.fileStorage.UploadFile(tarBallLocation, tarBall); err != nil {
+		return "", err
+	}
+	return ops.fileStorage.PresignGet(tarBallLocation, opts.PresignExpiry)
+}
+
+func (ops *DeployOperations) newBuildURLs(a *app.App, tarBallURL, buildDest string, opts *Options) (*buildURLs, error) {
+	urls := &buildURLs{TarBall: tarBallURL}
+	var err error
+	urls.Slug, err = ops.fileStorage.PresignPut(fmt.Sprintf("%s/slug.tgz", buildDest), opts.PresignExpiry)
+	if err != nil {
+		return nil, err
+	}
+	if opts.NoCache {
+		return urls, nil
+	}
+	cachePath := app.BuildCachePath(a.Name)
+	if urls.CacheGet, err = ops.fileStorage.PresignGet(cachePath, opts.PresignExpiry); err != nil {
+		return nil, err
+	}
+	if urls.CachePut, err = ops.fileStorage.PresignPut(cachePath, opts.PresignExpiry); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+func (ops *DeployOperations) buildImage(tarBall io.ReadSeeker, a *app.App, deployId, image string, stream io.Writer, opts *Options) error {
+	tarBallURL, err := ops.uploadTarBall(tarBall, a, deployId, opts)
+	if err != nil {
+		return err
+	}
+	buildSpec := newImageBuildSpec(a, deployId, tarBallURL, image, opts)
+	if err := ops.podRun(buildSpec, stream); err != nil {
+		if err == ErrPodRunFail {
+			return ErrBuildFail
+		}
 		return err
 	}
-	buildSpec := newBuildSpec(a, deployId, tarBallLocation, buildDest, ops.fileStorage, opts)
-	err := ops.podRun(buildSpec, stream)
+	return nil
+}
+
+func (ops *DeployOperations) buildApp(tarBall io.ReadSeeker, a *app.App, deployId, buildDest string, stream io.Writer, opts *Options) error {
+	tarBallURL, err := ops.uploadTarBall(tarBall, a, deployId, opts)
+	if err != nil {
+		return err
+	}
+	urls, err := ops.newBuildURLs(a, tarBallURL, buildDest, opts)
+	if err != nil {
+		return err
+	}
+	buildSpec := newBuildSpec(a, deployId, urls, opts)
+	err = ops.podRun(buildSpec, stream)
 	if err != nil {
 		if err == ErrPodRunFail {
 			return ErrBuildFail
@@ -154,10 +357,14 @@ func (ops *DeployOperations) podRun(podSpec *PodSpec, stream io.Writer) error {
 	return nil
 }
 
+func isValidImage(image string) bool {
+	return image != "" && !strings.ContainsAny(image, " \t\n")
+}
+
 func genDeployId() string {
 	return uuid.New()[:8]
 }
 
-func NewDeployOperations(aOps app.Operations, k8s K8sOperations, s st.Storage) Operations {
-	return &DeployOperations{appOps: aOps, k8s: k8s, fileStorage: s}
+func NewDeployOperations(aOps app.Operations, k8s K8sOperations, s st.Storage, wh webhook.Notifier) Operations {
+	return &DeployOperations{appOps: aOps, k8s: k8s, fileStorage: s, wh: wh}
 }
@@ -7,12 +7,16 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/app"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 )
 
 type fakeReadSeeker struct{}
@@ -34,6 +38,40 @@ type fakeK8sOperations struct {
 	podRunReadCloser       io.ReadCloser
 	podRunExitCodeChan     chan int
 	podRunErr              error
+	deployAnnotations      map[string]string
+	deployIds              map[string][]string
+	slugURLs               []string
+	updateServiceWasCalled bool
+	lastServicePorts       []*ServicePort
+	deletedSecrets         []string
+}
+
+func (f *fakeK8sOperations) DeployAnnotations(namespace, deployId string) (map[string]string, error) {
+	if f.deployAnnotations == nil {
+		return nil, errors.New("not found")
+	}
+	return f.deployAnnotations, nil
+}
+
+func (f *fakeK8sOperations) DeployIds(namespace string) ([]string, error) {
+	ids, found := f.deployIds[namespace]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	return ids, nil
+}
+
+func (f *fakeK8sOperations) SlugURLs() ([]string, error) {
+	return f.slugURLs, nil
+}
+
+func (f *fakeK8sOperations) DeleteSecret(namespace, name string) error {
+	f.deletedSecrets = append(f.deletedSecrets, namespace+"/"+name)
+	return nil
+}
+
+func (f *fakeK8sOperations) IsNotFound(err error) bool {
+	return true
 }
 
 func (f *fakeK8sOperations) PodRun(podSpec *PodSpec) (io.ReadCloser, <-chan int, error) {
@@ -49,8 +87,15 @@ func (f *fakeK8sOperations) HasService(namespace string, name string) (bool, err
 	return f.hasSrvReturn, f.hasSrvErr
 }
 
-func (f *fakeK8sOperations) CreateService(namespace string, name string) error {
+func (f *fakeK8sOperations) CreateService(namespace string, name string, ports []*ServicePort) error {
 	f.createServiceWasCalled = true
+	f.lastServicePorts = ports
+	return nil
+}
+
+func (f *fakeK8sOperations) UpdateServicePorts(namespace string, name string, ports []*ServicePort) error {
+	f.updateServiceWasCalled = true
+	f.lastServicePorts = ports
 	return nil
 }
 
@@ -59,6 +104,7 @@ func TestDeployPermissionDenied(t *testing.T) {
 		app.NewFakeOperations(),
 		&fakeK8sOperations{},
 		st.NewFake(),
+		nil,
 	)
 	u := &storage.User{Email: "bad-user@luizalabs.com"}
 	if _, err := ops.Deploy(u, "teresa", &fakeReadSeeker{}, "test", &Options{}); err != auth.ErrPermissionDenied {
@@ -86,20 +132,185 @@ func TestDeploy(t *testing.T) {
 		app.NewFakeOperations(),
 		fakeK8s,
 		st.NewFake(),
+		nil,
 	)
 	u := &storage.User{Email: "gopher@luizalabs.com"}
-	r, err := ops.Deploy(u, "teresa", tarBall, "test", &Options{})
+	opts := &Options{ServerURL: "https://teresa.io", SlugSigningKey: "key"}
+	r, err := ops.Deploy(u, "teresa", tarBall, "test", opts)
 	if err != nil {
 		t.Fatal("error making deploy:", err)
 	}
 	defer r.Close()
 }
 
+func TestDeploySlugNotConfigured(t *testing.T) {
+	tarBall, err := os.Open(filepath.Join("testdata", "fooTxt.tgz"))
+	if err != nil {
+		t.Fatal("error getting tarBall:", err)
+	}
+	defer tarBall.Close()
+
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		&fakeK8sOperations{},
+		st.NewFake(),
+		nil,
+	)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	if _, err := ops.Deploy(u, "teresa", tarBall, "test", &Options{}); err != ErrInvalidSlugConfig {
+		t.Errorf("expected ErrInvalidSlugConfig, got %v", err)
+	}
+}
+
+func TestDeployImage(t *testing.T) {
+	fakeK8s := &fakeK8sOperations{}
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		fakeK8s,
+		st.NewFake(),
+		nil,
+	)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	expectedImage := "luizalabs/teresa:1.0.0"
+
+	r, err := ops.DeployImage(u, "teresa", expectedImage, nil, "test", &Options{})
+	if err != nil {
+		t.Fatal("error making deploy:", err)
+	}
+	defer r.Close()
+	ioutil.ReadAll(r)
+
+	if fakeK8s.lastDeploySpec == nil {
+		t.Fatal("expected a deploy spec, got nil")
+	}
+	if fakeK8s.lastDeploySpec.Image != expectedImage {
+		t.Errorf("expected %s, got %s", expectedImage, fakeK8s.lastDeploySpec.Image)
+	}
+}
+
+func TestDeployImageNotifyWebhooks(t *testing.T) {
+	wh := webhook.NewFakeOperations()
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		&fakeK8sOperations{},
+		st.NewFake(),
+		wh,
+	)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+
+	r, err := ops.DeployImage(u, "teresa", "luizalabs/teresa:1.0.0", nil, "test", &Options{})
+	if err != nil {
+		t.Fatal("error making deploy:", err)
+	}
+	defer r.Close()
+	ioutil.ReadAll(r)
+
+	events := wh.(*webhook.FakeOperations).Events
+	expectedTypes := []string{webhook.DeployStarted, webhook.DeploySucceeded}
+	if len(events) != len(expectedTypes) {
+		t.Fatalf("expected %d events, got %d", len(expectedTypes), len(events))
+	}
+	for i, ev := range events {
+		if ev.Type != expectedTypes[i] {
+			t.Errorf("expected %s, got %s", expectedTypes[i], ev.Type)
+		}
+		if ev.Data["description"] != "test" {
+			t.Errorf("expected test, got %s", ev.Data["description"])
+		}
+	}
+	if events[0].Data["deploy_id"] != events[1].Data["deploy_id"] {
+		t.Errorf("expected the same deploy id, got %s and %s", events[0].Data["deploy_id"], events[1].Data["deploy_id"])
+	}
+}
+
+func TestDeployImageErrors(t *testing.T) {
+	var testCases = []struct {
+		email       string
+		image       string
+		expectedErr error
+	}{
+		{"bad-user@luizalabs.com", "luizalabs/teresa:1.0.0", auth.ErrPermissionDenied},
+		{"gopher@luizalabs.com", "", ErrInvalidImage},
+		{"gopher@luizalabs.com", "luizalabs/teresa 1.0.0", ErrInvalidImage},
+	}
+
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		&fakeK8sOperations{},
+		st.NewFake(),
+		nil,
+	)
+	for _, tc := range testCases {
+		u := &storage.User{Email: tc.email}
+		if _, err := ops.DeployImage(u, "teresa", tc.image, nil, "test", &Options{}); err != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestDeployDockerfile(t *testing.T) {
+	podExitCodeChan := make(chan int, 2)
+	defer close(podExitCodeChan)
+	fakeK8s := &fakeK8sOperations{
+		podRunExitCodeChan: podExitCodeChan,
+		podRunReadCloser:   ioutil.NopCloser(new(bytes.Buffer)),
+	}
+	podExitCodeChan <- 0 // build
+	podExitCodeChan <- 0 // release
+
+	tarBall, err := os.Open(filepath.Join("testdata", "dockerfile.tgz"))
+	if err != nil {
+		t.Fatal("error getting tarBall:", err)
+	}
+	defer tarBall.Close()
+
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		fakeK8s,
+		st.NewFake(),
+		nil,
+	)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	opts := &Options{Registry: "registry.luizalabs.com"}
+	r, err := ops.Deploy(u, "teresa", tarBall, "test", opts)
+	if err != nil {
+		t.Fatal("error making deploy:", err)
+	}
+	defer r.Close()
+	ioutil.ReadAll(r)
+
+	if fakeK8s.lastDeploySpec == nil {
+		t.Fatal("expected a deploy spec, got nil")
+	}
+	if !strings.HasPrefix(fakeK8s.lastDeploySpec.Image, "registry.luizalabs.com/teresa:") {
+		t.Errorf("expected image from registry, got %s", fakeK8s.lastDeploySpec.Image)
+	}
+}
+
+func TestDeployDockerfileErrRegistryNotConfigured(t *testing.T) {
+	tarBall, err := os.Open(filepath.Join("testdata", "dockerfile.tgz"))
+	if err != nil {
+		t.Fatal("error getting tarBall:", err)
+	}
+	defer tarBall.Close()
+
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		&fakeK8sOperations{},
+		st.NewFake(),
+		nil,
+	)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	if _, err := ops.Deploy(u, "teresa", tarBall, "test", &Options{}); err != ErrRegistryNotConfigured {
+		t.Errorf("expected ErrRegistryNotConfigured, got %v", err)
+	}
+}
+
 func TestCreateDeploy(t *testing.T) {
 	expectedName := "Test app"
 	a := &app.App{Name: expectedName}
 	expectedDescription := "test-description"
-	expectedSlugURL := "test-slug"
+	expectedSlugPath := "test-slug"
 	opts := &Options{RevisionHistoryLimit: 3}
 
 	fakeK8s := new(fakeK8sOperations)
@@ -107,14 +318,16 @@ func TestCreateDeploy(t *testing.T) {
 		app.NewFakeOperations(),
 		fakeK8s,
 		st.NewFake(),
+		nil,
 	)
 
 	deployOperations := ops.(*DeployOperations)
 	err := deployOperations.createDeploy(
 		a,
-		nil,
+		"123",
+		new(DeployConfigFiles),
 		expectedDescription,
-		expectedSlugURL,
+		expectedSlugPath,
 		opts,
 	)
 
@@ -128,12 +341,15 @@ func TestCreateDeploy(t *testing.T) {
 	if fakeK8s.lastDeploySpec.Description != expectedDescription {
 		t.Errorf("expected %s, got %s", expectedDescription, fakeK8s.lastDeploySpec.Description)
 	}
-	if fakeK8s.lastDeploySpec.SlugURL != expectedSlugURL {
-		t.Errorf("expected %s, got %s", expectedSlugURL, fakeK8s.lastDeploySpec.SlugURL)
+	if fakeK8s.lastDeploySpec.SlugPath != expectedSlugPath {
+		t.Errorf("expected %s, got %s", expectedSlugPath, fakeK8s.lastDeploySpec.SlugPath)
 	}
 	if fakeK8s.lastDeploySpec.RevisionHistoryLimit != opts.RevisionHistoryLimit {
 		t.Errorf("expected %d, got %d", opts.RevisionHistoryLimit, fakeK8s.lastDeploySpec.RevisionHistoryLimit)
 	}
+	if fakeK8s.lastDeploySpec.Annotations[DeployIdAnnotation] != "123" {
+		t.Errorf("expected 123, got %s", fakeK8s.lastDeploySpec.Annotations[DeployIdAnnotation])
+	}
 }
 
 func TestCreateDeployReturnError(t *testing.T) {
@@ -144,12 +360,14 @@ func TestCreateDeployReturnError(t *testing.T) {
 		app.NewFakeOperations(),
 		fakeK8s,
 		st.NewFake(),
+		nil,
 	)
 
 	deployOperations := ops.(*DeployOperations)
 	err := deployOperations.createDeploy(
 		&app.App{Name: "test"},
-		nil,
+		"123",
+		new(DeployConfigFiles),
 		"some desc",
 		"some slug",
 		&Options{},
@@ -160,17 +378,30 @@ func TestCreateDeployReturnError(t *testing.T) {
 	}
 }
 
+func TestFinishDeployDeletesLegacySecret(t *testing.T) {
+	fakeK8s := new(fakeK8sOperations)
+	ops := NewDeployOperations(app.NewFakeOperations(), fakeK8s, st.NewFake(), nil)
+	a := &app.App{Name: "teresa", ProcessType: "worker"}
+
+	ops.(*DeployOperations).finishDeploy(a, new(DeployConfigFiles), "1", new(bytes.Buffer))
+
+	if expected := []string{"teresa/storage-keys"}; !reflect.DeepEqual(fakeK8s.deletedSecrets, expected) {
+		t.Errorf("expected %v, got %v", expected, fakeK8s.deletedSecrets)
+	}
+}
+
 func TestExposeService(t *testing.T) {
 	var testCases = []struct {
 		appProcessType                 string
 		hasSrvReturn                   bool
 		hasSrvErr                      error
 		expectedCreateServiceWasCalled bool
+		expectedUpdateServiceWasCalled bool
 	}{
-		{app.ProcessTypeWeb, false, nil, true},
-		{app.ProcessTypeWeb, true, nil, false},
-		{app.ProcessTypeWeb, false, errors.New("some sad error"), false},
-		{"worker", false, nil, false},
+		{app.ProcessTypeWeb, false, nil, true, false},
+		{app.ProcessTypeWeb, true, nil, false, true},
+		{app.ProcessTypeWeb, false, errors.New("some sad error"), false, false},
+		{"worker", false, nil, false, false},
 	}
 
 	for _, tc := range testCases {
@@ -182,9 +413,10 @@ func TestExposeService(t *testing.T) {
 			app.NewFakeOperations(),
 			fakeK8s,
 			st.NewFake(),
+			nil,
 		)
 		deployOperations := ops.(*DeployOperations)
-		err := deployOperations.exposeService(&app.App{ProcessType: tc.appProcessType}, new(bytes.Buffer))
+		err := deployOperations.exposeService(&app.App{ProcessType: tc.appProcessType}, nil, new(bytes.Buffer))
 		if err != tc.hasSrvErr {
 			t.Error("error exposing service:", err)
 		}
@@ -196,6 +428,41 @@ func TestExposeService(t *testing.T) {
 				fakeK8s.createServiceWasCalled,
 			)
 		}
+		if fakeK8s.updateServiceWasCalled != tc.expectedUpdateServiceWasCalled {
+			t.Errorf(
+				"expected %v, got %v",
+				tc.expectedUpdateServiceWasCalled,
+				fakeK8s.updateServiceWasCalled,
+			)
+		}
+	}
+}
+
+func TestExposeServicePorts(t *testing.T) {
+	fakeK8s := &fakeK8sOperations{}
+	ops := NewDeployOperations(app.NewFakeOperations(), fakeK8s, st.NewFake(), nil)
+	tYaml := &appconfig.TeresaYaml{
+		Port:  8080,
+		Ports: []*appconfig.Port{{Name: "grpc", Port: 50051}, {Name: "metrics", Port: 9100, Protocol: "udp"}},
+	}
+
+	err := ops.(*DeployOperations).exposeService(&app.App{ProcessType: app.ProcessTypeWeb}, tYaml, new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("error exposing service:", err)
+	}
+
+	expected := []ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "TCP"},
+		{Name: "grpc", Port: 50051, TargetPort: 50051, Protocol: "TCP"},
+		{Name: "metrics", Port: 9100, TargetPort: 9100, Protocol: "UDP"},
+	}
+	if len(fakeK8s.lastServicePorts) != len(expected) {
+		t.Fatalf("expected %d ports, got %d", len(expected), len(fakeK8s.lastServicePorts))
+	}
+	for i, p := range fakeK8s.lastServicePorts {
+		if *p != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], *p)
+		}
 	}
 }
 
@@ -219,6 +486,7 @@ func TestBuildApp(t *testing.T) {
 		app.NewFakeOperations(),
 		fakeK8s,
 		st.NewFake(),
+		nil,
 	)
 
 	for _, tc := range testCases {
@@ -259,6 +527,7 @@ func TestRunReleaseCmd(t *testing.T) {
 		app.NewFakeOperations(),
 		fakeK8s,
 		st.NewFake(),
+		nil,
 	)
 
 	for _, tc := range testCases {
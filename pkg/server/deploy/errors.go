@@ -1,6 +1,7 @@
 package deploy
 
 import (
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -10,4 +11,13 @@ var (
 	ErrBuildFail             = status.Errorf(codes.Unknown, "Build returned a non zero value")
 	ErrReleaseFail           = status.Errorf(codes.Unknown, "Release command returned a non zero value")
 	ErrInvalidTeresaYamlFile = status.Errorf(codes.InvalidArgument, "Invalid Teresa Yaml file")
+	ErrInvalidImage          = status.Errorf(codes.InvalidArgument, "Invalid image name")
+	ErrRegistryNotConfigured = status.Errorf(codes.FailedPrecondition, "No registry configured for Dockerfile builds")
+	ErrDeployNotFound        = status.Errorf(codes.NotFound, "Deploy not found")
+	ErrDuplicateInfo         = status.Errorf(codes.InvalidArgument, "Only one info message is allowed per deploy")
+	ErrInvalidSlugConfig     = status.Errorf(codes.FailedPrecondition, "No server url and slug signing key configured for slug deploys")
 )
+
+func newInvalidConfigErr(err *appconfig.ValidationError) error {
+	return status.Errorf(codes.InvalidArgument, "Invalid config files: %s", err)
+}
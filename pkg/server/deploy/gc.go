@@ -0,0 +1,148 @@
+package deploy
+
+import (
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+)
+
+const deploysPrefix = "deploys/"
+
+// GarbageCollector deletes from the storage the artifacts (tarballs and
+// slugs) of the deploys no longer referenced by the revisions kept by
+// the app deployments, nor used by the revisions of any app (as the
+// promoted ones).
+type GarbageCollector struct {
+	k8s         K8sOperations
+	fileStorage st.Storage
+	opts        *Options
+	now         func() time.Time
+}
+
+// Collect returns the paths of the artifacts deleted, or that would be
+// deleted when dryRun is set. The artifacts of apps without revisions and
+// the ones newer than GCMinAge (as the ones of a running build) are kept.
+func (gc *GarbageCollector) Collect(dryRun bool) ([]string, error) {
+	objs, err := gc.fileStorage.List(deploysPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse, err := gc.slugsInUse()
+	if err != nil {
+		return nil, err
+	}
+
+	byApp := make(map[string][]*st.Object)
+	for _, o := range objs {
+		appName, deployId := parseArtifactPath(o.Path)
+		if appName == "" || deployId == "" {
+			continue
+		}
+		byApp[appName] = append(byApp[appName], o)
+	}
+
+	var deleted []string
+	minTime := gc.now().Add(-gc.opts.GCMinAge)
+	for appName, objs := range byApp {
+		keep, err := gc.referencedDeploys(appName)
+		if err != nil {
+			log.WithError(err).Errorf("Getting the deploys of app %s", appName)
+			continue
+		}
+		if len(keep) == 0 {
+			continue
+		}
+
+		for _, o := range objs {
+			_, deployId := parseArtifactPath(o.Path)
+			if keep[deployId] || inUse[appName+"/"+deployId] || o.LastModified.After(minTime) {
+				continue
+			}
+			if !dryRun {
+				if err := gc.fileStorage.Delete(o.Path); err != nil {
+					log.WithError(err).Errorf("Deleting artifact %s", o.Path)
+					continue
+				}
+			}
+			deleted = append(deleted, o.Path)
+		}
+	}
+	return deleted, nil
+}
+
+func (gc *GarbageCollector) referencedDeploys(appName string) (map[string]bool, error) {
+	ids, err := gc.k8s.DeployIds(appName)
+	if err != nil {
+		if gc.k8s.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// the current revision plus the RevisionHistoryLimit old ones
+	if limit := gc.opts.RevisionHistoryLimit + 1; len(ids) > limit {
+		ids = ids[:limit]
+	}
+	keep := make(map[string]bool)
+	for _, id := range ids {
+		keep[id] = true
+	}
+	return keep, nil
+}
+
+// slugsInUse returns the <app>/<deploy id> of the slugs used by the
+// revisions of all apps, a slug promoted to another app stays in the
+// directory of the deploys of the app it was built for.
+func (gc *GarbageCollector) slugsInUse() (map[string]bool, error) {
+	slugURLs, err := gc.k8s.SlugURLs()
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for _, slugURL := range slugURLs {
+		appName, deployId := parseArtifactPath(slugPathFromURL(slugURL))
+		if appName != "" && deployId != "" {
+			inUse[appName+"/"+deployId] = true
+		}
+	}
+	return inUse, nil
+}
+
+// Run collects the garbage every GCInterval, forever.
+func (gc *GarbageCollector) Run() {
+	for range time.Tick(gc.opts.GCInterval) {
+		deleted, err := gc.Collect(false)
+		if err != nil {
+			log.WithError(err).Error("Collecting deploy artifacts")
+			continue
+		}
+		log.Infof("Deleted %d deploy artifacts", len(deleted))
+	}
+}
+
+// parseArtifactPath returns the app and the deploy id of artifacts in the
+// form deploys/<app>/<deploy id>/..., other paths (like the build cache)
+// return empty strings.
+func parseArtifactPath(path string) (string, string) {
+	parts := strings.Split(strings.TrimPrefix(path, deploysPrefix), "/")
+	if len(parts) < 3 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// DeployIdFromSlugURL returns the deploy id of a slug path or of a signed
+// slug URL.
+func DeployIdFromSlugURL(slugURL string) string {
+	_, deployId := parseArtifactPath(slugPathFromURL(slugURL))
+	return deployId
+}
+
+func NewGarbageCollector(k8s K8sOperations, s st.Storage, opts *Options) *GarbageCollector {
+	return &GarbageCollector{k8s: k8s, fileStorage: s, opts: opts, now: time.Now}
+}
@@ -0,0 +1,138 @@
+package deploy
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+)
+
+type gcFakeStorage struct {
+	st.Storage
+	objs    []*st.Object
+	deleted []string
+}
+
+func (f *gcFakeStorage) List(prefix string) ([]*st.Object, error) {
+	return f.objs, nil
+}
+
+func (f *gcFakeStorage) Delete(path string) error {
+	f.deleted = append(f.deleted, path)
+	return nil
+}
+
+func TestGarbageCollectorCollect(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	fakeSt := &gcFakeStorage{
+		Storage: st.NewFake(),
+		objs: []*st.Object{
+			{Path: "deploys/teresa/cache.tgz", LastModified: old},
+			{Path: "deploys/teresa/1/in/app.tar.gz", LastModified: old},
+			{Path: "deploys/teresa/1/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/2/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/3/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/4/out/slug.tgz", LastModified: now},
+			{Path: "deploys/gone/1/out/slug.tgz", LastModified: old},
+		},
+	}
+	fakeK8s := &fakeK8sOperations{
+		deployIds: map[string][]string{"teresa": {"3", "2"}},
+	}
+	opts := &Options{RevisionHistoryLimit: 0, GCMinAge: 24 * time.Hour}
+	gc := NewGarbageCollector(fakeK8s, fakeSt, opts)
+
+	var testCases = []struct {
+		dryRun          bool
+		expectedDeleted []string
+	}{
+		{true, nil},
+		{false, []string{
+			"deploys/teresa/1/in/app.tar.gz",
+			"deploys/teresa/1/out/slug.tgz",
+			"deploys/teresa/2/out/slug.tgz",
+		}},
+	}
+
+	for _, tc := range testCases {
+		fakeSt.deleted = nil
+		deleted, err := gc.Collect(tc.dryRun)
+		if err != nil {
+			t.Fatal("error collecting garbage: ", err)
+		}
+		sort.Strings(deleted)
+		sort.Strings(fakeSt.deleted)
+
+		expected := strings.Join([]string{
+			"deploys/teresa/1/in/app.tar.gz",
+			"deploys/teresa/1/out/slug.tgz",
+			"deploys/teresa/2/out/slug.tgz",
+		}, ",")
+		if actual := strings.Join(deleted, ","); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+		if actual, exp := strings.Join(fakeSt.deleted, ","), strings.Join(tc.expectedDeleted, ","); actual != exp {
+			t.Errorf("expected deleted from storage %s, got %s", exp, actual)
+		}
+	}
+}
+
+func TestGarbageCollectorCollectKeepsPromotedSlugs(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	fakeSt := &gcFakeStorage{
+		Storage: st.NewFake(),
+		objs: []*st.Object{
+			{Path: "deploys/teresa/1/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/2/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/3/out/slug.tgz", LastModified: old},
+			{Path: "deploys/teresa/4/out/slug.tgz", LastModified: old},
+		},
+	}
+	fakeK8s := &fakeK8sOperations{
+		// deploy 1 of teresa was promoted to teresa-prod as deploy 5 and
+		// teresa rolled past its history limit
+		deployIds: map[string][]string{
+			"teresa":      {"4"},
+			"teresa-prod": {"5"},
+		},
+		slugURLs: []string{
+			"deploys/teresa/4/out/slug.tgz",
+			"https://teresa.luizalabs.com/slugs/deploys/teresa/1/out/slug.tgz?expires=1&signature=abc",
+		},
+	}
+	opts := &Options{RevisionHistoryLimit: 0, GCMinAge: 24 * time.Hour}
+	gc := NewGarbageCollector(fakeK8s, fakeSt, opts)
+
+	deleted, err := gc.Collect(false)
+	if err != nil {
+		t.Fatal("error collecting garbage: ", err)
+	}
+	sort.Strings(deleted)
+
+	expected := "deploys/teresa/2/out/slug.tgz,deploys/teresa/3/out/slug.tgz"
+	if actual := strings.Join(deleted, ","); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestParseArtifactPath(t *testing.T) {
+	var testCases = []struct {
+		path             string
+		expectedApp      string
+		expectedDeployId string
+	}{
+		{"deploys/teresa/abc/out/slug.tgz", "teresa", "abc"},
+		{"deploys/teresa/cache.tgz", "", ""},
+		{"other", "", ""},
+	}
+
+	for _, tc := range testCases {
+		appName, deployId := parseArtifactPath(tc.path)
+		if appName != tc.expectedApp || deployId != tc.expectedDeployId {
+			t.Errorf("expected %s and %s, got %s and %s", tc.expectedApp, tc.expectedDeployId, appName, deployId)
+		}
+	}
+}
@@ -21,6 +21,16 @@ type Options struct {
 	RevisionHistoryLimit int           `split_words:"true" default:"5"`
 	SlugBuilderImage     string        `split_words:"true" default:"luizalabs/slugbuilder:v2.4.9"`
 	SlugRunnerImage      string        `split_words:"true" default:"luizalabs/slugrunner:v2.2.4"`
+	ImageBuilderImage    string        `split_words:"true" default:"luizalabs/imagebuilder:v0.1.0"`
+	Registry             string        `split_words:"true"`
+	RegistrySecretName   string        `split_words:"true"`
+	GCInterval           time.Duration `split_words:"true" default:"24h"`
+	GCMinAge             time.Duration `split_words:"true" default:"24h"`
+	ServerURL            string        `envconfig:"server_url"`
+	SlugSigningKey       string        `split_words:"true"`
+	SlugURLExpiry        time.Duration `envconfig:"slug_url_expiry" default:"8760h"`
+	PresignExpiry        time.Duration `split_words:"true" default:"1h"`
+	NoCache              bool          `ignored:"true"`
 }
 
 type Service struct {
@@ -29,7 +39,8 @@ type Service struct {
 }
 
 func (s *Service) Make(stream dpb.Deploy_MakeServer) error {
-	var appName, description string
+	var appName, description, image string
+	var noCache, gotInfo bool
 	content := new(bytes.Buffer)
 
 	ctx := stream.Context()
@@ -44,21 +55,66 @@ func (s *Service) Make(stream dpb.Deploy_MakeServer) error {
 			return err
 		}
 		if info := in.GetInfo(); info != nil {
+			if gotInfo {
+				return ErrDuplicateInfo
+			}
+			gotInfo = true
 			appName = info.App
 			description = info.Description
+			image = info.Image
+			noCache = info.NoCache
 		}
 		if data := in.GetFile(); data != nil {
 			content.Write(data.Chunk)
 		}
 	}
 
-	rs := bytes.NewReader(content.Bytes())
-	rc, err := s.ops.Deploy(u, appName, rs, description, s.options)
+	opts := s.options
+	if noCache {
+		o := *s.options
+		o.NoCache = true
+		opts = &o
+	}
+
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	if image != "" {
+		var rs io.ReadSeeker
+		if content.Len() > 0 {
+			rs = bytes.NewReader(content.Bytes())
+		}
+		rc, err = s.ops.DeployImage(u, appName, image, rs, description, opts)
+	} else {
+		rs := bytes.NewReader(content.Bytes())
+		rc, err = s.ops.Deploy(u, appName, rs, description, opts)
+	}
 	if err != nil {
 		return err
 	}
 	defer rc.Close()
 
+	return s.streamDeployMsgs(rc, stream)
+}
+
+func (s *Service) Promote(req *dpb.PromoteRequest, stream dpb.Deploy_PromoteServer) error {
+	u := stream.Context().Value("user").(*storage.User)
+
+	rc, err := s.ops.Promote(u, req.FromApp, req.ToApp, req.DeployId, req.Description, s.options)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return s.streamDeployMsgs(rc, stream)
+}
+
+type deployMsgSender interface {
+	Send(*dpb.DeployResponse) error
+}
+
+func (s *Service) streamDeployMsgs(rc io.Reader, stream deployMsgSender) error {
 	deployMsgs := goutil.ChannelFromReader(rc, true)
 	var msg string
 
@@ -0,0 +1,46 @@
+package deploy
+
+import (
+	"io"
+	"testing"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	dpb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
+)
+
+type fakeMakeServer struct {
+	grpc.ServerStream
+	msgs []*dpb.DeployRequest
+}
+
+func (f *fakeMakeServer) Context() context.Context {
+	return context.WithValue(context.Background(), "user", &storage.User{Email: "gopher@luizalabs.com"})
+}
+
+func (f *fakeMakeServer) Recv() (*dpb.DeployRequest, error) {
+	if len(f.msgs) == 0 {
+		return nil, io.EOF
+	}
+	m := f.msgs[0]
+	f.msgs = f.msgs[1:]
+	return m, nil
+}
+
+func (f *fakeMakeServer) Send(*dpb.DeployResponse) error {
+	return nil
+}
+
+func TestServiceMakeDuplicateInfo(t *testing.T) {
+	info := func(app string) *dpb.DeployRequest {
+		return &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{Info: &dpb.DeployRequest_Info{App: app}}}
+	}
+	s := NewService(nil, &Options{})
+	stream := &fakeMakeServer{msgs: []*dpb.DeployRequest{info("teresa"), info("other")}}
+
+	if err := s.Make(stream); err != ErrDuplicateInfo {
+		t.Errorf("expected ErrDuplicateInfo, got %v", err)
+	}
+}
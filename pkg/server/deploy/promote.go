@@ -0,0 +1,131 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
+)
+
+const (
+	DeployIdAnnotation   = "teresa.io/deploy-id"
+	SlugPathAnnotation   = "teresa.io/slug"
+	ImageAnnotation      = "teresa.io/image"
+	TeresaYamlAnnotation = "teresa.io/teresa-yaml"
+	ProcfileAnnotation   = "teresa.io/procfile"
+)
+
+// newDeployAnnotations returns the annotations kept in the pod template of
+// the deploy, they have all it takes to deploy the same artifact again.
+func newDeployAnnotations(deployId string, confFiles *DeployConfigFiles, slugPath, image string) map[string]string {
+	an := map[string]string{DeployIdAnnotation: deployId}
+	if slugPath != "" {
+		an[SlugPathAnnotation] = slugPath
+	}
+	if image != "" {
+		an[ImageAnnotation] = image
+	}
+	if confFiles == nil {
+		return an
+	}
+	if confFiles.TeresaYaml != nil {
+		if b, err := yaml.Marshal(confFiles.TeresaYaml); err == nil {
+			an[TeresaYamlAnnotation] = string(b)
+		}
+	}
+	if confFiles.Procfile != nil {
+		if b, err := yaml.Marshal(confFiles.Procfile); err == nil {
+			an[ProcfileAnnotation] = string(b)
+		}
+	}
+	return an
+}
+
+func deployConfigFilesFromAnnotations(an map[string]string) (*DeployConfigFiles, error) {
+	confFiles := new(DeployConfigFiles)
+	if v, ok := an[TeresaYamlAnnotation]; ok {
+		confFiles.TeresaYaml = new(appconfig.TeresaYaml)
+		if err := yaml.Unmarshal([]byte(v), confFiles.TeresaYaml); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := an[ProcfileAnnotation]; ok {
+		confFiles.Procfile = make(appconfig.Procfile)
+		if err := yaml.Unmarshal([]byte(v), &confFiles.Procfile); err != nil {
+			return nil, err
+		}
+	}
+	return confFiles, nil
+}
+
+// Promote deploys the artifact (slug or image) and the teresa.yaml of the
+// deploy deployId of fromApp in toApp, with the env vars and the release
+// command of toApp.
+func (ops *DeployOperations) Promote(user *storage.User, fromApp, toApp, deployId, description string, opts *Options) (io.ReadCloser, error) {
+	if !ops.appOps.HasPermission(user, fromApp, team.RoleMember) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	a, err := ops.appOps.Get(toApp)
+	if err != nil {
+		return nil, err
+	}
+
+	teamName, err := ops.appOps.TeamName(toApp)
+	if err != nil {
+		return nil, err
+	}
+	a.Team = teamName
+
+	if !ops.appOps.HasPermission(user, toApp, team.RoleMember) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	an, err := ops.k8s.DeployAnnotations(fromApp, deployId)
+	if err != nil {
+		if ops.k8s.IsNotFound(err) {
+			return nil, teresa_errors.New(ErrDeployNotFound, err)
+		}
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+
+	confFiles, err := deployConfigFilesFromAnnotations(an)
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+
+	slugPath, image := an[SlugPathAnnotation], an[ImageAnnotation]
+	if slugPath == "" && image == "" {
+		return nil, ErrDeployNotFound
+	}
+	if image == "" && !slugConfigured(opts) {
+		return nil, ErrInvalidSlugConfig
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("promoted from %s (deploy id: %s)", fromApp, deployId)
+	}
+	newDeployId := genDeployId()
+
+	ops.notify(webhook.DeployStarted, user, a, newDeployId, description, nil)
+	r, w := io.Pipe()
+	go func() {
+		defer w.Close()
+		fmt.Fprintf(w, "Promoting deploy %s of app %s to app %s\n", deployId, fromApp, toApp)
+		var err error
+		if image != "" {
+			err = ops.releaseAndDeployImage(a, confFiles, newDeployId, image, description, w, opts)
+		} else {
+			err = ops.releaseAndDeploySlug(a, confFiles, newDeployId, slugPath, description, w, opts)
+		}
+		ops.notifyResult(user, a, newDeployId, description, err)
+	}()
+	return r, nil
+}
@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
+	"github.com/luizalabs/teresa-api/pkg/server/app"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+func TestDeployAnnotations(t *testing.T) {
+	confFiles := &DeployConfigFiles{
+		TeresaYaml: &appconfig.TeresaYaml{
+			HealthCheck: &appconfig.HealthCheck{
+				Liveness: &appconfig.HealthCheckProbe{Path: "/healthcheck/"},
+			},
+		},
+		Procfile: appconfig.Procfile{"release": "./migrate"},
+	}
+
+	an := newDeployAnnotations("123", confFiles, "deploys/teresa/123/out/slug.tgz", "")
+	if an[DeployIdAnnotation] != "123" {
+		t.Errorf("expected 123, got %s", an[DeployIdAnnotation])
+	}
+	if _, found := an[ImageAnnotation]; found {
+		t.Error("expected no image annotation")
+	}
+
+	actual, err := deployConfigFilesFromAnnotations(an)
+	if err != nil {
+		t.Fatal("error reading annotations:", err)
+	}
+	if actual.TeresaYaml.HealthCheck.Liveness.Path != "/healthcheck/" {
+		t.Errorf("expected /healthcheck/, got %s", actual.TeresaYaml.HealthCheck.Liveness.Path)
+	}
+	if actual.Procfile["release"] != "./migrate" {
+		t.Errorf("expected ./migrate, got %s", actual.Procfile["release"])
+	}
+}
+
+func TestPromote(t *testing.T) {
+	expectedSlug := "deploys/teresa-staging/123/out/slug.tgz"
+	fakeK8s := &fakeK8sOperations{
+		deployAnnotations: map[string]string{
+			DeployIdAnnotation: "123",
+			SlugPathAnnotation: expectedSlug,
+		},
+	}
+	ops := NewDeployOperations(app.NewFakeOperations(), fakeK8s, st.NewFake(), nil)
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+
+	opts := &Options{ServerURL: "https://teresa.io", SlugSigningKey: "key"}
+	r, err := ops.Promote(u, "teresa-staging", "teresa", "123", "", opts)
+	if err != nil {
+		t.Fatal("error promoting deploy:", err)
+	}
+	defer r.Close()
+	ioutil.ReadAll(r)
+
+	if fakeK8s.lastDeploySpec == nil {
+		t.Fatal("expected a deploy spec, got nil")
+	}
+	if fakeK8s.lastDeploySpec.SlugPath != expectedSlug {
+		t.Errorf("expected %s, got %s", expectedSlug, fakeK8s.lastDeploySpec.SlugPath)
+	}
+	if id := fakeK8s.lastDeploySpec.Annotations[DeployIdAnnotation]; id == "123" {
+		t.Error("expected a new deploy id")
+	}
+}
+
+func TestPromoteErrors(t *testing.T) {
+	var testCases = []struct {
+		email       string
+		annotations map[string]string
+		expectedErr error
+	}{
+		{"bad-user@luizalabs.com", nil, auth.ErrPermissionDenied},
+		{"gopher@luizalabs.com", nil, ErrDeployNotFound},
+		{"gopher@luizalabs.com", map[string]string{DeployIdAnnotation: "123"}, ErrDeployNotFound},
+	}
+
+	for _, tc := range testCases {
+		fakeK8s := &fakeK8sOperations{deployAnnotations: tc.annotations}
+		ops := NewDeployOperations(app.NewFakeOperations(), fakeK8s, st.NewFake(), nil)
+		u := &storage.User{Email: tc.email}
+
+		_, err := ops.Promote(u, "teresa-staging", "teresa", "123", "", &Options{})
+		if teresa_errors.Get(err) != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
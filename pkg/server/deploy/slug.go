@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+)
+
+// SlugsHTTPPath is where the SlugHandler is served.
+const SlugsHTTPPath = "/slugs/"
+
+// signSlugPath returns the signature of a slug path valid until expires
+// (unix time); unlike the presigned storage URLs it lasts SlugURLExpiry, so
+// the deploys can keep using it while the replicas are recreated.
+func signSlugPath(key, path, expires string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	fmt.Fprintf(mac, "%s\n%s", path, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// slugURL returns the URL the slug runner downloads the slug from.
+func slugURL(slugPath string, opts *Options) string {
+	exp := strconv.FormatInt(time.Now().Add(opts.SlugURLExpiry).Unix(), 10)
+	q := url.Values{
+		"expires":   {exp},
+		"signature": {signSlugPath(opts.SlugSigningKey, slugPath, exp)},
+	}
+	return fmt.Sprintf(
+		"%s%s%s?%s",
+		strings.TrimSuffix(opts.ServerURL, "/"),
+		SlugsHTTPPath,
+		slugPath,
+		q.Encode(),
+	)
+}
+
+// slugConfigured reports whether the slug URLs can be made; only the
+// deploys of slugs need them, the image deploys work without.
+func slugConfigured(opts *Options) bool {
+	return opts.ServerURL != "" && opts.SlugSigningKey != ""
+}
+
+// SlugHandler redirects the signed slug URLs to short lived presigned
+// storage URLs.
+type SlugHandler struct {
+	fileStorage st.Storage
+	opts        *Options
+	now         func() time.Time
+}
+
+// validSignature checks the signature and the expiration of a slug URL.
+func (h *SlugHandler) validSignature(r *http.Request, p string) bool {
+	q := r.URL.Query()
+	exp := q.Get("expires")
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || h.now().Unix() > expUnix {
+		return false
+	}
+	sig := signSlugPath(h.opts.SlugSigningKey, p, exp)
+	return hmac.Equal([]byte(sig), []byte(q.Get("signature")))
+}
+
+func (h *SlugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	p := strings.TrimPrefix(r.URL.Path, SlugsHTTPPath)
+	if !h.validSignature(r, p) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	u, err := h.fileStorage.PresignGet(p, h.opts.PresignExpiry)
+	if err != nil {
+		log.WithError(err).Errorf("Presigning slug %s", p)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, u, http.StatusTemporaryRedirect)
+}
+
+func NewSlugHandler(s st.Storage, opts *Options) *SlugHandler {
+	return &SlugHandler{fileStorage: s, opts: opts, now: time.Now}
+}
+
+// slugPathFromURL returns the slug path of a signed slug URL, other values
+// are returned as they are.
+func slugPathFromURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return s
+	}
+	if i := strings.Index(u.Path, SlugsHTTPPath); i >= 0 {
+		return u.Path[i+len(SlugsHTTPPath):]
+	}
+	return u.Path
+}
@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	st "github.com/luizalabs/teresa-api/pkg/server/storage"
+)
+
+func TestSlugURL(t *testing.T) {
+	opts := &Options{ServerURL: "https://teresa.io/", SlugSigningKey: "key", SlugURLExpiry: time.Hour}
+	p := "deploys/teresa/123/out/slug.tgz"
+
+	u := slugURL(p, opts)
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal("error parsing slug url: ", err)
+	}
+	if expected := "https://teresa.io/slugs/" + p; !strings.HasPrefix(u, expected+"?") {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+	q := parsed.Query()
+	if sig := signSlugPath("key", p, q.Get("expires")); q.Get("signature") != sig {
+		t.Errorf("expected signature %s, got %s", sig, q.Get("signature"))
+	}
+	if actual := slugPathFromURL(u); actual != p {
+		t.Errorf("expected %s, got %s", p, actual)
+	}
+	if actual := DeployIdFromSlugURL(u); actual != "123" {
+		t.Errorf("expected 123, got %s", actual)
+	}
+}
+
+func TestSlugHandler(t *testing.T) {
+	opts := &Options{ServerURL: "https://teresa.io", SlugSigningKey: "key", SlugURLExpiry: time.Hour}
+	h := NewSlugHandler(st.NewFake(), opts)
+	expired := &Options{ServerURL: "https://teresa.io", SlugSigningKey: "key", SlugURLExpiry: -time.Hour}
+	p := "deploys/teresa/123/out/slug.tgz"
+
+	var testCases = []struct {
+		method   string
+		url      string
+		expected int
+	}{
+		{http.MethodGet, slugURL(p, opts), http.StatusTemporaryRedirect},
+		{http.MethodHead, slugURL(p, opts), http.StatusTemporaryRedirect},
+		{http.MethodGet, slugURL(p, &Options{SlugSigningKey: "other", SlugURLExpiry: time.Hour}), http.StatusForbidden},
+		{http.MethodGet, slugURL(p, expired), http.StatusForbidden},
+		{http.MethodGet, strings.Replace(slugURL(p, opts), "expires=", "expires=9", 1), http.StatusForbidden},
+		{http.MethodGet, "/slugs/" + p, http.StatusForbidden},
+		{http.MethodPut, slugURL(p, opts), http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(t, tc.method, tc.url))
+		if w.Code != tc.expected {
+			t.Errorf("expected %d for %s %s, got %d", tc.expected, tc.method, tc.url, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(t, http.MethodGet, slugURL(p, opts)))
+	if loc := w.Header().Get("Location"); loc != "https://bucket/"+p+"?method=GET" {
+		t.Errorf("expected presigned url, got %s", loc)
+	}
+}
+
+func newRequest(t *testing.T, method, url string) *http.Request {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal("error creating request: ", err)
+	}
+	return req
+}
@@ -0,0 +1,106 @@
+package gitpush
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// archiveURL fills the archive URL template with the repo and the commit
+// sha of the push. {repo_escaped} is the repo with the slash escaped, as
+// required by the GitLab API.
+func archiveURL(tmpl string, p *push) string {
+	r := strings.NewReplacer(
+		"{repo}", p.Repo,
+		"{repo_escaped}", url.QueryEscape(p.Repo),
+		"{sha}", p.Sha,
+	)
+	return r.Replace(tmpl)
+}
+
+func (h *Handler) fetchArchive(p *push) (*os.File, error) {
+	req, err := http.NewRequest("GET", archiveURL(h.opts.ArchiveURL, p), nil)
+	if err != nil {
+		return nil, err
+	}
+	if h.opts.ArchiveToken != "" {
+		req.Header.Set(h.opts.ArchiveTokenHeader, h.opts.ArchiveToken)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching the archive", resp.StatusCode)
+	}
+
+	f, err := ioutil.TempFile("", "gitpush")
+	if err != nil {
+		return nil, err
+	}
+	if err := stripArchivePrefix(resp.Body, f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+// stripArchivePrefix rewrites the archive without the top level directory
+// added by GitHub and GitLab (repo-sha/), leaving the teresa.yaml and the
+// Procfile in the root as in the archives sent by the client.
+func stripArchivePrefix(r io.Reader, w io.Writer) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		i := strings.Index(hdr.Name, "/")
+		if i == -1 || i == len(hdr.Name)-1 {
+			continue
+		}
+		hdr.Name = hdr.Name[i+1:]
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,146 @@
+package gitpush
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
+	"github.com/luizalabs/teresa-api/pkg/server/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+const (
+	Path         = "/gitpush/"
+	maxBodyBytes = 5 << 20
+	// auditMethod is the method of the audit entries of the git push deploys
+	auditMethod = "/gitpush.GitPush/Deploy"
+)
+
+type Options struct {
+	Secret             string            `envconfig:"secret"`
+	ArchiveURL         string            `split_words:"true"`
+	ArchiveToken       string            `split_words:"true"`
+	ArchiveTokenHeader string            `split_words:"true" default:"Authorization"`
+	ArchiveTimeout     time.Duration     `split_words:"true" default:"2m"`
+	User               string            `envconfig:"user"`
+	Apps               map[string]string `envconfig:"apps"`
+}
+
+// Enabled tells if push webhooks are configured; without a secret the
+// endpoint is not served at all.
+func (o *Options) Enabled() bool {
+	return o.Secret != "" && o.ArchiveURL != "" && o.User != ""
+}
+
+// Handler receives GitHub and GitLab push webhooks and deploys the pushed
+// commit in the app configured for the repo and branch.
+type Handler struct {
+	deployOps  deploy.Operations
+	userOps    user.Operations
+	auditOps   audit.Operations
+	opts       *Options
+	deployOpts *deploy.Options
+	client     *http.Client
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+	if err := verifySignature(r, body, h.opts.Secret); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var p *push
+	switch {
+	case r.Header.Get(githubEventHeader) == githubPingEvent:
+		fmt.Fprintln(w, "pong")
+		return
+	case r.Header.Get(githubEventHeader) == githubPushEvent:
+		p, err = parseGithubPush(body)
+	case r.Header.Get(gitlabEventHeader) == gitlabPushEvent:
+		p, err = parseGitlabPush(body)
+	default:
+		fmt.Fprintln(w, "ignoring event")
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid push payload", http.StatusBadRequest)
+		return
+	}
+
+	if !p.isBranchPush() {
+		fmt.Fprintln(w, "ignoring push, not a branch update")
+		return
+	}
+	appName, found := h.opts.Apps[p.key()]
+	if !found {
+		fmt.Fprintf(w, "no app configured for %s\n", p.key())
+		return
+	}
+
+	go h.deploy(appName, p)
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "deploying %s in app %s\n", p.Sha, appName)
+}
+
+func (h *Handler) deploy(appName string, p *push) {
+	logger := log.WithField("app", appName).WithField("sha", p.Sha)
+
+	u, err := h.userOps.GetUser(h.opts.User)
+	if err != nil {
+		logger.WithError(err).Errorf("Getting git push user %s", h.opts.User)
+		return
+	}
+
+	f, err := h.fetchArchive(p)
+	if err != nil {
+		logger.WithError(err).Error("Fetching archive of git push")
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	opts := *h.deployOpts
+	rc, err := h.deployOps.Deploy(u, appName, f, p.description(), &opts)
+	params := map[string]string{"app": appName, "ref": p.key(), "sha": p.Sha}
+	if err := h.auditOps.Record(audit.NewEntry(u, auditMethod, params, err)); err != nil {
+		logger.WithError(err).Error("Recording audit entry of git push")
+	}
+	if err != nil {
+		logger.WithError(err).Error("Deploying git push")
+		return
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		logger.Info(scanner.Text())
+	}
+}
+
+func NewHandler(dOps deploy.Operations, uOps user.Operations, aOps audit.Operations, opts *Options, deployOpts *deploy.Options) *Handler {
+	return &Handler{
+		deployOps:  dOps,
+		userOps:    uOps,
+		auditOps:   aOps,
+		opts:       opts,
+		deployOpts: deployOpts,
+		client:     &http.Client{Timeout: opts.ArchiveTimeout},
+	}
+}
@@ -0,0 +1,256 @@
+package gitpush
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
+	"github.com/luizalabs/teresa-api/pkg/server/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+type deployCall struct {
+	user        string
+	app         string
+	files       []string
+	description string
+}
+
+type fakeDeployOperations struct {
+	calls chan *deployCall
+}
+
+func (f *fakeDeployOperations) Deploy(u *storage.User, appName string, tarBall io.ReadSeeker, description string, opts *deploy.Options) (io.ReadCloser, error) {
+	f.calls <- &deployCall{
+		user:        u.Email,
+		app:         appName,
+		files:       tarFileNames(tarBall),
+		description: description,
+	}
+	return ioutil.NopCloser(strings.NewReader("done\n")), nil
+}
+
+func (f *fakeDeployOperations) DeployImage(u *storage.User, appName, image string, tarBall io.ReadSeeker, description string, opts *deploy.Options) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (f *fakeDeployOperations) Promote(u *storage.User, fromApp, toApp, deployId, description string, opts *deploy.Options) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func tarFileNames(r io.Reader) []string {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil
+	}
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return names
+		}
+		names = append(names, hdr.Name)
+	}
+}
+
+func newArchive(t *testing.T, files ...string) []byte {
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	tw.WriteHeader(&tar.Header{Name: "pax_global_header", Typeflag: tar.TypeXGlobalHeader})
+	tw.WriteHeader(&tar.Header{Name: "teresa-abc123/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, name := range files {
+		hdr := &tar.Header{Name: "teresa-abc123/" + name, Mode: 0644, Size: 2}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal("error writing archive: ", err)
+		}
+		tw.Write([]byte("ok"))
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+const githubPayload = `{
+	"ref": "refs/heads/master",
+	"after": "abc123",
+	"repository": {"full_name": "luizalabs/teresa"},
+	"head_commit": {"message": "Fix the deploy\n\nLong description"}
+}`
+
+func newTestHandler(t *testing.T, archiveURL string) (*Handler, *fakeDeployOperations) {
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).Storage["gopher@luizalabs.com"] = &storage.User{Email: "gopher@luizalabs.com"}
+
+	dOps := &fakeDeployOperations{calls: make(chan *deployCall, 1)}
+	opts := &Options{
+		Secret:             "secret",
+		ArchiveURL:         archiveURL + "/{repo}/archive/{sha}.tar.gz",
+		ArchiveTokenHeader: "Authorization",
+		User:               "gopher@luizalabs.com",
+		Apps:               map[string]string{"luizalabs/teresa@master": "teresa"},
+	}
+	return NewHandler(dOps, uOps, audit.NewFakeOperations(), opts, &deploy.Options{}), dOps
+}
+
+func newRequest(t *testing.T, method, url string, body io.Reader) *http.Request {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal("error creating request: ", err)
+	}
+	return req
+}
+
+func TestHandlerGithubPush(t *testing.T) {
+	var archivePath string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivePath = r.URL.Path
+		w.Write(newArchive(t, "teresa.yaml", "Procfile"))
+	}))
+	defer ts.Close()
+
+	h, dOps := newTestHandler(t, ts.URL)
+
+	body := []byte(githubPayload)
+	req := newRequest(t, "POST", Path, bytes.NewReader(body))
+	req.Header.Set(githubEventHeader, githubPushEvent)
+	req.Header.Set(githubSignatureHeader, sign("secret", body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	var call *deployCall
+	select {
+	case call = <-dOps.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the deploy")
+	}
+
+	if expected := "/luizalabs/teresa/archive/abc123.tar.gz"; archivePath != expected {
+		t.Errorf("expected %s, got %s", expected, archivePath)
+	}
+	if call.app != "teresa" {
+		t.Errorf("expected teresa, got %s", call.app)
+	}
+	if call.user != "gopher@luizalabs.com" {
+		t.Errorf("expected gopher@luizalabs.com, got %s", call.user)
+	}
+	if expected := "abc123 Fix the deploy"; call.description != expected {
+		t.Errorf("expected %s, got %s", expected, call.description)
+	}
+	if strings.Join(call.files, ",") != "teresa.yaml,Procfile" {
+		t.Errorf("expected teresa.yaml,Procfile, got %v", call.files)
+	}
+}
+
+func TestHandlerIgnoredRequests(t *testing.T) {
+	var testCases = []struct {
+		header       string
+		event        string
+		signature    string
+		payload      string
+		expectedCode int
+	}{
+		{githubEventHeader, githubPushEvent, "sha256=00", githubPayload, http.StatusUnauthorized},
+		{githubEventHeader, githubPushEvent, "", githubPayload, http.StatusUnauthorized},
+		{githubEventHeader, githubPingEvent, "sign", `{}`, http.StatusOK},
+		{githubEventHeader, githubPushEvent, "sign", strings.Replace(githubPayload, "master", "develop", 1), http.StatusOK},
+		{githubEventHeader, githubPushEvent, "sign", strings.Replace(githubPayload, "heads", "tags", 1), http.StatusOK},
+		{githubEventHeader, githubPushEvent, "sign", `not json`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		h, dOps := newTestHandler(t, "http://localhost")
+		body := []byte(tc.payload)
+		req := newRequest(t, "POST", Path, bytes.NewReader(body))
+		req.Header.Set(tc.header, tc.event)
+		switch tc.signature {
+		case "sign":
+			req.Header.Set(githubSignatureHeader, sign("secret", body))
+		case "":
+		default:
+			req.Header.Set(githubSignatureHeader, tc.signature)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		if w.Code != tc.expectedCode {
+			t.Errorf("expected %d, got %d (%s)", tc.expectedCode, w.Code, w.Body.String())
+		}
+		if len(dOps.calls) != 0 {
+			t.Errorf("expected no deploy for %s", tc.payload)
+		}
+	}
+}
+
+func TestParseGitlabPush(t *testing.T) {
+	payload := `{
+		"ref": "refs/heads/master",
+		"checkout_sha": "def456",
+		"project": {"path_with_namespace": "luizalabs/teresa"},
+		"commits": [
+			{"id": "abc123", "message": "Old commit"},
+			{"id": "def456", "message": "Add feature"}
+		]
+	}`
+
+	p, err := parseGitlabPush([]byte(payload))
+	if err != nil {
+		t.Fatal("error parsing push: ", err)
+	}
+	if p.key() != "luizalabs/teresa@master" {
+		t.Errorf("expected luizalabs/teresa@master, got %s", p.key())
+	}
+	if p.description() != "def456 Add feature" {
+		t.Errorf("expected def456 Add feature, got %s", p.description())
+	}
+}
+
+func TestVerifySignatureGitlabToken(t *testing.T) {
+	var testCases = []struct {
+		token       string
+		expectedErr error
+	}{
+		{"secret", nil},
+		{"other", errInvalidSignature},
+	}
+
+	for _, tc := range testCases {
+		req := newRequest(t, "POST", Path, nil)
+		req.Header.Set(gitlabTokenHeader, tc.token)
+		if err := verifySignature(req, nil, "secret"); err != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestArchiveURL(t *testing.T) {
+	p := &push{Repo: "luizalabs/teresa", Sha: "abc123"}
+	tmpl := "https://gitlab.com/api/v4/projects/{repo_escaped}/repository/archive.tar.gz?sha={sha}"
+	expected := "https://gitlab.com/api/v4/projects/luizalabs%2Fteresa/repository/archive.tar.gz?sha=abc123"
+	if actual := archiveURL(tmpl, p); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
@@ -0,0 +1,159 @@
+package gitpush
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+const (
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	githubSha1Header      = "X-Hub-Signature"
+	gitlabEventHeader     = "X-Gitlab-Event"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+	githubPushEvent       = "push"
+	githubPingEvent       = "ping"
+	gitlabPushEvent       = "Push Hook"
+	branchRefPrefix       = "refs/heads/"
+	nullSha               = "0000000000000000000000000000000000000000"
+)
+
+var (
+	errInvalidSignature = errors.New("invalid signature")
+	errMissingSignature = errors.New("missing signature")
+)
+
+// push is the part of a push event used to deploy, common to GitHub and
+// GitLab.
+type push struct {
+	Repo    string
+	Branch  string
+	Sha     string
+	Message string
+}
+
+// key is how the repo and branch of the push are written in the
+// configuration of the apps, as in luizalabs/teresa@master.
+func (p *push) key() string {
+	return p.Repo + "@" + p.Branch
+}
+
+func (p *push) description() string {
+	msg := strings.TrimSpace(p.Message)
+	if i := strings.Index(msg, "\n"); i != -1 {
+		msg = msg[:i]
+	}
+	return strings.TrimSpace(p.Sha + " " + msg)
+}
+
+type githubPush struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	HeadCommit *struct {
+		Message string `json:"message"`
+	} `json:"head_commit"`
+}
+
+type gitlabPush struct {
+	Ref         string `json:"ref"`
+	CheckoutSha string `json:"checkout_sha"`
+	Project     struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	Commits []struct {
+		Id      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"commits"`
+}
+
+func parseGithubPush(body []byte) (*push, error) {
+	gp := new(githubPush)
+	if err := json.Unmarshal(body, gp); err != nil {
+		return nil, err
+	}
+	p := &push{
+		Repo:   gp.Repository.FullName,
+		Branch: branchName(gp.Ref),
+		Sha:    gp.After,
+	}
+	if gp.HeadCommit != nil {
+		p.Message = gp.HeadCommit.Message
+	}
+	return p, nil
+}
+
+func parseGitlabPush(body []byte) (*push, error) {
+	gp := new(gitlabPush)
+	if err := json.Unmarshal(body, gp); err != nil {
+		return nil, err
+	}
+	p := &push{
+		Repo:   gp.Project.PathWithNamespace,
+		Branch: branchName(gp.Ref),
+		Sha:    gp.CheckoutSha,
+	}
+	for _, c := range gp.Commits {
+		if c.Id == p.Sha {
+			p.Message = c.Message
+		}
+	}
+	return p, nil
+}
+
+// isBranchPush tells if the push created or updated a branch; tags and
+// branch deletions are not deployed.
+func (p *push) isBranchPush() bool {
+	return p.Branch != "" && p.Sha != "" && p.Sha != nullSha
+}
+
+func branchName(ref string) string {
+	if !strings.HasPrefix(ref, branchRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, branchRefPrefix)
+}
+
+// verifySignature checks the GitHub HMAC signature of the body or the
+// GitLab secret token, whichever the request carries.
+func verifySignature(r *http.Request, body []byte, secret string) error {
+	if sig := r.Header.Get(githubSignatureHeader); sig != "" {
+		return verifyHMAC(sha256.New, "sha256=", sig, body, secret)
+	}
+	if sig := r.Header.Get(githubSha1Header); sig != "" {
+		return verifyHMAC(sha1.New, "sha1=", sig, body, secret)
+	}
+	if token := r.Header.Get(gitlabTokenHeader); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return errInvalidSignature
+		}
+		return nil
+	}
+	return errMissingSignature
+}
+
+func verifyHMAC(h func() hash.Hash, prefix, sig string, body []byte, secret string) error {
+	if !strings.HasPrefix(sig, prefix) {
+		return errInvalidSignature
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(sig, prefix))
+	if err != nil {
+		return errInvalidSignature
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(actual, mac.Sum(nil)) {
+		return errInvalidSignature
+	}
+	return nil
+}
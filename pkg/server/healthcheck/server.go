@@ -15,6 +15,7 @@ type K8sOperations interface {
 type Server struct {
 	k8s K8sOperations
 	DB  *gorm.DB
+	mux *http.ServeMux
 }
 
 type healthCheckResponse struct {
@@ -46,14 +47,19 @@ func (s *Server) healthCheck(w http.ResponseWriter, _ *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func (s *Server) Run(l net.Listener) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/healthcheck/", s.healthCheck)
-	server := &http.Server{Handler: mux}
+// Handle registers other handlers to be served by the HTTP listener,
+// next to the health check.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
 
+func (s *Server) Run(l net.Listener) error {
+	server := &http.Server{Handler: s.mux}
 	return server.Serve(l)
 }
 
 func New(k K8sOperations, db *gorm.DB) *Server {
-	return &Server{k8s: k, DB: db}
+	s := &Server{k8s: k, DB: db, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/healthcheck/", s.healthCheck)
+	return s
 }
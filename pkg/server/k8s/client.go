@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/luizalabs/teresa-api/pkg/server/app"
@@ -12,15 +16,20 @@ import (
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
 	"k8s.io/client-go/pkg/api/resource"
+	"k8s.io/client-go/pkg/api/unversioned"
 	k8sv1 "k8s.io/client-go/pkg/api/v1"
 	asv1 "k8s.io/client-go/pkg/apis/autoscaling/v1"
+	k8s_extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 	"k8s.io/client-go/pkg/util/wait"
+	"k8s.io/client-go/pkg/watch"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
+	revisionAnnotation     = "deployment.kubernetes.io/revision"
 	patchDeployEnvVarsTmpl = `{"spec":{"template":{"spec":{"containers":[{"name": "%s", "env":%s}]}}}}`
 )
 
@@ -60,6 +69,24 @@ func (k k8sClient) NamespaceLabel(namespace, label string) (string, error) {
 	return ns.Labels[label], nil
 }
 
+func (k *k8sClient) NamespaceListByLabel(label, value string) ([]string, error) {
+	labelSelector := fmt.Sprintf("%s=%s", label, value)
+	nl, err := k.kc.CoreV1().Namespaces().List(k8sv1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "list namespaces failed")
+	}
+
+	namespaces := make([]string, 0, len(nl.Items))
+	for _, item := range nl.Items {
+		namespaces = append(namespaces, item.Name)
+	}
+	return namespaces, nil
+}
+
+func (k *k8sClient) DeleteNamespace(namespace string) error {
+	return k.kc.CoreV1().Namespaces().Delete(namespace, &k8sv1.DeleteOptions{})
+}
+
 func (k *k8sClient) PodList(namespace string) ([]*app.Pod, error) {
 	podList, err := k.kc.CoreV1().Pods(namespace).List(k8sv1.ListOptions{})
 	if err != nil {
@@ -98,6 +125,88 @@ func (k *k8sClient) PodLogs(namespace string, podName string, lines int64, follo
 	return req.Stream()
 }
 
+type eventsByLastSeen []k8sv1.Event
+
+func (e eventsByLastSeen) Len() int      { return len(e) }
+func (e eventsByLastSeen) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e eventsByLastSeen) Less(i, j int) bool {
+	return e[i].LastTimestamp.Before(e[j].LastTimestamp)
+}
+
+func newAppEvent(ev *k8sv1.Event) *app.Event {
+	obj := ev.InvolvedObject.Name
+	if ev.InvolvedObject.Kind != "" {
+		obj = fmt.Sprintf("%s/%s", strings.ToLower(ev.InvolvedObject.Kind), obj)
+	}
+	return &app.Event{
+		Type:     ev.Type,
+		Reason:   ev.Reason,
+		Object:   obj,
+		Message:  strings.TrimSpace(ev.Message),
+		Count:    ev.Count,
+		LastSeen: ev.LastTimestamp.Time,
+	}
+}
+
+func (k *k8sClient) Events(namespace string, follow bool) (<-chan *app.Event, func(), error) {
+	evList, err := k.kc.CoreV1().Events(namespace).List(k8sv1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "list events failed")
+	}
+	sort.Sort(eventsByLastSeen(evList.Items))
+
+	var w watch.Interface
+	if follow {
+		opts := k8sv1.ListOptions{ResourceVersion: evList.ResourceVersion}
+		w, err = k.kc.CoreV1().Events(namespace).Watch(opts)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "watch events failed")
+		}
+	}
+
+	ch := make(chan *app.Event)
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			if w != nil {
+				w.Stop()
+			}
+		})
+	}
+
+	go func() {
+		defer close(ch)
+		for i := range evList.Items {
+			select {
+			case ch <- newAppEvent(&evList.Items[i]):
+			case <-done:
+				return
+			}
+		}
+		if w == nil {
+			return
+		}
+		for we := range w.ResultChan() {
+			if we.Type != watch.Added && we.Type != watch.Modified {
+				continue
+			}
+			ev, ok := we.Object.(*k8sv1.Event)
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- newAppEvent(ev):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return ch, stop, nil
+}
+
 func newNs(a *app.App, user string) *k8sv1.Namespace {
 	return &k8sv1.Namespace{
 		ObjectMeta: k8sv1.ObjectMeta{
@@ -206,20 +315,6 @@ func (k *k8sClient) CreateQuota(a *app.App) error {
 	return err
 }
 
-func (k *k8sClient) CreateSecret(appName, secretName string, data map[string][]byte) error {
-	s := &k8sv1.Secret{
-		Type: k8sv1.SecretTypeOpaque,
-		ObjectMeta: k8sv1.ObjectMeta{
-			Name:      secretName,
-			Namespace: appName,
-		},
-		Data: data,
-	}
-
-	_, err := k.kc.CoreV1().Secrets(appName).Create(s)
-	return err
-}
-
 func (k *k8sClient) CreateAutoScale(a *app.App) error {
 	hpa := newHPA(a)
 
@@ -235,12 +330,16 @@ func (k *k8sClient) AddressList(namespace string) ([]*app.Address, error) {
 
 	addrs := []*app.Address{}
 	for _, srv := range srvs.Items {
+		ports := make([]*app.Port, 0, len(srv.Spec.Ports))
+		for _, p := range srv.Spec.Ports {
+			ports = append(ports, &app.Port{Name: p.Name, Port: p.Port, Protocol: string(p.Protocol)})
+		}
 		for _, i := range srv.Status.LoadBalancer.Ingress {
 			h := i.Hostname
 			if h == "" {
 				h = i.IP
 			}
-			addrs = append(addrs, &app.Address{Hostname: h})
+			addrs = append(addrs, &app.Address{Hostname: h, Ports: ports})
 		}
 	}
 	return addrs, nil
@@ -332,6 +431,100 @@ func (k *k8sClient) CreateOrUpdateDeploy(deploySpec *deploy.DeploySpec) error {
 	return err
 }
 
+func (k *k8sClient) DeleteSecret(namespace, name string) error {
+	return k.kc.CoreV1().Secrets(namespace).Delete(name, &k8sv1.DeleteOptions{})
+}
+
+func (k *k8sClient) DeployAnnotations(namespace, deployId string) (map[string]string, error) {
+	rsList, err := k.kc.ExtensionsV1beta1().ReplicaSets(namespace).List(k8sv1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list replica sets failed")
+	}
+
+	for _, rs := range rsList.Items {
+		an := rs.Spec.Template.Annotations
+		if an[deploy.DeployIdAnnotation] == deployId {
+			return an, nil
+		}
+	}
+	gr := unversioned.GroupResource{Group: "extensions", Resource: "replicasets"}
+	return nil, k8serrors.NewNotFound(gr, deployId)
+}
+
+type deployRevision struct {
+	number   int64
+	deployId string
+}
+
+type revisionsByNumber []*deployRevision
+
+func (r revisionsByNumber) Len() int           { return len(r) }
+func (r revisionsByNumber) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r revisionsByNumber) Less(i, j int) bool { return r[i].number > r[j].number }
+
+// DeployIds returns the deploy ids of the revisions (replica sets) of the
+// app deployment, newest first. Revisions created before the deploy id
+// annotation fall back to the slug path.
+func (k *k8sClient) DeployIds(namespace string) ([]string, error) {
+	rsList, err := k.kc.ExtensionsV1beta1().ReplicaSets(namespace).List(k8sv1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list replica sets failed")
+	}
+
+	var revs []*deployRevision
+	for _, rs := range rsList.Items {
+		deployId := rs.Spec.Template.Annotations[deploy.DeployIdAnnotation]
+		if deployId == "" {
+			deployId = deploy.DeployIdFromSlugURL(replicaSetSlugURL(&rs))
+		}
+		if deployId == "" {
+			continue
+		}
+		number, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		revs = append(revs, &deployRevision{number: number, deployId: deployId})
+	}
+	sort.Sort(revisionsByNumber(revs))
+
+	ids := make([]string, len(revs))
+	for i, r := range revs {
+		ids[i] = r.deployId
+	}
+	return ids, nil
+}
+
+// replicaSetSlugURL returns the slug of the revision, from the slug
+// annotation or, for revisions created before it, from the SLUG_URL env var.
+func replicaSetSlugURL(rs *k8s_extensions.ReplicaSet) string {
+	if slugURL := rs.Spec.Template.Annotations[deploy.SlugPathAnnotation]; slugURL != "" {
+		return slugURL
+	}
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		for _, e := range c.Env {
+			if e.Name == "SLUG_URL" {
+				return e.Value
+			}
+		}
+	}
+	return ""
+}
+
+// SlugURLs returns the slugs used by the revisions (replica sets) of the
+// deployments of all apps, including the slugs promoted from other apps.
+func (k *k8sClient) SlugURLs() ([]string, error) {
+	rsList, err := k.kc.ExtensionsV1beta1().ReplicaSets(k8sv1.NamespaceAll).List(k8sv1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list replica sets failed")
+	}
+
+	var slugURLs []string
+	for i := range rsList.Items {
+		if slugURL := replicaSetSlugURL(&rsList.Items[i]); slugURL != "" {
+			slugURLs = append(slugURLs, slugURL)
+		}
+	}
+	return slugURLs, nil
+}
+
 func (k *k8sClient) PodRun(podSpec *deploy.PodSpec) (io.ReadCloser, <-chan int, error) {
 	podYaml := podSpecToK8sPod(podSpec)
 	pod, err := k.kc.Pods(podSpec.Namespace).Create(podYaml)
@@ -382,12 +575,24 @@ func (k *k8sClient) HasService(namespace, appName string) (bool, error) {
 	return true, nil
 }
 
-func (k *k8sClient) CreateService(namespace, appName string) error {
-	srvSpec := serviceSpec(namespace, appName, k.defaultServiceType)
+func (k *k8sClient) CreateService(namespace, appName string, ports []*deploy.ServicePort) error {
+	srvSpec := serviceSpec(namespace, appName, k.defaultServiceType, ports)
 	_, err := k.kc.CoreV1().Services(namespace).Create(srvSpec)
 	return errors.Wrap(err, "create service failed")
 }
 
+// UpdateServicePorts replaces the ports of the service, keeping the node
+// ports already allocated.
+func (k *k8sClient) UpdateServicePorts(namespace, appName string, ports []*deploy.ServicePort) error {
+	srv, err := k.kc.CoreV1().Services(namespace).Get(appName)
+	if err != nil {
+		return errors.Wrap(err, "get service failed")
+	}
+	srv.Spec.Ports = servicePortsToK8sServicePorts(ports, srv.Spec.Ports)
+	_, err = k.kc.CoreV1().Services(namespace).Update(srv)
+	return errors.Wrap(err, "update service failed")
+}
+
 func (k *k8sClient) killPod(pod *k8sv1.Pod) error {
 	return k.kc.Pods(pod.Namespace).Delete(pod.Name, &k8sv1.DeleteOptions{})
 }
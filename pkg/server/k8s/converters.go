@@ -2,7 +2,9 @@ package k8s
 
 import (
 	"strconv"
+	"strings"
 
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/deploy"
 	"k8s.io/client-go/pkg/api/unversioned"
 	k8sv1 "k8s.io/client-go/pkg/api/v1"
@@ -17,6 +19,10 @@ func podSpecToK8sContainer(podSpec *deploy.PodSpec) k8sv1.Container {
 		Image:           podSpec.Image,
 	}
 
+	for _, cmd := range podSpec.Command {
+		c.Command = append(c.Command, cmd)
+	}
+
 	for _, arg := range podSpec.Args {
 		c.Args = append(c.Args, arg)
 	}
@@ -69,14 +75,16 @@ func podSpecToK8sPod(podSpec *deploy.PodSpec) *k8sv1.Pod {
 
 func deploySpecToK8sDeploy(deploySpec *deploy.DeploySpec, replicas int32) *k8s_extensions.Deployment {
 	c := podSpecToK8sContainer(&deploySpec.PodSpec)
+	c.Ports = teresaYamlToK8sContainerPorts(&deploySpec.TeresaYaml)
 	volumes := podSpecVolumesToK8sVolumes(deploySpec.Volume)
 
+	appPort := deploySpec.AppPort()
 	if deploySpec.HealthCheck != nil {
 		if deploySpec.HealthCheck.Liveness != nil {
-			c.LivenessProbe = healthCheckProbeToK8sProbe(deploySpec.HealthCheck.Liveness)
+			c.LivenessProbe = healthCheckProbeToK8sProbe(deploySpec.HealthCheck.Liveness, appPort)
 		}
 		if deploySpec.HealthCheck.Readiness != nil {
-			c.ReadinessProbe = healthCheckProbeToK8sProbe(deploySpec.HealthCheck.Readiness)
+			c.ReadinessProbe = healthCheckProbeToK8sProbe(deploySpec.HealthCheck.Readiness, appPort)
 		}
 	}
 
@@ -108,7 +116,7 @@ func deploySpecToK8sDeploy(deploySpec *deploy.DeploySpec, replicas int32) *k8s_e
 			Labels:    map[string]string{"run": deploySpec.Name},
 			Annotations: map[string]string{
 				"kubernetes.io/change-cause": deploySpec.Description,
-				"teresa.io/slug":             deploySpec.SlugURL,
+				"teresa.io/slug":             deploySpec.SlugPath,
 			},
 		},
 		Spec: k8s_extensions.DeploymentSpec{
@@ -122,7 +130,8 @@ func deploySpecToK8sDeploy(deploySpec *deploy.DeploySpec, replicas int32) *k8s_e
 			},
 			Template: k8sv1.PodTemplateSpec{
 				ObjectMeta: k8sv1.ObjectMeta{
-					Labels: map[string]string{"run": deploySpec.Name},
+					Labels:      map[string]string{"run": deploySpec.Name},
+					Annotations: deploySpec.Annotations,
 				},
 				Spec: ps,
 			},
@@ -132,7 +141,7 @@ func deploySpecToK8sDeploy(deploySpec *deploy.DeploySpec, replicas int32) *k8s_e
 	return d
 }
 
-func rollingUpdateToK8sRollingUpdate(ru *deploy.RollingUpdate) (maxSurge, maxUnavailable intstr.IntOrString) {
+func rollingUpdateToK8sRollingUpdate(ru *appconfig.RollingUpdate) (maxSurge, maxUnavailable intstr.IntOrString) {
 	conv := func(value string) intstr.IntOrString {
 		v, err := strconv.Atoi(value)
 		if err != nil {
@@ -143,23 +152,61 @@ func rollingUpdateToK8sRollingUpdate(ru *deploy.RollingUpdate) (maxSurge, maxUna
 	return conv(ru.MaxSurge), conv(ru.MaxUnavailable)
 }
 
-func healthCheckProbeToK8sProbe(probe *deploy.HealthCheckProbe) *k8sv1.Probe {
+func teresaYamlToK8sContainerPorts(tYaml *appconfig.TeresaYaml) []k8sv1.ContainerPort {
+	ports := []k8sv1.ContainerPort{{
+		Name:          appconfig.AppPortName,
+		ContainerPort: tYaml.AppPort(),
+		Protocol:      k8sv1.ProtocolTCP,
+	}}
+	for _, p := range tYaml.Ports {
+		ports = append(ports, k8sv1.ContainerPort{
+			Name:          p.Name,
+			ContainerPort: p.Port,
+			Protocol:      k8sv1.Protocol(p.ProtocolOrDefault()),
+		})
+	}
+	return ports
+}
+
+// healthCheckProbeToK8sProbe converts the probe, the http and tcp probes
+// without a port check appPort.
+func healthCheckProbeToK8sProbe(probe *appconfig.HealthCheckProbe, appPort int32) *k8sv1.Probe {
 	return &k8sv1.Probe{
 		InitialDelaySeconds: probe.InitialDelaySeconds,
 		TimeoutSeconds:      probe.TimeoutSeconds,
 		PeriodSeconds:       probe.PeriodSeconds,
 		FailureThreshold:    probe.FailureThreshold,
 		SuccessThreshold:    probe.SuccessThreshold,
-		Handler: k8sv1.Handler{
-			HTTPGet: &k8sv1.HTTPGetAction{
-				Port: intstr.FromInt(deploy.DefaultPort),
-				Path: probe.Path,
-			},
-		},
+		Handler:             healthCheckProbeToK8sHandler(probe, appPort),
+	}
+}
+
+func healthCheckProbeToK8sHandler(probe *appconfig.HealthCheckProbe, appPort int32) k8sv1.Handler {
+	if probe.Exec != nil {
+		return k8sv1.Handler{Exec: &k8sv1.ExecAction{Command: probe.Exec.Command}}
+	}
+	if probe.TCPSocket != nil {
+		port := probe.TCPSocket.Port
+		if port == 0 {
+			port = appPort
+		}
+		return k8sv1.Handler{TCPSocket: &k8sv1.TCPSocketAction{Port: intstr.FromInt(int(port))}}
+	}
+
+	action := &k8sv1.HTTPGetAction{
+		Port: intstr.FromInt(int(appPort)),
+		Path: probe.Path,
 	}
+	if probe.Scheme != "" {
+		action.Scheme = k8sv1.URIScheme(strings.ToUpper(probe.Scheme))
+	}
+	for _, h := range probe.Headers {
+		action.HTTPHeaders = append(action.HTTPHeaders, k8sv1.HTTPHeader{Name: h.Name, Value: h.Value})
+	}
+	return k8sv1.Handler{HTTPGet: action}
 }
 
-func lifecycleToK8sLifecycle(lc *deploy.Lifecycle) *k8sv1.Lifecycle {
+func lifecycleToK8sLifecycle(lc *appconfig.Lifecycle) *k8sv1.Lifecycle {
 	k8sLc := new(k8sv1.Lifecycle)
 
 	if lc.PreStop != nil {
@@ -173,7 +220,27 @@ func lifecycleToK8sLifecycle(lc *deploy.Lifecycle) *k8sv1.Lifecycle {
 	return k8sLc
 }
 
-func serviceSpec(namespace, name, srvType string) *k8sv1.Service {
+// servicePortsToK8sServicePorts converts the ports, keeping the node ports
+// of the current ports with the same name.
+func servicePortsToK8sServicePorts(ports []*deploy.ServicePort, current []k8sv1.ServicePort) []k8sv1.ServicePort {
+	nodePorts := make(map[string]int32)
+	for _, p := range current {
+		nodePorts[p.Name] = p.NodePort
+	}
+	k8sPorts := make([]k8sv1.ServicePort, 0, len(ports))
+	for _, p := range ports {
+		k8sPorts = append(k8sPorts, k8sv1.ServicePort{
+			Name:       p.Name,
+			Port:       p.Port,
+			Protocol:   k8sv1.Protocol(p.Protocol),
+			TargetPort: intstr.FromInt(int(p.TargetPort)),
+			NodePort:   nodePorts[p.Name],
+		})
+	}
+	return k8sPorts
+}
+
+func serviceSpec(namespace, name, srvType string, ports []*deploy.ServicePort) *k8sv1.Service {
 	serviceType := k8sv1.ServiceType(srvType)
 	return &k8sv1.Service{
 		TypeMeta: unversioned.TypeMeta{
@@ -193,13 +260,7 @@ func serviceSpec(namespace, name, srvType string) *k8sv1.Service {
 			Selector: map[string]string{
 				"run": name,
 			},
-			Ports: []k8sv1.ServicePort{
-				k8sv1.ServicePort{
-					Port:       80,
-					Protocol:   k8sv1.ProtocolTCP,
-					TargetPort: intstr.FromInt(deploy.DefaultPort),
-				},
-			},
+			Ports: servicePortsToK8sServicePorts(ports, nil),
 		},
 	}
 }
@@ -3,17 +3,20 @@ package k8s
 import (
 	"testing"
 
+	k8sv1 "k8s.io/client-go/pkg/api/v1"
 	"k8s.io/client-go/pkg/util/intstr"
 
+	"github.com/luizalabs/teresa-api/pkg/appconfig"
 	"github.com/luizalabs/teresa-api/pkg/server/deploy"
 )
 
 func TestPodSpecToK8sContainer(t *testing.T) {
 	ps := &deploy.PodSpec{
-		Name:  "Teresa",
-		Image: "luizalabs/teresa:0.0.1",
-		Env:   map[string]string{"ENV-KEY": "ENV-VALUE"},
-		Args:  []string{"start", "release"},
+		Name:    "Teresa",
+		Image:   "luizalabs/teresa:0.0.1",
+		Env:     map[string]string{"ENV-KEY": "ENV-VALUE"},
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{"start", "release"},
 		VolumeMounts: []*deploy.PodVolumeMountsSpec{
 			&deploy.PodVolumeMountsSpec{Name: "Vol1", MountPath: "/tmp", ReadOnly: true},
 		},
@@ -45,6 +48,12 @@ func TestPodSpecToK8sContainer(t *testing.T) {
 		}
 	}
 
+	for idx, cmd := range ps.Command {
+		if c.Command[idx] != cmd {
+			t.Errorf("expected %s, got %s", cmd, c.Command[idx])
+		}
+	}
+
 	for idx, arg := range ps.Args {
 		if c.Args[idx] != arg {
 			t.Errorf("expected %s, got %s", arg, c.Args[idx])
@@ -87,7 +96,7 @@ func TestPodSpecToK8sPod(t *testing.T) {
 }
 
 func TestRollingUpdateToK8sRollingUpdate(t *testing.T) {
-	ru := &deploy.RollingUpdate{MaxSurge: "3", MaxUnavailable: "30%"}
+	ru := &appconfig.RollingUpdate{MaxSurge: "3", MaxUnavailable: "30%"}
 	maxSurge, maxUnavailable := rollingUpdateToK8sRollingUpdate(ru)
 
 	if maxSurge != intstr.FromInt(3) {
@@ -100,7 +109,7 @@ func TestRollingUpdateToK8sRollingUpdate(t *testing.T) {
 }
 
 func TestHealthCheckProbeToK8sProbe(t *testing.T) {
-	hc := &deploy.HealthCheckProbe{
+	hc := &appconfig.HealthCheckProbe{
 		FailureThreshold:    2,
 		InitialDelaySeconds: 5,
 		PeriodSeconds:       5,
@@ -108,7 +117,7 @@ func TestHealthCheckProbeToK8sProbe(t *testing.T) {
 		TimeoutSeconds:      3,
 		Path:                "/hc/",
 	}
-	k8sHC := healthCheckProbeToK8sProbe(hc)
+	k8sHC := healthCheckProbeToK8sProbe(hc, deploy.DefaultPort)
 
 	if k8sHC.InitialDelaySeconds != hc.InitialDelaySeconds {
 		t.Errorf("expected %d, got %d", hc.InitialDelaySeconds, k8sHC.InitialDelaySeconds)
@@ -130,6 +139,34 @@ func TestHealthCheckProbeToK8sProbe(t *testing.T) {
 	}
 }
 
+func TestHealthCheckProbeToK8sProbeHandlers(t *testing.T) {
+	tcp := healthCheckProbeToK8sProbe(&appconfig.HealthCheckProbe{TCPSocket: &appconfig.TCPSocketProbe{}}, 8080)
+	if tcp.Handler.TCPSocket == nil || tcp.Handler.TCPSocket.Port != intstr.FromInt(8080) {
+		t.Errorf("expected tcp socket probe on port 8080, got %+v", tcp.Handler)
+	}
+
+	cmd := []string{"cat", "/tmp/healthy"}
+	exec := healthCheckProbeToK8sProbe(&appconfig.HealthCheckProbe{Exec: &appconfig.ExecProbe{Command: cmd}}, 8080)
+	if exec.Handler.Exec == nil || len(exec.Handler.Exec.Command) != 2 || exec.Handler.HTTPGet != nil {
+		t.Errorf("expected exec probe %v, got %+v", cmd, exec.Handler)
+	}
+
+	http := healthCheckProbeToK8sProbe(&appconfig.HealthCheckProbe{
+		Path:    "/hc/",
+		Scheme:  "https",
+		Headers: []*appconfig.HTTPHeader{{Name: "Host", Value: "teresa.io"}},
+	}, 8080)
+	if http.Handler.HTTPGet.Scheme != k8sv1.URISchemeHTTPS {
+		t.Errorf("expected %s, got %s", k8sv1.URISchemeHTTPS, http.Handler.HTTPGet.Scheme)
+	}
+	if http.Handler.HTTPGet.Port != intstr.FromInt(8080) {
+		t.Errorf("expected port 8080, got %v", http.Handler.HTTPGet.Port)
+	}
+	if h := http.Handler.HTTPGet.HTTPHeaders; len(h) != 1 || h[0].Name != "Host" || h[0].Value != "teresa.io" {
+		t.Errorf("expected Host header, got %v", h)
+	}
+}
+
 func TestDeploySpecToK8sDeploy(t *testing.T) {
 	ds := &deploy.DeploySpec{
 		PodSpec: deploy.PodSpec{
@@ -137,12 +174,12 @@ func TestDeploySpecToK8sDeploy(t *testing.T) {
 			Image: "luizalabs/teresa:0.0.1",
 			Args:  []string{"run", "web"},
 		},
-		TeresaYaml: deploy.TeresaYaml{
-			HealthCheck: &deploy.HealthCheck{
-				Liveness:  &deploy.HealthCheckProbe{PeriodSeconds: 2},
-				Readiness: &deploy.HealthCheckProbe{PeriodSeconds: 5},
+		TeresaYaml: appconfig.TeresaYaml{
+			HealthCheck: &appconfig.HealthCheck{
+				Liveness:  &appconfig.HealthCheckProbe{PeriodSeconds: 2},
+				Readiness: &appconfig.HealthCheckProbe{PeriodSeconds: 5},
 			},
-			RollingUpdate: &deploy.RollingUpdate{MaxSurge: "3", MaxUnavailable: "30%"},
+			RollingUpdate: &appconfig.RollingUpdate{MaxSurge: "3", MaxUnavailable: "30%"},
 		},
 	}
 
@@ -179,3 +216,47 @@ func TestDeploySpecToK8sDeploy(t *testing.T) {
 		t.Errorf("expected 3, got %v", k8sRollingUpdate.MaxSurge)
 	}
 }
+
+func TestDeploySpecToK8sDeployPorts(t *testing.T) {
+	ds := &deploy.DeploySpec{
+		PodSpec: deploy.PodSpec{Name: "teresa"},
+		TeresaYaml: appconfig.TeresaYaml{
+			Port:        8080,
+			Ports:       []*appconfig.Port{{Name: "metrics", Port: 9100, Protocol: "udp"}},
+			HealthCheck: &appconfig.HealthCheck{Liveness: &appconfig.HealthCheckProbe{Path: "/hc/"}},
+		},
+	}
+
+	c := deploySpecToK8sDeploy(ds, 1).Spec.Template.Spec.Containers[0]
+	if len(c.Ports) != 2 {
+		t.Fatalf("expected 2 ports, got %d", len(c.Ports))
+	}
+	if c.Ports[0].Name != appconfig.AppPortName || c.Ports[0].ContainerPort != 8080 {
+		t.Errorf("expected http port 8080, got %+v", c.Ports[0])
+	}
+	if c.Ports[1].Name != "metrics" || c.Ports[1].ContainerPort != 9100 || c.Ports[1].Protocol != k8sv1.ProtocolUDP {
+		t.Errorf("expected metrics port 9100/UDP, got %+v", c.Ports[1])
+	}
+	if c.LivenessProbe.HTTPGet.Port != intstr.FromInt(8080) {
+		t.Errorf("expected probe on port 8080, got %v", c.LivenessProbe.HTTPGet.Port)
+	}
+}
+
+func TestServicePortsToK8sServicePorts(t *testing.T) {
+	ports := []*deploy.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "TCP"},
+		{Name: "grpc", Port: 50051, TargetPort: 50051, Protocol: "TCP"},
+	}
+	current := []k8sv1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}}
+
+	k8sPorts := servicePortsToK8sServicePorts(ports, current)
+	if len(k8sPorts) != 2 {
+		t.Fatalf("expected 2 ports, got %d", len(k8sPorts))
+	}
+	if k8sPorts[0].NodePort != 30080 || k8sPorts[0].TargetPort != intstr.FromInt(8080) {
+		t.Errorf("expected node port 30080 and target port 8080, got %+v", k8sPorts[0])
+	}
+	if k8sPorts[1].NodePort != 0 || k8sPorts[1].Port != 50051 {
+		t.Errorf("expected port 50051 without node port, got %+v", k8sPorts[1])
+	}
+}
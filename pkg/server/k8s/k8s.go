@@ -4,6 +4,7 @@ import (
 	"github.com/luizalabs/teresa-api/pkg/server/app"
 	"github.com/luizalabs/teresa-api/pkg/server/deploy"
 	"github.com/luizalabs/teresa-api/pkg/server/healthcheck"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
 	"k8s.io/client-go/pkg/api"
 )
 
@@ -22,6 +23,7 @@ type Client interface {
 	app.K8sOperations
 	deploy.K8sOperations
 	healthcheck.K8sOperations
+	team.K8sOperations
 }
 
 func validateConfig(conf *Config) error {
@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/luizalabs/teresa-api/pkg/server/app"
+	"github.com/pkg/errors"
+
+	"k8s.io/client-go/pkg/api/resource"
+)
+
+const podMetricsPathTmpl = "/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods"
+
+type containerMetrics struct {
+	Name  string            `json:"name"`
+	Usage map[string]string `json:"usage"`
+}
+
+type podMetrics struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Containers []containerMetrics `json:"containers"`
+}
+
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+func parsePodMetrics(data []byte) ([]*app.PodMetrics, error) {
+	pml := new(podMetricsList)
+	if err := json.Unmarshal(data, pml); err != nil {
+		return nil, err
+	}
+
+	pods := make([]*app.PodMetrics, 0)
+	for _, item := range pml.Items {
+		pm := &app.PodMetrics{Name: item.Metadata.Name}
+		for _, c := range item.Containers {
+			if v, ok := c.Usage["cpu"]; ok {
+				q, err := resource.ParseQuantity(v)
+				if err != nil {
+					return nil, err
+				}
+				pm.CPU += q.MilliValue()
+			}
+			if v, ok := c.Usage["memory"]; ok {
+				q, err := resource.ParseQuantity(v)
+				if err != nil {
+					return nil, err
+				}
+				pm.Memory += q.Value()
+			}
+		}
+		pods = append(pods, pm)
+	}
+	return pods, nil
+}
+
+func (k *k8sClient) PodMetrics(namespace string) ([]*app.PodMetrics, error) {
+	data, err := k.kc.CoreV1().RESTClient().
+		Get().
+		AbsPath(fmt.Sprintf(podMetricsPathTmpl, namespace)).
+		DoRaw()
+	if err != nil {
+		return nil, errors.Wrap(err, "get pod metrics failed")
+	}
+
+	pods, err := parsePodMetrics(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse pod metrics failed")
+	}
+	return pods, nil
+}
@@ -0,0 +1,64 @@
+package k8s
+
+import "testing"
+
+const fakePodMetrics = `{
+  "kind": "PodMetricsList",
+  "apiVersion": "metrics.k8s.io/v1beta1",
+  "items": [
+    {
+      "metadata": {"name": "teresa-1", "namespace": "teresa"},
+      "window": "30s",
+      "containers": [
+        {"name": "teresa", "usage": {"cpu": "150m", "memory": "128Mi"}},
+        {"name": "sidecar", "usage": {"cpu": "50m", "memory": "64Mi"}}
+      ]
+    },
+    {
+      "metadata": {"name": "teresa-2", "namespace": "teresa"},
+      "window": "30s",
+      "containers": [
+        {"name": "teresa", "usage": {"cpu": "1", "memory": "1Gi"}}
+      ]
+    }
+  ]
+}`
+
+func TestParsePodMetrics(t *testing.T) {
+	pods, err := parsePodMetrics([]byte(fakePodMetrics))
+	if err != nil {
+		t.Fatal("error parsing pod metrics:", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+
+	var testCases = []struct {
+		name   string
+		cpu    int64
+		memory int64
+	}{
+		{"teresa-1", 200, 192 * 1024 * 1024},
+		{"teresa-2", 1000, 1024 * 1024 * 1024},
+	}
+
+	for i, tc := range testCases {
+		pm := pods[i]
+		if pm.Name != tc.name {
+			t.Errorf("expected %s, got %s", tc.name, pm.Name)
+		}
+		if pm.CPU != tc.cpu {
+			t.Errorf("expected %d, got %d", tc.cpu, pm.CPU)
+		}
+		if pm.Memory != tc.memory {
+			t.Errorf("expected %d, got %d", tc.memory, pm.Memory)
+		}
+	}
+}
+
+func TestParsePodMetricsInvalidQuantity(t *testing.T) {
+	data := `{"items": [{"metadata": {"name": "p"}, "containers": [{"usage": {"cpu": "x"}}]}]}`
+	if _, err := parsePodMetrics([]byte(data)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,6 @@
+// Package labels has the labels teresa sets on the Kubernetes resources of
+// the apps, for the packages that can't import the app package.
+package labels
+
+// Team is the namespace label holding the team of an app.
+const Team = "teresa.io/team"
@@ -0,0 +1,218 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	goldap "gopkg.in/ldap.v2"
+
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+// Options configure the LDAP login, read from the TERESA_LDAP_* env vars;
+// it is disabled without an URL.
+type Options struct {
+	// URL of the directory, ldap://host[:port] or ldaps://host[:port]
+	URL      string
+	StartTLS bool `envconfig:"start_tls"`
+	// Insecure skips the verification of the server certificate
+	Insecure bool
+	Timeout  time.Duration `default:"10s"`
+	// BindDN and BindPassword are the service account used to search the
+	// users and groups, the search is anonymous without them
+	BindDN       string `envconfig:"bind_dn"`
+	BindPassword string `split_words:"true"`
+	BaseDN       string `envconfig:"base_dn"`
+	// UserFilter finds the entry of the user by the e-mail
+	UserFilter string `split_words:"true" default:"(mail=%s)"`
+	// GroupBaseDN defaults to BaseDN
+	GroupBaseDN string `envconfig:"group_base_dn"`
+	// GroupFilter finds the groups of the user by its DN
+	GroupFilter        string `split_words:"true" default:"(member=%s)"`
+	GroupNameAttribute string `split_words:"true" default:"cn"`
+	// GroupTeams maps groups to teams, as "group:team,other:team"; only the
+	// mapped groups are synced
+	GroupTeams map[string]string `split_words:"true"`
+}
+
+// conn is the part of the LDAP connection used by the Authenticator.
+type conn interface {
+	Bind(username, password string) error
+	Search(*goldap.SearchRequest) (*goldap.SearchResult, error)
+	Close()
+}
+
+// Authenticator checks the password of the users binding to the directory
+// with their DN and maps their groups to teams.
+type Authenticator struct {
+	opts *Options
+	url  *url.URL
+	dial func() (conn, error)
+}
+
+func (a *Authenticator) Authenticate(email, password string) (*user.Identity, error) {
+	// an empty password is an unauthenticated bind, which always succeeds
+	if password == "" {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	c, err := a.dial()
+	if err != nil {
+		return nil, internalError(err, "connecting to %s", a.url.Host)
+	}
+	defer c.Close()
+
+	if err := a.bind(c); err != nil {
+		return nil, internalError(err, "binding as %s", a.opts.BindDN)
+	}
+	filter := fmt.Sprintf(a.opts.UserFilter, goldap.EscapeFilter(email))
+	entries, err := a.search(c, a.opts.BaseDN, filter, []string{"dn"})
+	if err != nil {
+		return nil, internalError(err, "searching user %s", email)
+	}
+	if len(entries) != 1 {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			errors.Errorf("found %d LDAP entries for user %s", len(entries), email),
+		)
+	}
+
+	dn := entries[0].DN
+	if err := c.Bind(dn, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, teresa_errors.New(
+				auth.ErrPermissionDenied,
+				errors.Wrap(err, fmt.Sprintf("Authentication failed for user %s", email)),
+			)
+		}
+		return nil, internalError(err, "binding as %s", dn)
+	}
+
+	teams, err := a.teams(c, dn)
+	if err != nil {
+		return nil, err
+	}
+	return &user.Identity{Email: email, Teams: teams, ManagedTeams: a.managedTeams()}, nil
+}
+
+// managedTeams returns the teams mapped from groups, synced on each login.
+func (a *Authenticator) managedTeams() []string {
+	teams := make([]string, 0, len(a.opts.GroupTeams))
+	for _, team := range a.opts.GroupTeams {
+		teams = append(teams, team)
+	}
+	return teams
+}
+
+// teams returns the teams mapped from the groups of the user.
+func (a *Authenticator) teams(c conn, dn string) ([]string, error) {
+	if len(a.opts.GroupTeams) == 0 {
+		return nil, nil
+	}
+	if err := a.bind(c); err != nil {
+		return nil, internalError(err, "binding as %s", a.opts.BindDN)
+	}
+	base := a.opts.GroupBaseDN
+	if base == "" {
+		base = a.opts.BaseDN
+	}
+	filter := fmt.Sprintf(a.opts.GroupFilter, goldap.EscapeFilter(dn))
+	entries, err := a.search(c, base, filter, []string{a.opts.GroupNameAttribute})
+	if err != nil {
+		return nil, internalError(err, "searching groups of %s", dn)
+	}
+
+	var teams []string
+	for _, e := range entries {
+		if team, ok := a.opts.GroupTeams[e.GetAttributeValue(a.opts.GroupNameAttribute)]; ok {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+func (a *Authenticator) bind(c conn) error {
+	if a.opts.BindDN == "" {
+		return nil
+	}
+	return c.Bind(a.opts.BindDN, a.opts.BindPassword)
+}
+
+func (a *Authenticator) search(c conn, base, filter string, attrs []string) ([]*goldap.Entry, error) {
+	req := goldap.NewSearchRequest(
+		base, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, int(a.opts.Timeout/time.Second), false, filter, attrs, nil,
+	)
+	res, err := c.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Entries, nil
+}
+
+func (a *Authenticator) dialURL() (conn, error) {
+	isTLS := a.url.Scheme == "ldaps"
+	host, port, err := net.SplitHostPort(a.url.Host)
+	if err != nil {
+		host = strings.Trim(a.url.Host, "[]")
+		port = "389"
+		if isTLS {
+			port = "636"
+		}
+	}
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: a.opts.Insecure}
+
+	nc, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), a.opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if isTLS {
+		tc := tls.Client(nc, tlsConfig)
+		nc.SetDeadline(time.Now().Add(a.opts.Timeout))
+		if err := tc.Handshake(); err != nil {
+			nc.Close()
+			return nil, err
+		}
+		nc.SetDeadline(time.Time{})
+		nc = tc
+	}
+
+	c := goldap.NewConn(nc, isTLS)
+	c.Start()
+	c.SetTimeout(a.opts.Timeout)
+	if a.opts.StartTLS && !isTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func internalError(err error, format string, args ...interface{}) error {
+	return teresa_errors.New(
+		teresa_errors.ErrInternalServerError,
+		errors.Wrap(err, fmt.Sprintf("LDAP: "+format, args...)),
+	)
+}
+
+// New returns the Authenticator of the directory of the options.
+func New(opts *Options) (*Authenticator, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid LDAP URL")
+	}
+	if (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return nil, errors.Errorf("invalid LDAP URL %s, expected ldap://host or ldaps://host", opts.URL)
+	}
+	a := &Authenticator{opts: opts, url: u}
+	a.dial = a.dialURL
+	return a, nil
+}
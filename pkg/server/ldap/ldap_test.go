@@ -0,0 +1,151 @@
+package ldap
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	goldap "gopkg.in/ldap.v2"
+
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+const (
+	bindDN   = "cn=teresa,dc=luizalabs,dc=com"
+	userDN   = "uid=gopher,ou=people,dc=luizalabs,dc=com"
+	email    = "gopher@luizalabs.com"
+	password = "secret"
+)
+
+type fakeConn struct {
+	passwords map[string]string
+	users     map[string]string
+	groups    map[string][]string
+	bound     string
+	closed    bool
+	searchErr error
+}
+
+func (c *fakeConn) Bind(dn, password string) error {
+	if p, ok := c.passwords[dn]; !ok || p != password {
+		return goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	c.bound = dn
+	return nil
+}
+
+func (c *fakeConn) Search(req *goldap.SearchRequest) (*goldap.SearchResult, error) {
+	if c.searchErr != nil {
+		return nil, c.searchErr
+	}
+	if c.bound != bindDN {
+		return nil, errors.New("search as " + c.bound)
+	}
+	res := new(goldap.SearchResult)
+	if strings.HasPrefix(req.Filter, "(mail=") {
+		if dn, ok := c.users[req.Filter]; ok {
+			res.Entries = append(res.Entries, goldap.NewEntry(dn, nil))
+		}
+		return res, nil
+	}
+	for _, g := range c.groups[req.Filter] {
+		res.Entries = append(res.Entries, goldap.NewEntry(
+			"cn="+g+",ou=groups,dc=luizalabs,dc=com",
+			map[string][]string{"cn": {g}},
+		))
+	}
+	return res, nil
+}
+
+func (c *fakeConn) Close() {
+	c.closed = true
+}
+
+func newTestAuthenticator(t *testing.T) (*Authenticator, *fakeConn) {
+	a, err := New(&Options{
+		URL:                "ldap://ldap.luizalabs.com",
+		BindDN:             bindDN,
+		BindPassword:       "teresa",
+		BaseDN:             "dc=luizalabs,dc=com",
+		UserFilter:         "(mail=%s)",
+		GroupFilter:        "(member=%s)",
+		GroupNameAttribute: "cn",
+		GroupTeams:         map[string]string{"devs": "luizalabs", "ops": "infra"},
+	})
+	if err != nil {
+		t.Fatal("error creating authenticator: ", err)
+	}
+	c := &fakeConn{
+		passwords: map[string]string{bindDN: "teresa", userDN: password},
+		users:     map[string]string{"(mail=" + email + ")": userDN},
+		groups: map[string][]string{
+			"(member=uid=gopher,ou=people,dc=luizalabs,dc=com)": {"devs", "staff"},
+		},
+	}
+	a.dial = func() (conn, error) { return c, nil }
+	return a, c
+}
+
+func TestAuthenticatorAuthenticate(t *testing.T) {
+	a, c := newTestAuthenticator(t)
+
+	id, err := a.Authenticate(email, password)
+	if err != nil {
+		t.Fatal("error authenticating: ", err)
+	}
+	if id.Email != email {
+		t.Errorf("expected %s, got %s", email, id.Email)
+	}
+	if expected := []string{"luizalabs"}; !reflect.DeepEqual(id.Teams, expected) {
+		t.Errorf("expected %v, got %v", expected, id.Teams)
+	}
+	sort.Strings(id.ManagedTeams)
+	if expected := []string{"infra", "luizalabs"}; !reflect.DeepEqual(id.ManagedTeams, expected) {
+		t.Errorf("expected managed teams %v, got %v", expected, id.ManagedTeams)
+	}
+	if !c.closed {
+		t.Error("expected the connection to be closed")
+	}
+}
+
+func TestAuthenticatorAuthenticateErrors(t *testing.T) {
+	var testCases = []struct {
+		email, password string
+		searchErr       error
+		expectedErr     error
+	}{
+		{email, "wrong", nil, auth.ErrPermissionDenied},
+		{email, "", nil, auth.ErrPermissionDenied},
+		{"unknown@luizalabs.com", password, nil, auth.ErrPermissionDenied},
+		{"*", password, nil, auth.ErrPermissionDenied},
+		{email, password, errors.New("timeout"), teresa_errors.ErrInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		a, c := newTestAuthenticator(t)
+		c.searchErr = tc.searchErr
+		if _, err := a.Authenticate(tc.email, tc.password); teresa_errors.Get(err) != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.email, err)
+		}
+	}
+}
+
+func TestAuthenticatorDialError(t *testing.T) {
+	a, _ := newTestAuthenticator(t)
+	a.dial = func() (conn, error) { return nil, errors.New("connection refused") }
+
+	if _, err := a.Authenticate(email, password); teresa_errors.Get(err) != teresa_errors.ErrInternalServerError {
+		t.Errorf("expected internal server error, got %v", err)
+	}
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	for _, u := range []string{"ldap.luizalabs.com", "http://ldap.luizalabs.com", "ldaps://"} {
+		if _, err := New(&Options{URL: u}); err == nil {
+			t.Errorf("expected error for %s, got nil", u)
+		}
+	}
+}
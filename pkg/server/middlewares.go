@@ -7,8 +7,10 @@ import (
 	context "golang.org/x/net/context"
 
 	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/token"
 	"github.com/luizalabs/teresa-api/pkg/server/user"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -16,6 +18,28 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// recvRecorderStream keeps the request of a streaming rpc recorded in the
+// audit log: the last message received by the handler naming an app or a
+// team (like the info of a deploy), or else the first one.
+type recvRecorderStream struct {
+	grpc.ServerStream
+	fullMethod string
+	req        interface{}
+}
+
+func (s *recvRecorderStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && (s.req == nil || hasTarget(s.fullMethod, m)) {
+		s.req = m
+	}
+	return err
+}
+
+func hasTarget(fullMethod string, req interface{}) bool {
+	app, team := audit.Target(fullMethod, req)
+	return app != "" || team != ""
+}
+
 type serverStreamWrapper struct {
 	grpc.ServerStream
 	ctx context.Context
@@ -25,53 +49,117 @@ func (w *serverStreamWrapper) Context() context.Context {
 	return w.ctx
 }
 
-func loginStreamInterceptor(a auth.Auth, uOps user.Operations) grpc.StreamServerInterceptor {
+// scopedServerStream checks the requests of a streaming rpc called with an
+// API token against the token scope: the first message received and every
+// other one naming an app or a team.
+type scopedServerStream struct {
+	grpc.ServerStream
+	fullMethod string
+	check      func(req interface{}) error
+	checked    bool
+}
+
+func (s *scopedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.checked && !hasTarget(s.fullMethod, m) {
+		return nil
+	}
+	s.checked = true
+	return s.check(m)
+}
+
+func loginStreamInterceptor(a auth.Auth, uOps user.Operations, tOps token.Operations) grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if strings.HasSuffix(info.FullMethod, "Login") {
+		if isPublicMethod(info.FullMethod) {
 			return handler(srv, stream)
 		}
 
 		ctx := stream.Context()
-		user, err := authorize(ctx, a, uOps)
+		user, scope, err := authorize(ctx, a, uOps, tOps)
 		if err != nil {
 			return err
 		}
+		if err := checkTOTPRequired(uOps, user, info.FullMethod); err != nil {
+			return err
+		}
 
 		ctx = context.WithValue(ctx, "user", user)
-		wrap := &serverStreamWrapper{stream, ctx}
+		var wrap grpc.ServerStream = &serverStreamWrapper{stream, ctx}
+		if scope != nil {
+			check := func(req interface{}) error {
+				return tOps.Check(scope, info.FullMethod, req)
+			}
+			wrap = &scopedServerStream{ServerStream: wrap, fullMethod: info.FullMethod, check: check}
+		}
 		return handler(srv, wrap)
 	}
 }
 
-func loginUnaryInterceptor(a auth.Auth, uOps user.Operations) grpc.UnaryServerInterceptor {
+func loginUnaryInterceptor(a auth.Auth, uOps user.Operations, tOps token.Operations) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if strings.HasSuffix(info.FullMethod, "Login") {
+		if isPublicMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		user, err := authorize(ctx, a, uOps)
+		user, scope, err := authorize(ctx, a, uOps, tOps)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkTOTPRequired(uOps, user, info.FullMethod); err != nil {
+			return nil, err
+		}
+		if scope != nil {
+			if err := tOps.Check(scope, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+		}
 
 		ctx = context.WithValue(ctx, "user", user)
 		return handler(ctx, req)
 	}
 }
 
-func authorize(ctx context.Context, a auth.Auth, uOps user.Operations) (*storage.User, error) {
+// authorize returns the user of the token of the call, a login JWT or an
+// API token; the scope is only returned for API tokens.
+func authorize(ctx context.Context, a auth.Auth, uOps user.Operations, tOps token.Operations) (*storage.User, *token.Scope, error) {
 	md, ok := metadata.FromContext(ctx)
 	if !ok {
-		return nil, auth.ErrPermissionDenied
+		return nil, nil, auth.ErrPermissionDenied
 	}
 	if len(md["token"]) < 1 || md["token"][0] == "" {
-		return nil, auth.ErrPermissionDenied
+		return nil, nil, auth.ErrPermissionDenied
 	}
-	email, err := a.ValidateToken(md["token"][0])
+	if token.IsAPIToken(md["token"][0]) {
+		return tOps.Authenticate(md["token"][0])
+	}
+	claims, err := a.ValidateToken(md["token"][0])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	u, err := uOps.Authenticate(claims)
+	return u, nil, err
+}
+
+// checkTOTPRequired denies the calls of the users who must enable the
+// two-factor authentication, except the ones enabling it.
+func checkTOTPRequired(uOps user.Operations, u *storage.User, fullMethod string) error {
+	if !uOps.TOTPRequired(u) {
+		return nil
+	}
+	switch fullMethod {
+	case "/user.User/EnrollTOTP", "/user.User/EnableTOTP", "/user.User/Logout":
+		return nil
 	}
-	return uOps.GetUser(email)
+	return user.ErrTOTPRequired
+}
+
+// isPublicMethod reports whether the rpc is called without a token.
+func isPublicMethod(fullMethod string) bool {
+	return strings.HasSuffix(fullMethod, "Login") ||
+		fullMethod == "/user.User/Refresh" ||
+		fullMethod == "/oidc.OIDC/Config"
 }
 
 func recFunc(p interface{}) (err error) {
@@ -106,3 +194,33 @@ func logStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.
 	}
 	return nil
 }
+
+func auditUnaryInterceptor(aOps audit.Operations) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if audit.IsAudited(info.FullMethod) || (err != nil && audit.IsAuditedFailure(info.FullMethod)) {
+			recordAudit(ctx, aOps, info.FullMethod, req, err)
+		}
+		return resp, err
+	}
+}
+
+func auditStreamInterceptor(aOps audit.Operations) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !audit.IsAudited(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		wrap := &recvRecorderStream{ServerStream: stream, fullMethod: info.FullMethod}
+		err := handler(srv, wrap)
+		recordAudit(stream.Context(), aOps, info.FullMethod, wrap.req, err)
+		return err
+	}
+}
+
+func recordAudit(ctx context.Context, aOps audit.Operations, fullMethod string, req interface{}, err error) {
+	u, _ := ctx.Value("user").(*storage.User)
+	entry := audit.NewEntry(u, fullMethod, req, err)
+	if err := aOps.Record(entry); err != nil {
+		log.WithError(err).WithField("route", fullMethod).Error("Recording audit entry")
+	}
+}
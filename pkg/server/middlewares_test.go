@@ -5,7 +5,9 @@ import (
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -15,24 +17,29 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/luizalabs/teresa-api/models/storage"
+	apppb "github.com/luizalabs/teresa-api/pkg/protobuf/app"
+	deploypb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
+	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/token"
 	"github.com/luizalabs/teresa-api/pkg/server/user"
 )
 
 var (
 	privateKey, _ = rsa.GenerateKey(rand.Reader, 1024)
 	publicKey     = &privateKey.PublicKey
-	authenticator = auth.New(privateKey, publicKey)
+	authenticator = auth.New(privateKey, publicKey, &auth.Options{TokenExpiration: time.Hour})
 )
 
 func TestAuthorize(t *testing.T) {
 	validEmail := "gopher@luizalabs.com"
-	validToken, err := authenticator.GenerateToken(validEmail)
+	validToken, err := authenticator.GenerateToken(validEmail, 0)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
-	tokenForInvalidUser, err := authenticator.GenerateToken("invalid@luizalabs.com")
+	tokenForInvalidUser, err := authenticator.GenerateToken("invalid@luizalabs.com", 0)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
@@ -79,7 +86,7 @@ func TestAuthorize(t *testing.T) {
 	for _, tc := range testCases {
 		md := metadata.Pairs("token", tc.token)
 		ctx := metadata.NewIncomingContext(context.Background(), md)
-		u, err := authorize(ctx, authenticator, uOps)
+		u, _, err := authorize(ctx, authenticator, uOps, token.NewFakeOperations())
 		tc.testResultFunc(u, err)
 	}
 }
@@ -89,7 +96,7 @@ func TestLoginUnaryInterceptorIgnoreLoginRoute(t *testing.T) {
 		return nil, nil
 	}
 	info := &grpc.UnaryServerInfo{FullMethod: "Login"}
-	if _, err := loginUnaryInterceptor(nil, nil)(context.Background(), nil, info, handler); err != nil {
+	if _, err := loginUnaryInterceptor(nil, nil, nil)(context.Background(), nil, info, handler); err != nil {
 		t.Error("error on process unaryInterceptor: ", err)
 	}
 }
@@ -114,14 +121,14 @@ func TestLoginUnaryInterceptor(t *testing.T) {
 		Email:    expectedUserEmail,
 	}
 
-	validToken, err := authenticator.GenerateToken(expectedUserEmail)
+	validToken, err := authenticator.GenerateToken(expectedUserEmail, 0)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
 	md := metadata.Pairs("token", validToken)
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	if ok, err := loginUnaryInterceptor(authenticator, uOps)(ctx, nil, info, handler); err != nil || !ok.(bool) {
+	if ok, err := loginUnaryInterceptor(authenticator, uOps, token.NewFakeOperations())(ctx, nil, info, handler); err != nil || !ok.(bool) {
 		t.Errorf("expected successful execution, got error %v", err)
 	}
 }
@@ -131,7 +138,7 @@ func TestLoginStreamInterceptorIgnoreLoginRoute(t *testing.T) {
 		return nil
 	}
 	info := &grpc.StreamServerInfo{FullMethod: "Login"}
-	if err := loginStreamInterceptor(nil, nil)(nil, nil, info, handler); err != nil {
+	if err := loginStreamInterceptor(nil, nil, nil)(nil, nil, info, handler); err != nil {
 		t.Error("error on process StreamInterceptor: ", err)
 	}
 }
@@ -157,7 +164,7 @@ func TestLoginStreamInterceptor(t *testing.T) {
 		Email:    expectedUserEmail,
 	}
 
-	validToken, err := authenticator.GenerateToken(expectedUserEmail)
+	validToken, err := authenticator.GenerateToken(expectedUserEmail, 0)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
@@ -165,7 +172,7 @@ func TestLoginStreamInterceptor(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
 	stream := &serverStreamWrapper{ctx: ctx}
-	if err := loginStreamInterceptor(authenticator, uOps)(nil, stream, info, handler); err != nil {
+	if err := loginStreamInterceptor(authenticator, uOps, token.NewFakeOperations())(nil, stream, info, handler); err != nil {
 		t.Errorf("expected successful execution, got error %v", err)
 	}
 }
@@ -228,3 +235,206 @@ func TestLogUnaryInterceptor(t *testing.T) {
 		}
 	}
 }
+
+func TestAuditUnaryInterceptor(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", u)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	for _, method := range []string{"/app.App/SetEnv", "/app.App/Info"} {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		req := &apppb.SetEnvRequest{Name: "teresa"}
+		if _, err := auditUnaryInterceptor(aOps)(ctx, req, info, handler); err != auth.ErrPermissionDenied {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+
+	entries := aOps.(*audit.FakeOperations).Entries
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Method != "App.SetEnv" || e.App != "teresa" || e.User != u.Email || e.Success {
+		t.Errorf("got unexpected entry %+v", e)
+	}
+}
+
+func TestAuditUnaryInterceptorFailedLogin(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	req := &userpb.LoginRequest{Email: "gopher@luizalabs.com", Password: "wrong"}
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.User/Login"}
+
+	for _, expectedErr := range []error{auth.ErrPermissionDenied, nil} {
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, expectedErr
+		}
+		if _, err := auditUnaryInterceptor(aOps)(context.Background(), req, info, handler); err != expectedErr {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+	}
+
+	entries := aOps.(*audit.FakeOperations).Entries
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if e := entries[0]; e.Method != "User.Login" || e.User != req.Email || e.Success {
+		t.Errorf("got unexpected entry %+v", e)
+	}
+}
+
+func TestLoginUnaryInterceptorAPIToken(t *testing.T) {
+	tOps := token.NewFakeOperations()
+	expectedUserEmail := "gopher@luizalabs.com"
+	tOps.(*token.FakeOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
+	tk, err := tOps.Create(&storage.User{Email: expectedUserEmail}, &storage.APIToken{Apps: "teresa", Actions: token.ActionRead})
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		u := ctx.Value("user").(*storage.User)
+		return u.Email, nil
+	}
+	md := metadata.Pairs("token", tk)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	interceptor := loginUnaryInterceptor(authenticator, user.NewFakeOperations(), tOps)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/app.App/Info"}
+	resp, err := interceptor(ctx, &apppb.InfoRequest{Name: "teresa"}, info, handler)
+	if err != nil {
+		t.Fatal("error on process unaryInterceptor: ", err)
+	}
+	if resp != expectedUserEmail {
+		t.Errorf("expected %s, got %v", expectedUserEmail, resp)
+	}
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/app.App/SetEnv"}
+	if _, err := interceptor(ctx, &apppb.SetEnvRequest{Name: "teresa"}, info, handler); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestCheckTOTPRequired(t *testing.T) {
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).TOTPRequiredForAdmins = true
+	admin := &storage.User{Email: "admin@luizalabs.com", IsAdmin: true}
+
+	var testCases = []struct {
+		user        *storage.User
+		fullMethod  string
+		expectedErr error
+	}{
+		{admin, "/app.App/Info", user.ErrTOTPRequired},
+		{admin, "/user.User/EnrollTOTP", nil},
+		{admin, "/user.User/EnableTOTP", nil},
+		{&storage.User{Email: "admin@luizalabs.com", IsAdmin: true, TOTPEnabled: true}, "/app.App/Info", nil},
+		{&storage.User{Email: "gopher@luizalabs.com"}, "/app.App/Info", nil},
+	}
+
+	for _, tc := range testCases {
+		if err := checkTOTPRequired(uOps, tc.user, tc.fullMethod); err != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.fullMethod, err)
+		}
+	}
+}
+
+func TestAuthorizeRevokedSession(t *testing.T) {
+	email := "gopher@luizalabs.com"
+	tk, err := authenticator.GenerateToken(email, 0)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).Storage[email] = &storage.User{Email: email, TokenVersion: 1}
+
+	md := metadata.Pairs("token", tk)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	if _, _, err := authorize(ctx, authenticator, uOps, token.NewFakeOperations()); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+type recvServerStream struct {
+	serverStreamWrapper
+	msgs []*deploypb.DeployRequest
+}
+
+func (s *recvServerStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+	*m.(*deploypb.DeployRequest) = *s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func newDeployInfo(app string) *deploypb.DeployRequest {
+	return &deploypb.DeployRequest{
+		Value: &deploypb.DeployRequest_Info_{Info: &deploypb.DeployRequest_Info{App: app}},
+	}
+}
+
+func recvAll(stream grpc.ServerStream) error {
+	for {
+		if err := stream.RecvMsg(new(deploypb.DeployRequest)); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestLoginStreamInterceptorAPITokenChecksEveryInfo(t *testing.T) {
+	tOps := token.NewFakeOperations()
+	expectedUserEmail := "gopher@luizalabs.com"
+	tOps.(*token.FakeOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
+	tk, err := tOps.Create(&storage.User{Email: expectedUserEmail}, &storage.APIToken{Apps: "teresa", Actions: token.ActionDeploy})
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", tk))
+	info := &grpc.StreamServerInfo{FullMethod: "/deploy.Deploy/Make"}
+	interceptor := loginStreamInterceptor(authenticator, user.NewFakeOperations(), tOps)
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return recvAll(stream)
+	}
+
+	var testCases = []struct {
+		msgs        []*deploypb.DeployRequest
+		expectedErr error
+	}{
+		{[]*deploypb.DeployRequest{newDeployInfo("teresa")}, nil},
+		{[]*deploypb.DeployRequest{newDeployInfo("teresa"), newDeployInfo("other")}, auth.ErrPermissionDenied},
+	}
+
+	for _, tc := range testCases {
+		stream := &recvServerStream{serverStreamWrapper{ctx: ctx}, tc.msgs}
+		if err := interceptor(nil, stream, info, handler); err != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestAuditStreamInterceptorRecordsLastInfo(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "gopher@luizalabs.com"})
+	info := &grpc.StreamServerInfo{FullMethod: "/deploy.Deploy/Make"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return recvAll(stream)
+	}
+
+	msgs := []*deploypb.DeployRequest{newDeployInfo("teresa"), newDeployInfo("other")}
+	stream := &recvServerStream{serverStreamWrapper{ctx: ctx}, msgs}
+	if err := auditStreamInterceptor(aOps)(nil, stream, info, handler); err != nil {
+		t.Fatal("error on process StreamInterceptor: ", err)
+	}
+
+	entries := aOps.(*audit.FakeOperations).Entries
+	if len(entries) != 1 || entries[0].App != "other" {
+		t.Errorf("expected an entry of app other, got %+v", entries)
+	}
+}
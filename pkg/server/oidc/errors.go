@@ -0,0 +1,12 @@
+package oidc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrDisabled     = status.Errorf(codes.FailedPrecondition, "OpenID Connect login is not enabled")
+	ErrInvalidToken = status.Errorf(codes.Unauthenticated, "Invalid ID token")
+	ErrNoEmail      = status.Errorf(codes.InvalidArgument, "ID token without e-mail")
+)
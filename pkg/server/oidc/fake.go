@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	fakeKeyID           = "fake-key"
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// FakeIssuer is an in-process OpenID Connect issuer for tests. It logs in
+// Email (member of Groups) without asking, in the authorization code flow
+// with PKCE and in the device flow, where the first poll is pending.
+type FakeIssuer struct {
+	URL      string
+	ClientID string
+	Email    string
+	Groups   []string
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	mutex    sync.Mutex
+	grants   map[string]*fakeGrant
+}
+
+type fakeGrant struct {
+	nonce     string
+	challenge string
+	polled    bool
+}
+
+// IDToken returns an ID token signed by the issuer with the claims, which
+// override the default ones.
+func (f *FakeIssuer) IDToken(claims jwt.MapClaims) (string, error) {
+	c := jwt.MapClaims{
+		"iss":    f.URL,
+		"aud":    f.ClientID,
+		"sub":    f.Email,
+		"email":  f.Email,
+		"groups": f.Groups,
+		"iat":    time.Now().Unix(),
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		c[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = fakeKeyID
+	return token.SignedString(f.key)
+}
+
+func (f *FakeIssuer) Close() {
+	f.server.Close()
+}
+
+func (f *FakeIssuer) newGrant(g *fakeGrant) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	code := hex.EncodeToString(b)
+	f.mutex.Lock()
+	f.grants[code] = g
+	f.mutex.Unlock()
+	return code
+}
+
+func (f *FakeIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &Discovery{
+		Issuer:                      f.URL,
+		AuthorizationEndpoint:       f.URL + "/authorize",
+		TokenEndpoint:               f.URL + "/token",
+		DeviceAuthorizationEndpoint: f.URL + "/device",
+		JWKSURI:                     f.URL + "/keys",
+	})
+}
+
+func (f *FakeIssuer) keys(w http.ResponseWriter, r *http.Request) {
+	pub := f.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string][]jwk{"keys": {{
+		Kid: fakeKeyID,
+		Kty: "RSA",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (f *FakeIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != f.ClientID || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	code := f.newGrant(&fakeGrant{nonce: q.Get("nonce"), challenge: q.Get("code_challenge")})
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (f *FakeIssuer) device(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != f.ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+	code := f.newGrant(new(fakeGrant))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":      code,
+		"user_code":        "TERE-SA42",
+		"verification_uri": f.URL + "/device/verify",
+		"expires_in":       60,
+		"interval":         1,
+	})
+}
+
+func (f *FakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != f.ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.FormValue("code")
+	if r.FormValue("grant_type") == deviceCodeGrantType {
+		code = r.FormValue("device_code")
+	}
+	f.mutex.Lock()
+	g, found := f.grants[code]
+	pending := found && r.FormValue("grant_type") == deviceCodeGrantType && !g.polled
+	if pending {
+		g.polled = true
+	} else {
+		delete(f.grants, code)
+	}
+	f.mutex.Unlock()
+
+	if !found {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if pending {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+		return
+	}
+	if g.challenge != "" {
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	}
+
+	claims := jwt.MapClaims{}
+	if g.nonce != "" {
+		claims["nonce"] = g.nonce
+	}
+	idToken, err := f.IDToken(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "fake-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// NewFakeIssuer starts a fake issuer, call Close to stop it.
+func NewFakeIssuer(clientID, email string, groups ...string) (*FakeIssuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		return nil, err
+	}
+	f := &FakeIssuer{
+		ClientID: clientID,
+		Email:    email,
+		Groups:   groups,
+		key:      key,
+		grants:   make(map[string]*fakeGrant),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, f.discovery)
+	mux.HandleFunc("/keys", f.keys)
+	mux.HandleFunc("/authorize", f.authorize)
+	mux.HandleFunc("/device", f.device)
+	mux.HandleFunc("/token", f.token)
+	f.server = httptest.NewServer(mux)
+	f.URL = f.server.URL
+	return f, nil
+}
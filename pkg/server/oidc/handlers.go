@@ -0,0 +1,40 @@
+package oidc
+
+import (
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	oidcpb "github.com/luizalabs/teresa-api/pkg/protobuf/oidc"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) Config(ctx context.Context, _ *oidcpb.Empty) (*oidcpb.ConfigResponse, error) {
+	cfg, err := s.ops.Config()
+	if err != nil {
+		return nil, err
+	}
+	return &oidcpb.ConfigResponse{
+		Issuer:   cfg.Issuer,
+		ClientId: cfg.ClientID,
+		Scopes:   cfg.Scopes,
+	}, nil
+}
+
+func (s *Service) Login(ctx context.Context, req *oidcpb.LoginRequest) (*oidcpb.LoginResponse, error) {
+	tokens, err := s.ops.Login(req.IdToken, req.Nonce, req.TotpCode)
+	if err != nil {
+		return nil, err
+	}
+	return &oidcpb.LoginResponse{Token: tokens.Token, RefreshToken: tokens.RefreshToken}, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	oidcpb.RegisterOIDCServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
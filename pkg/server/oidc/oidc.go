@@ -0,0 +1,110 @@
+package oidc
+
+import (
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+// Options configure the OpenID Connect login, read from the TERESA_OIDC_*
+// env vars; it is disabled without an issuer.
+type Options struct {
+	Issuer   string
+	ClientID string   `envconfig:"client_id"`
+	Scopes   []string `default:"openid,email,profile"`
+	// EmailClaim and GroupsClaim are the claims of the ID token with the
+	// e-mail of the user and the groups mapped to teams
+	EmailClaim  string `split_words:"true" default:"email"`
+	GroupsClaim string `split_words:"true" default:"groups"`
+	// GroupsPrefix selects the groups mapped to teams, the team is the name
+	// of the group without the prefix
+	GroupsPrefix string `split_words:"true"`
+}
+
+// Config is what the client needs to get an ID token from the issuer.
+type Config struct {
+	Issuer   string
+	ClientID string
+	Scopes   []string
+}
+
+type Operations interface {
+	Config() (*Config, error)
+	Login(idToken, nonce, code string) (*user.Tokens, error)
+}
+
+type OIDCOperations struct {
+	opts     *Options
+	provider *Provider
+	uOps     user.Operations
+	tOps     team.Operations
+}
+
+func (o *OIDCOperations) Config() (*Config, error) {
+	if o.provider == nil {
+		return nil, ErrDisabled
+	}
+	return &Config{Issuer: o.opts.Issuer, ClientID: o.opts.ClientID, Scopes: o.opts.Scopes}, nil
+}
+
+// Login validates the ID token, creates the user on its first login, syncs
+// its teams with its groups and returns teresa tokens; the users with
+// two-factor authentication must give a code too.
+func (o *OIDCOperations) Login(idToken, nonce, code string) (*user.Tokens, error) {
+	if o.provider == nil {
+		return nil, ErrDisabled
+	}
+	claims, err := o.provider.Verify(idToken, nonce)
+	if err != nil {
+		return nil, teresa_errors.New(ErrInvalidToken, errors.Wrap(err, "validating ID token"))
+	}
+	email, _ := claims[o.opts.EmailClaim].(string)
+	if email == "" {
+		return nil, ErrNoEmail
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, teresa_errors.New(ErrInvalidToken, errors.Errorf("e-mail %s not verified", email))
+	}
+
+	if _, err := o.uOps.Provision(email); err != nil {
+		return nil, err
+	}
+	if err := team.SyncTeams(o.tOps, email, o.teams(claims), o.managed(claims)); err != nil {
+		return nil, err
+	}
+	return o.uOps.IssueTokens(email, code)
+}
+
+func (o *OIDCOperations) teams(claims jwt.MapClaims) []string {
+	groups, _ := claims[o.opts.GroupsClaim].([]interface{})
+	teams := make([]string, 0, len(groups))
+	for _, g := range groups {
+		name, _ := g.(string)
+		if name == "" || !strings.HasPrefix(name, o.opts.GroupsPrefix) {
+			continue
+		}
+		teams = append(teams, strings.TrimPrefix(name, o.opts.GroupsPrefix))
+	}
+	return teams
+}
+
+// managed returns which teams are synced with the groups claim: every team
+// has a group, named with the prefix, so all of them are synced when the ID
+// token has the claim and none without it.
+func (o *OIDCOperations) managed(claims jwt.MapClaims) func(string) bool {
+	_, ok := claims[o.opts.GroupsClaim].([]interface{})
+	return func(string) bool { return ok }
+}
+
+func NewOperations(opts *Options, uOps user.Operations, tOps team.Operations) Operations {
+	o := &OIDCOperations{opts: opts, uOps: uOps, tOps: tOps}
+	if opts.Issuer != "" {
+		o.provider = newProvider(opts.Issuer, opts.ClientID)
+	}
+	return o
+}
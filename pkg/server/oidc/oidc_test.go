@@ -0,0 +1,193 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+const (
+	clientID = "teresa"
+	email    = "gopher@luizalabs.com"
+)
+
+func newTestOperations(t *testing.T, groups ...string) (*FakeIssuer, Operations, *team.FakeOperations) {
+	issuer, err := NewFakeIssuer(clientID, email, groups...)
+	if err != nil {
+		t.Fatal("error starting fake issuer: ", err)
+	}
+	tOps := team.NewFakeOperations().(*team.FakeOperations)
+	tOps.Storage["luizalabs"] = &storage.Team{Name: "luizalabs"}
+	opts := &Options{
+		Issuer:       issuer.URL,
+		ClientID:     clientID,
+		EmailClaim:   "email",
+		GroupsClaim:  "groups",
+		GroupsPrefix: "teresa-",
+	}
+	return issuer, NewOperations(opts, tOps.UserOps, tOps), tOps
+}
+
+func TestOIDCOperationsLogin(t *testing.T) {
+	issuer, ops, tOps := newTestOperations(t, "teresa-luizalabs", "teresa-unknown", "luizalabs-staff")
+	defer issuer.Close()
+
+	idToken, err := issuer.IDToken(jwt.MapClaims{"nonce": "n0nc3"})
+	if err != nil {
+		t.Fatal("error signing id token: ", err)
+	}
+	tokens, err := ops.Login(idToken, "n0nc3", "")
+	if err != nil {
+		t.Fatal("error on login: ", err)
+	}
+	if tokens.Token != "good token" {
+		t.Errorf("expected good token, got %s", tokens.Token)
+	}
+
+	u, err := tOps.UserOps.GetUser(email)
+	if err != nil {
+		t.Fatal("expected the user to be created, got ", err)
+	}
+	if u.Email != email {
+		t.Errorf("expected %s, got %s", email, u.Email)
+	}
+	if role, err := tOps.Role("luizalabs", email); err != nil || role != team.RoleMember {
+		t.Errorf("expected member of luizalabs, got role %q and error %v", role, err)
+	}
+
+	// the second login finds the user
+	if _, err := ops.Login(idToken, "n0nc3", ""); err != nil {
+		t.Error("error on second login: ", err)
+	}
+}
+
+func TestOIDCOperationsLoginSyncsTeams(t *testing.T) {
+	issuer, ops, tOps := newTestOperations(t, "teresa-luizalabs")
+	defer issuer.Close()
+	tOps.UserOps.(*user.FakeOperations).Storage[email] = &storage.User{Email: email}
+	tOps.Storage["gophers"] = &storage.Team{Name: "gophers"}
+	if err := tOps.AddUser("gophers", email, team.RoleOwner); err != nil {
+		t.Fatal("error adding user to team: ", err)
+	}
+
+	var testCases = []struct {
+		claims            jwt.MapClaims
+		expectedLuizalabs error
+		expectedGophers   error
+	}{
+		{jwt.MapClaims{"groups": nil}, team.ErrUserNotInTeam, nil},
+		{jwt.MapClaims{}, nil, team.ErrUserNotInTeam},
+	}
+	for _, tc := range testCases {
+		tc.claims["nonce"] = "n0nc3"
+		idToken, err := issuer.IDToken(tc.claims)
+		if err != nil {
+			t.Fatal("error signing id token: ", err)
+		}
+		if _, err := ops.Login(idToken, "n0nc3", ""); err != nil {
+			t.Fatal("error on login: ", err)
+		}
+		if _, err := tOps.Role("luizalabs", email); err != tc.expectedLuizalabs {
+			t.Errorf("expected %v in luizalabs for claims %v, got %v", tc.expectedLuizalabs, tc.claims, err)
+		}
+		if _, err := tOps.Role("gophers", email); err != tc.expectedGophers {
+			t.Errorf("expected %v in gophers for claims %v, got %v", tc.expectedGophers, tc.claims, err)
+		}
+	}
+}
+
+func TestOIDCOperationsLoginTOTP(t *testing.T) {
+	issuer, ops, tOps := newTestOperations(t)
+	defer issuer.Close()
+
+	tOps.UserOps.(*user.FakeOperations).Storage[email] = &storage.User{Email: email, TOTPEnabled: true}
+	idToken, err := issuer.IDToken(jwt.MapClaims{})
+	if err != nil {
+		t.Fatal("error signing id token: ", err)
+	}
+
+	var testCases = []struct {
+		code        string
+		expectedErr error
+	}{
+		{"", user.ErrTOTPCodeRequired},
+		{"000000", auth.ErrPermissionDenied},
+		{"123456", nil},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ops.Login(idToken, "", tc.code); err != tc.expectedErr {
+			t.Errorf("expected %v for code %q, got %v", tc.expectedErr, tc.code, err)
+		}
+	}
+}
+
+func TestOIDCOperationsLoginInvalidTokens(t *testing.T) {
+	issuer, ops, _ := newTestOperations(t)
+	defer issuer.Close()
+
+	other, err := NewFakeIssuer(clientID, email)
+	if err != nil {
+		t.Fatal("error starting fake issuer: ", err)
+	}
+	defer other.Close()
+	otherToken, _ := other.IDToken(jwt.MapClaims{"iss": issuer.URL})
+
+	var testCases = []struct {
+		claims      jwt.MapClaims
+		nonce       string
+		expectedErr error
+	}{
+		{jwt.MapClaims{"aud": "other-client"}, "", ErrInvalidToken},
+		{jwt.MapClaims{"aud": []string{"other-client", clientID}}, "", nil},
+		{jwt.MapClaims{"iss": "https://other.issuer"}, "", ErrInvalidToken},
+		{jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}, "", ErrInvalidToken},
+		{jwt.MapClaims{"nonce": "n0nc3"}, "other", ErrInvalidToken},
+		{jwt.MapClaims{"email_verified": false}, "", ErrInvalidToken},
+		{jwt.MapClaims{"email": ""}, "", ErrNoEmail},
+	}
+
+	for _, tc := range testCases {
+		idToken, err := issuer.IDToken(tc.claims)
+		if err != nil {
+			t.Fatal("error signing id token: ", err)
+		}
+		if _, err := ops.Login(idToken, tc.nonce, ""); teresa_errors.Get(err) != tc.expectedErr {
+			t.Errorf("expected %v for claims %v, got %v", tc.expectedErr, tc.claims, err)
+		}
+	}
+
+	if _, err := ops.Login(otherToken, "", ""); teresa_errors.Get(err) != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for a token signed by other key, got %v", err)
+	}
+}
+
+func TestOIDCOperationsDisabled(t *testing.T) {
+	ops := NewOperations(&Options{}, user.NewFakeOperations(), team.NewFakeOperations())
+	if _, err := ops.Config(); err != ErrDisabled {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+	if _, err := ops.Login("token", "", ""); err != ErrDisabled {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+}
+
+func TestOIDCOperationsConfig(t *testing.T) {
+	issuer, ops, _ := newTestOperations(t)
+	defer issuer.Close()
+
+	cfg, err := ops.Config()
+	if err != nil {
+		t.Fatal("error getting config: ", err)
+	}
+	if cfg.Issuer != issuer.URL || cfg.ClientID != clientID {
+		t.Errorf("expected issuer %s and client %s, got %+v", issuer.URL, clientID, cfg)
+	}
+}
@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// keysRefetchInterval limits the fetches of the JWKS caused by tokens
+	// with unknown key ids
+	keysRefetchInterval = time.Minute
+)
+
+// Discovery is the part of the provider metadata used by teresa.
+type Discovery struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Provider validates the ID tokens of an issuer with the keys of its JWKS,
+// fetched again when a token has an unknown kid.
+type Provider struct {
+	issuer    string
+	clientID  string
+	client    *http.Client
+	mutex     sync.Mutex
+	jwksURI   string
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// Discover fetches the provider metadata of the issuer.
+func Discover(client *http.Client, issuer string) (*Discovery, error) {
+	resp, err := client.Get(strings.TrimSuffix(issuer, "/") + discoveryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching the provider metadata: %s", resp.Status)
+	}
+	d := new(Discovery)
+	if err := json.NewDecoder(resp.Body).Decode(d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != issuer {
+		return nil, fmt.Errorf("issuer mismatch: expected %s, got %s", issuer, d.Issuer)
+	}
+	return d, nil
+}
+
+func (p *Provider) key(kid string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	if time.Since(p.fetchedAt) < keysRefetchInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := p.fetchKeys(); err != nil {
+		return nil, err
+	}
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (p *Provider) fetchKeys() error {
+	if p.jwksURI == "" {
+		d, err := Discover(p.client, p.issuer)
+		if err != nil {
+			return err
+		}
+		p.jwksURI = d.JWKSURI
+	}
+	resp, err := p.client.Get(p.jwksURI)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching the JWKS: %s", resp.Status)
+	}
+	jwks := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAKey(k)
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	return nil
+}
+
+func parseRSAKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus of key %s: %v", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent of key %s: %v", k.Kid, err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// Verify validates the signature, the issuer, the audience, the expiration
+// and the nonce of the ID token and returns its claims.
+func (p *Provider) Verify(idToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.key(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claims.VerifyIssuer(p.issuer, true) {
+		return nil, fmt.Errorf("invalid issuer %v", claims["iss"])
+	}
+	if !hasAudience(claims, p.clientID) {
+		return nil, fmt.Errorf("invalid audience %v", claims["aud"])
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("token without expiration")
+	}
+	if n, _ := claims["nonce"].(string); n != nonce {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	return claims, nil
+}
+
+func hasAudience(claims jwt.MapClaims, clientID string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if s, _ := a.(string); s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newProvider(issuer, clientID string) *Provider {
+	return &Provider{
+		issuer:   issuer,
+		clientID: clientID,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 
 	"golang.org/x/sync/errgroup"
 
@@ -11,13 +12,19 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa-api/pkg/server/app"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
 	"github.com/luizalabs/teresa-api/pkg/server/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/gitpush"
 	"github.com/luizalabs/teresa-api/pkg/server/healthcheck"
 	"github.com/luizalabs/teresa-api/pkg/server/k8s"
+	"github.com/luizalabs/teresa-api/pkg/server/ldap"
+	"github.com/luizalabs/teresa-api/pkg/server/oidc"
 	st "github.com/luizalabs/teresa-api/pkg/server/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/token"
 	"github.com/luizalabs/teresa-api/pkg/server/user"
+	"github.com/luizalabs/teresa-api/pkg/server/webhook"
 	"github.com/soheilhy/cmux"
 
 	"google.golang.org/grpc"
@@ -25,19 +32,25 @@ import (
 )
 
 type Options struct {
-	Port      string
-	TLSCert   *tls.Certificate
-	Auth      auth.Auth
-	DB        *gorm.DB
-	Storage   st.Storage
-	K8s       k8s.Client
-	DeployOpt *deploy.Options
+	Port       string
+	TLSCert    *tls.Certificate
+	Auth       auth.Auth
+	DB         *gorm.DB
+	Storage    st.Storage
+	K8s        k8s.Client
+	DeployOpt  *deploy.Options
+	WebhookOpt *webhook.Options
+	GitPushOpt *gitpush.Options
+	OIDCOpt    *oidc.Options
+	LDAPOpt    *ldap.Options
+	UserOpt    *user.Options
 }
 
 type Server struct {
 	listener   net.Listener
 	grpcServer *grpc.Server
 	hcServer   *healthcheck.Server
+	gc         *deploy.GarbageCollector
 	opt        *Options
 }
 
@@ -51,6 +64,10 @@ func (s *Server) Run() error {
 	grpcListener := m.Match(grpcMatchers...)
 	httpListener := m.Match(cmux.HTTP1Fast())
 
+	if s.gc != nil {
+		go s.gc.Run()
+	}
+
 	g := new(errgroup.Group)
 	g.Go(func() error { return s.grpcServer.Serve(grpcListener) })
 	g.Go(func() error { return s.hcServer.Run(httpListener) })
@@ -59,18 +76,20 @@ func (s *Server) Run() error {
 	return g.Wait()
 }
 
-func createSeverOps(opt Options, uOps user.Operations) []grpc.ServerOption {
+func createSeverOps(opt Options, uOps user.Operations, aOps audit.Operations, tkOps token.Operations) []grpc.ServerOption {
 	recOpts := []grpc_recovery.Option{
 		grpc_recovery.WithRecoveryHandler(recFunc),
 	}
 	sOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			loginUnaryInterceptor(opt.Auth, uOps),
+			loginUnaryInterceptor(opt.Auth, uOps, tkOps),
+			auditUnaryInterceptor(aOps),
 			logUnaryInterceptor,
 			grpc_recovery.UnaryServerInterceptor(recOpts...),
 		)),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			loginStreamInterceptor(opt.Auth, uOps),
+			loginStreamInterceptor(opt.Auth, uOps, tkOps),
+			auditStreamInterceptor(aOps),
 			logStreamInterceptor,
 			grpc_recovery.StreamServerInterceptor(recOpts...),
 		)),
@@ -82,21 +101,39 @@ func createSeverOps(opt Options, uOps user.Operations) []grpc.ServerOption {
 	return sOpts
 }
 
-func registerServices(s *grpc.Server, opt Options, uOps user.Operations) {
-	us := user.NewService(uOps)
+func registerServices(s *grpc.Server, opt Options, uOps user.Operations, tOps team.Operations, aOps audit.Operations, tkOps token.Operations) deploy.Operations {
+	us := user.NewService(uOps, opt.UserOpt)
 	us.RegisterService(s)
 
-	tOps := team.NewDatabaseOperations(opt.DB, uOps)
 	t := team.NewService(tOps)
 	t.RegisterService(s)
 
-	appOps := app.NewOperations(tOps, opt.K8s, opt.Storage)
+	as := audit.NewService(aOps)
+	as.RegisterService(s)
+
+	tks := token.NewService(tkOps)
+	tks.RegisterService(s)
+
+	oidcOpt := opt.OIDCOpt
+	if oidcOpt == nil {
+		oidcOpt = new(oidc.Options)
+	}
+	o := oidc.NewService(oidc.NewOperations(oidcOpt, uOps, tOps))
+	o.RegisterService(s)
+
+	whOps := webhook.NewDatabaseOperations(opt.DB, tOps, opt.K8s, opt.WebhookOpt)
+	wh := webhook.NewService(whOps)
+	wh.RegisterService(s)
+
+	appOps := app.NewOperations(tOps, opt.K8s, opt.Storage, whOps)
 	a := app.NewService(appOps)
 	a.RegisterService(s)
 
-	dOps := deploy.NewDeployOperations(appOps, opt.K8s, opt.Storage)
+	dOps := deploy.NewDeployOperations(appOps, opt.K8s, opt.Storage, whOps)
 	d := deploy.NewService(dOps, opt.DeployOpt)
 	d.RegisterService(s)
+
+	return dOps
 }
 
 func New(opt Options) (*Server, error) {
@@ -105,11 +142,34 @@ func New(opt Options) (*Server, error) {
 		return nil, err
 	}
 
-	uOps := user.NewDatabaseOperations(opt.DB, opt.Auth)
-	sOpts := createSeverOps(opt, uOps)
+	var authenticators []user.Authenticator
+	if opt.LDAPOpt != nil && opt.LDAPOpt.URL != "" {
+		l, err := ldap.New(opt.LDAPOpt)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, l)
+	}
+
+	uOps := user.NewDatabaseOperations(opt.DB, opt.Auth, opt.UserOpt, authenticators...)
+	tOps := team.NewDatabaseOperations(opt.DB, uOps, opt.K8s)
+	aOps := audit.NewDatabaseOperations(opt.DB, tOps, opt.K8s)
+	tkOps := token.NewDatabaseOperations(opt.DB, uOps, opt.K8s)
+	sOpts := createSeverOps(opt, uOps, aOps, tkOps)
 	s := grpc.NewServer(sOpts...)
-	registerServices(s, opt, uOps)
+	dOps := registerServices(s, opt, uOps, tOps, aOps, tkOps)
 
 	hcServer := healthcheck.New(opt.K8s, opt.DB)
-	return &Server{listener: l, grpcServer: s, hcServer: hcServer, opt: &opt}, nil
+	if h, ok := opt.Storage.(http.Handler); ok {
+		hcServer.Handle(st.FilesystemHTTPPath, h)
+	}
+	hcServer.Handle(deploy.SlugsHTTPPath, deploy.NewSlugHandler(opt.Storage, opt.DeployOpt))
+	if opt.GitPushOpt != nil && opt.GitPushOpt.Enabled() {
+		hcServer.Handle(gitpush.Path, gitpush.NewHandler(dOps, uOps, aOps, opt.GitPushOpt, opt.DeployOpt))
+	}
+	var gc *deploy.GarbageCollector
+	if opt.DeployOpt.GCInterval > 0 {
+		gc = deploy.NewGarbageCollector(opt.K8s, opt.Storage, opt.DeployOpt)
+	}
+	return &Server{listener: l, grpcServer: s, hcServer: hcServer, gc: gc, opt: &opt}, nil
 }
@@ -5,5 +5,7 @@ import (
 )
 
 var (
-	ErrInvalidStorageType = errors.New("Invalid storage type")
+	ErrInvalidStorageType      = errors.New("Invalid storage type")
+	ErrInvalidFilesystemConfig = errors.New("Filesystem storage requires an url and a signing key")
+	ErrInvalidPath             = errors.New("Invalid storage path")
 )
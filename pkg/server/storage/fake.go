@@ -1,46 +1,39 @@
 package storage
 
 import (
+	"fmt"
 	"io"
+	"time"
 )
 
 type fake struct {
-	Key    string
-	Secret string
-	Region string
 	Bucket string
 }
 
-func (f *fake) K8sSecretName() string {
-	return "fake-storage"
+func (f *fake) UploadFile(path string, file io.ReadSeeker) error {
+	return nil
 }
 
-func (f *fake) AccessData() map[string][]byte {
-	return map[string][]byte{
-		"region":         []byte(f.Region),
-		"builder-bucket": []byte(f.Bucket),
-		"accesskey":      []byte(f.Key),
-		"secretkey":      []byte(f.Secret),
-	}
+func (f *fake) Delete(path string) error {
+	return nil
 }
 
-func (f *fake) UploadFile(path string, file io.ReadSeeker) error {
-	return nil
+func (f *fake) List(prefix string) ([]*Object, error) {
+	return nil, nil
 }
 
-func (f *fake) Type() string {
-	return string(FakeType)
+func (f *fake) PresignGet(path string, expires time.Duration) (string, error) {
+	return fmt.Sprintf("https://%s/%s?method=GET", f.Bucket, path), nil
+}
+
+func (f *fake) PresignPut(path string, expires time.Duration) (string, error) {
+	return fmt.Sprintf("https://%s/%s?method=PUT", f.Bucket, path), nil
 }
 
-func (f *fake) PodEnvVars() map[string]string {
-	return make(map[string]string)
+func (f *fake) Type() string {
+	return string(FakeType)
 }
 
 func NewFake() Storage {
-	return &fake{
-		Key:    "key",
-		Region: "region",
-		Secret: "secret",
-		Bucket: "bucket",
-	}
+	return &fake{Bucket: "bucket"}
 }
@@ -4,14 +4,6 @@ import (
 	"testing"
 )
 
-func TestFakeK8sSecretName(t *testing.T) {
-	fake := NewFake()
-
-	if name := fake.K8sSecretName(); name != "fake-storage" {
-		t.Errorf("expected fake-storage, got %s", name)
-	}
-}
-
 func TestFakeType(t *testing.T) {
 	fake := NewFake()
 
@@ -20,26 +12,6 @@ func TestFakeType(t *testing.T) {
 	}
 }
 
-func TestFakeAccessData(t *testing.T) {
-	ad := NewFake().AccessData()
-	var testCases = []struct {
-		key   string
-		value string
-	}{
-		{"region", "region"},
-		{"builder-bucket", "bucket"},
-		{"accesskey", "key"},
-		{"secretkey", "secret"},
-	}
-
-	for _, tc := range testCases {
-		got := string(ad[tc.key])
-		if got != tc.value {
-			t.Errorf("expected %s, got %s", tc.value, got)
-		}
-	}
-}
-
 func TestFakeUploadFile(t *testing.T) {
 	fake := NewFake()
 
@@ -47,11 +19,3 @@ func TestFakeUploadFile(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 	}
 }
-
-func TestFakePodEnvVars(t *testing.T) {
-	fake := NewFake()
-	ev := fake.PodEnvVars()
-	if len(ev) != 0 {
-		t.Errorf("expected 0, got %d", len(ev))
-	}
-}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilesystemHTTPPath is where the teresa server serves the files of the
+// filesystem storage to the build and run pods.
+const FilesystemHTTPPath = "/storage/"
+
+// Filesystem keeps the files in a local directory (usually a mounted
+// volume) and serves them over HTTP through URLs signed with a key.
+type Filesystem struct {
+	Root       string
+	URL        string
+	SigningKey string
+	now        func() time.Time
+}
+
+// fullPath returns the path of the file in the local filesystem, or an
+// empty string if the path would fall outside the root directory.
+func (f *Filesystem) fullPath(p string) string {
+	clean := path.Clean("/" + p)
+	if clean == "/" {
+		return ""
+	}
+	return filepath.Join(f.Root, filepath.FromSlash(clean))
+}
+
+func (f *Filesystem) UploadFile(p string, file io.ReadSeeker) error {
+	return f.write(p, file)
+}
+
+func (f *Filesystem) write(p string, r io.Reader) error {
+	dest := f.fullPath(p)
+	if dest == "" {
+		return ErrInvalidPath
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), ".upload")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+func (f *Filesystem) Delete(p string) error {
+	full := f.fullPath(p)
+	if full == "" {
+		return ErrInvalidPath
+	}
+	if err := os.Remove(full); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *Filesystem) List(prefix string) ([]*Object, error) {
+	var objs []*Object
+	err := filepath.Walk(f.Root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(f.Root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, prefix) && !strings.HasPrefix(info.Name(), ".upload") {
+			objs = append(objs, &Object{Path: rel, LastModified: info.ModTime()})
+		}
+		return nil
+	})
+	return objs, err
+}
+
+func (f *Filesystem) PresignGet(p string, expires time.Duration) (string, error) {
+	return f.presign("GET", p, expires), nil
+}
+
+func (f *Filesystem) PresignPut(p string, expires time.Duration) (string, error) {
+	return f.presign("PUT", p, expires), nil
+}
+
+func (f *Filesystem) presign(method, p string, expires time.Duration) string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	exp := strconv.FormatInt(f.now().Add(expires).Unix(), 10)
+	q := url.Values{
+		"expires":   {exp},
+		"signature": {f.sign(method, p, exp)},
+	}
+	return fmt.Sprintf("%s%s%s?%s", strings.TrimSuffix(f.URL, "/"), FilesystemHTTPPath, p, q.Encode())
+}
+
+func (f *Filesystem) sign(method, p, expires string) string {
+	mac := hmac.New(sha256.New, []byte(f.SigningKey))
+	fmt.Fprintf(mac, "%s\n%s\n%s", method, p, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature checks the signature and the expiration of a presigned
+// URL; the URLs signed for GET are also valid for HEAD.
+func (f *Filesystem) validSignature(r *http.Request, p string) bool {
+	method := r.Method
+	if method == "HEAD" {
+		method = "GET"
+	}
+	q := r.URL.Query()
+	exp := q.Get("expires")
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || f.now().Unix() > expUnix {
+		return false
+	}
+	actual, err := hex.DecodeString(q.Get("signature"))
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(f.sign(method, p, exp))
+	return hmac.Equal(actual, expected)
+}
+
+func (f *Filesystem) Type() string {
+	return string(FilesystemType)
+}
+
+// ServeHTTP lets the build and run pods download (GET) and upload (PUT)
+// files through presigned URLs, as in
+// GET /storage/deploys/<app>/<id>/out/slug.tgz?expires=...&signature=...
+func (f *Filesystem) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.Path, FilesystemHTTPPath)
+	full := f.fullPath(p)
+	if full == "" {
+		http.Error(w, "invalid path", http.StatusBadRequest)
+		return
+	}
+	if !f.validSignature(r, p) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case "GET", "HEAD":
+		file, err := os.Open(full)
+		if err != nil {
+			if os.IsNotExist(err) {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+	case "PUT":
+		if err := f.write(p, r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func newFilesystem(conf *Config) (Storage, error) {
+	if conf.FilesystemURL == "" || conf.FilesystemSigningKey == "" {
+		return nil, ErrInvalidFilesystemConfig
+	}
+	return &Filesystem{
+		Root:       conf.FilesystemRoot,
+		URL:        conf.FilesystemURL,
+		SigningKey: conf.FilesystemSigningKey,
+		now:        time.Now,
+	}, nil
+}
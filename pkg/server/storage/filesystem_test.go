@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestFilesystem(t *testing.T) (*Filesystem, func()) {
+	dir, err := ioutil.TempDir("", "storage")
+	if err != nil {
+		t.Fatal("error creating temp dir: ", err)
+	}
+	conf := &Config{
+		FilesystemRoot:       dir,
+		FilesystemURL:        "http://teresa:50051",
+		FilesystemSigningKey: "key",
+	}
+	fs, err := newFilesystem(conf)
+	if err != nil {
+		t.Fatal("error creating filesystem storage: ", err)
+	}
+	return fs.(*Filesystem), func() { os.RemoveAll(dir) }
+}
+
+func TestFilesystemUploadListDelete(t *testing.T) {
+	fs, cleanup := newTestFilesystem(t)
+	defer cleanup()
+
+	for _, p := range []string{"deploys/teresa/1/in/app.tar.gz", "other/file"} {
+		if err := fs.UploadFile(p, strings.NewReader("content")); err != nil {
+			t.Fatal("error uploading file: ", err)
+		}
+	}
+
+	objs, err := fs.List("deploys/")
+	if err != nil {
+		t.Fatal("error listing files: ", err)
+	}
+	if len(objs) != 1 || objs[0].Path != "deploys/teresa/1/in/app.tar.gz" {
+		t.Fatalf("expected only the deploy tarball, got %v", objs)
+	}
+
+	if err := fs.Delete(objs[0].Path); err != nil {
+		t.Fatal("error deleting file: ", err)
+	}
+	if err := fs.Delete(objs[0].Path); err != nil {
+		t.Errorf("expected no error deleting a missing file, got %v", err)
+	}
+	if objs, _ := fs.List("deploys/"); len(objs) != 0 {
+		t.Errorf("expected no files, got %v", objs)
+	}
+}
+
+func TestFilesystemPathTraversal(t *testing.T) {
+	fs, cleanup := newTestFilesystem(t)
+	defer cleanup()
+
+	if err := fs.UploadFile("../../outside", strings.NewReader("content")); err != nil {
+		t.Fatal("error uploading file: ", err)
+	}
+	objs, _ := fs.List("")
+	if len(objs) != 1 || objs[0].Path != "outside" {
+		t.Errorf("expected the file inside the root directory, got %v", objs)
+	}
+	if err := fs.UploadFile("/", strings.NewReader("content")); err != ErrInvalidPath {
+		t.Errorf("expected ErrInvalidPath, got %v", err)
+	}
+}
+
+func TestFilesystemServeHTTP(t *testing.T) {
+	fs, cleanup := newTestFilesystem(t)
+	defer cleanup()
+
+	p := "deploys/teresa/1/out/slug.tgz"
+	putURL, _ := fs.PresignPut(p, time.Minute)
+	getURL, _ := fs.PresignGet(p, time.Minute)
+	expiredURL, _ := fs.PresignGet(p, -time.Minute)
+	otherURL, _ := fs.PresignGet("deploys/other/1/out/slug.tgz", time.Minute)
+
+	var testCases = []struct {
+		method       string
+		url          string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"PUT", getURL, "slug", http.StatusForbidden, ""},
+		{"GET", getURL, "", http.StatusNotFound, ""},
+		{"PUT", putURL, "slug", http.StatusCreated, ""},
+		{"GET", getURL, "", http.StatusOK, "slug"},
+		{"HEAD", getURL, "", http.StatusOK, ""},
+		{"GET", expiredURL, "", http.StatusForbidden, ""},
+		{"GET", strings.Replace(otherURL, "deploys/other", "deploys/teresa", 1), "", http.StatusForbidden, ""},
+		{"GET", FilesystemHTTPPath + p, "", http.StatusForbidden, ""},
+		{"DELETE", putURL, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range testCases {
+		req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal("error creating request: ", err)
+		}
+		w := httptest.NewRecorder()
+		fs.ServeHTTP(w, req)
+
+		if w.Code != tc.expectedCode {
+			t.Errorf("expected %d for %s %s, got %d", tc.expectedCode, tc.method, tc.url, w.Code)
+		}
+		if tc.expectedBody != "" && w.Body.String() != tc.expectedBody {
+			t.Errorf("expected %s, got %s", tc.expectedBody, w.Body.String())
+		}
+	}
+}
+
+func TestFilesystemPresign(t *testing.T) {
+	fs, cleanup := newTestFilesystem(t)
+	defer cleanup()
+
+	u, err := fs.PresignGet("/deploys/teresa/1/out/slug.tgz", time.Minute)
+	if err != nil {
+		t.Fatal("error presigning url: ", err)
+	}
+	expectedPrefix := "http://teresa:50051/storage/deploys/teresa/1/out/slug.tgz?expires="
+	if !strings.HasPrefix(u, expectedPrefix) {
+		t.Errorf("expected prefix %s, got %s", expectedPrefix, u)
+	}
+}
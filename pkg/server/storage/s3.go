@@ -2,15 +2,21 @@ package storage
 
 import (
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 type S3Client interface {
 	PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	DeleteObject(*s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	ListObjectsPages(*s3.ListObjectsInput, func(*s3.ListObjectsOutput, bool) bool) error
+	GetObjectRequest(*s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput)
+	PutObjectRequest(*s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput)
 }
 
 type S3 struct {
@@ -24,19 +30,6 @@ type S3 struct {
 	S3ForcePathStyle bool
 }
 
-func (s *S3) K8sSecretName() string {
-	return "s3-storage"
-}
-
-func (s *S3) AccessData() map[string][]byte {
-	return map[string][]byte{
-		"region":         []byte(s.Region),
-		"builder-bucket": []byte(s.Bucket),
-		"accesskey":      []byte(s.Key),
-		"secretkey":      []byte(s.Secret),
-	}
-}
-
 func (s *S3) UploadFile(path string, file io.ReadSeeker) error {
 	po := &s3.PutObjectInput{
 		Bucket: &s.Bucket,
@@ -47,12 +40,51 @@ func (s *S3) UploadFile(path string, file io.ReadSeeker) error {
 	return err
 }
 
-func (s *S3) Type() string {
-	return string(S3Type)
+func (s *S3) Delete(path string) error {
+	do := &s3.DeleteObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &path,
+	}
+	_, err := s.Client.DeleteObject(do)
+	return err
 }
 
-func (s *S3) PodEnvVars() map[string]string {
-	return make(map[string]string)
+func (s *S3) List(prefix string) ([]*Object, error) {
+	lo := &s3.ListObjectsInput{
+		Bucket: &s.Bucket,
+		Prefix: &prefix,
+	}
+	var objs []*Object
+	err := s.Client.ListObjectsPages(lo, func(out *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, o := range out.Contents {
+			objs = append(objs, &Object{
+				Path:         aws.StringValue(o.Key),
+				LastModified: aws.TimeValue(o.LastModified),
+			})
+		}
+		return true
+	})
+	return objs, err
+}
+
+func (s *S3) PresignGet(path string, expires time.Duration) (string, error) {
+	req, _ := s.Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &path,
+	})
+	return req.Presign(expires)
+}
+
+func (s *S3) PresignPut(path string, expires time.Duration) (string, error) {
+	req, _ := s.Client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &path,
+	})
+	return req.Presign(expires)
+}
+
+func (s *S3) Type() string {
+	return string(S3Type)
 }
 
 func newS3(conf *Config) Storage {
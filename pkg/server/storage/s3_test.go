@@ -1,9 +1,12 @@
 package storage
 
 import (
-	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
@@ -23,12 +26,30 @@ func (f *fakeS3Client) PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error
 	return nil, nil
 }
 
-func TestS3K8sSecretName(t *testing.T) {
-	s3 := newS3(&Config{})
+func (f *fakeS3Client) DeleteObject(*s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	return nil, nil
+}
 
-	if name := s3.K8sSecretName(); name != "s3-storage" {
-		t.Errorf("expected s3-storage, got %s", name)
+func (f *fakeS3Client) ListObjectsPages(in *s3.ListObjectsInput, fn func(*s3.ListObjectsOutput, bool) bool) error {
+	pages := [][]string{{"deploys/teresa/1/in/app.tar.gz"}, {"deploys/teresa/1/out/slug.tgz"}}
+	for i, keys := range pages {
+		out := &s3.ListObjectsOutput{}
+		for _, k := range keys {
+			out.Contents = append(out.Contents, &s3.Object{Key: aws.String(k), LastModified: aws.Time(time.Now())})
+		}
+		if !fn(out, i == len(pages)-1) {
+			break
+		}
 	}
+	return nil
+}
+
+func (f *fakeS3Client) GetObjectRequest(in *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput) {
+	return nil, nil
+}
+
+func (f *fakeS3Client) PutObjectRequest(in *s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput) {
+	return nil, nil
 }
 
 func TestS3Type(t *testing.T) {
@@ -39,48 +60,69 @@ func TestS3Type(t *testing.T) {
 	}
 }
 
-func TestS3AccessData(t *testing.T) {
-	conf := &Config{
-		AwsRegion: "region",
-		AwsSecret: "secret",
-		AwsKey:    "key",
-		AwsBucket: "bucket",
-	}
-	s3 := newS3(conf)
-	ad := s3.AccessData()
-	var testCases = []struct {
-		key   string
-		field string
-	}{
-		{"region", "AwsRegion"},
-		{"builder-bucket", "AwsBucket"},
-		{"accesskey", "AwsKey"},
-		{"secretkey", "AwsSecret"},
-	}
+func TestS3UploadFile(t *testing.T) {
+	s3 := newS3(&Config{})
+	s3.(*S3).Client = &fakeS3Client{}
 
-	for _, tc := range testCases {
-		v := reflect.ValueOf(conf)
-		expected := reflect.Indirect(v).FieldByName(tc.field).String()
-		got := string(ad[tc.key])
-		if got != expected {
-			t.Errorf("expected %s, got %s", expected, got)
-		}
+	if err := s3.UploadFile("/test", &fakeReadSeeker{}); err != nil {
+		t.Errorf("expected no error, got %v", err)
 	}
 }
 
-func TestS3UploadFile(t *testing.T) {
+func TestS3Delete(t *testing.T) {
 	s3 := newS3(&Config{})
 	s3.(*S3).Client = &fakeS3Client{}
 
-	if err := s3.UploadFile("/test", &fakeReadSeeker{}); err != nil {
+	if err := s3.Delete("/test"); err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
 }
 
-func TestS3PodEnvVars(t *testing.T) {
+func TestS3List(t *testing.T) {
 	s3 := newS3(&Config{})
-	ev := s3.PodEnvVars()
-	if len(ev) != 0 {
-		t.Errorf("expected 0, got %d", len(ev))
+	s3.(*S3).Client = &fakeS3Client{}
+
+	objs, err := s3.List("deploys/teresa/")
+	if err != nil {
+		t.Fatal("error listing objects: ", err)
+	}
+	expected := []string{"deploys/teresa/1/in/app.tar.gz", "deploys/teresa/1/out/slug.tgz"}
+	if len(objs) != len(expected) {
+		t.Fatalf("expected %d objects, got %d", len(expected), len(objs))
+	}
+	for i, o := range objs {
+		if o.Path != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], o.Path)
+		}
+	}
+}
+
+func TestS3Presign(t *testing.T) {
+	conf := &Config{
+		AwsKey:    "key",
+		AwsSecret: "secret",
+		AwsRegion: "us-east-1",
+		AwsBucket: "bucket",
+	}
+	s3 := newS3(conf)
+
+	var testCases = []struct {
+		presign func(string, time.Duration) (string, error)
+	}{
+		{s3.PresignGet},
+		{s3.PresignPut},
+	}
+
+	for _, tc := range testCases {
+		u, err := tc.presign("deploys/teresa/1/out/slug.tgz", time.Minute)
+		if err != nil {
+			t.Fatal("error presigning url: ", err)
+		}
+		if !strings.Contains(u, "deploys/teresa/1/out/slug.tgz") {
+			t.Errorf("expected the object key in %s", u)
+		}
+		if !strings.Contains(u, "X-Amz-Signature=") || !strings.Contains(u, "X-Amz-Expires=60") {
+			t.Errorf("expected a presigned url valid for 60 seconds, got %s", u)
+		}
 	}
 }
@@ -2,33 +2,47 @@ package storage
 
 import (
 	"io"
+	"time"
 )
 
 type storageType string
 
 const (
-	S3Type    storageType = "s3"
-	MinioType storageType = "minio"
-	FakeType  storageType = "fake"
+	S3Type         storageType = "s3"
+	MinioType      storageType = "minio"
+	FilesystemType storageType = "filesystem"
+	FakeType       storageType = "fake"
 )
 
 type Config struct {
-	Type                storageType `envconfig:"type" default:"s3"`
-	AwsKey              string      `envconfig:"aws_key"`
-	AwsSecret           string      `envconfig:"aws_secret"`
-	AwsRegion           string      `envconfig:"aws_region"`
-	AwsBucket           string      `envconfig:"aws_bucket"`
-	AwsEndpoint         string      `envconfig:"aws_endpoint" default:""`
-	AwsDisableSSL       bool        `envconfig:"aws_disable_ssl" default:"false"`
-	AwsS3ForcePathStyle bool        `envconfig:"aws_s3_force_path_style" default:"false"`
+	Type                 storageType `envconfig:"type" default:"s3"`
+	AwsKey               string      `envconfig:"aws_key"`
+	AwsSecret            string      `envconfig:"aws_secret"`
+	AwsRegion            string      `envconfig:"aws_region"`
+	AwsBucket            string      `envconfig:"aws_bucket"`
+	AwsEndpoint          string      `envconfig:"aws_endpoint" default:""`
+	AwsDisableSSL        bool        `envconfig:"aws_disable_ssl" default:"false"`
+	AwsS3ForcePathStyle  bool        `envconfig:"aws_s3_force_path_style" default:"false"`
+	FilesystemRoot       string      `envconfig:"filesystem_root" default:"/var/lib/teresa/storage"`
+	FilesystemURL        string      `envconfig:"filesystem_url"`
+	FilesystemSigningKey string      `envconfig:"filesystem_signing_key"`
 }
 
+// Object is a file kept in the storage
+type Object struct {
+	Path         string
+	LastModified time.Time
+}
+
+// Storage keeps the deploy artifacts. The pods never get its credentials,
+// only presigned URLs valid for a single file and a short time.
 type Storage interface {
-	K8sSecretName() string
-	AccessData() map[string][]byte
 	UploadFile(path string, file io.ReadSeeker) error
+	Delete(path string) error
+	List(prefix string) ([]*Object, error)
+	PresignGet(path string, expires time.Duration) (string, error)
+	PresignPut(path string, expires time.Duration) (string, error)
 	Type() string
-	PodEnvVars() map[string]string
 }
 
 func New(conf *Config) (Storage, error) {
@@ -37,6 +51,8 @@ func New(conf *Config) (Storage, error) {
 		return newS3(conf), nil
 	case MinioType:
 		return newMinio(conf), nil
+	case FilesystemType:
+		return newFilesystem(conf)
 	default:
 		return nil, ErrInvalidStorageType
 	}
@@ -11,6 +11,7 @@ func TestNewStorage(t *testing.T) {
 	}{
 		{"s3", nil},
 		{"InvalidType", ErrInvalidStorageType},
+		{"filesystem", ErrInvalidFilesystemConfig},
 	}
 
 	for _, tc := range testCases {
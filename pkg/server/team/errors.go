@@ -9,4 +9,7 @@ var (
 	ErrTeamAlreadyExists = status.Errorf(codes.AlreadyExists, "Team already exists")
 	ErrUserAlreadyInTeam = status.Errorf(codes.AlreadyExists, "User already in Team")
 	ErrNotFound          = status.Errorf(codes.NotFound, "Team Not Found")
+	ErrUserNotInTeam     = status.Errorf(codes.NotFound, "User not in Team")
+	ErrInvalidRole       = status.Errorf(codes.InvalidArgument, "Invalid role, use owner, member or viewer")
+	ErrTeamHasApps       = status.Errorf(codes.FailedPrecondition, "Team still has apps, use force to delete them with the team")
 )
@@ -10,6 +10,11 @@ import (
 type FakeOperations struct {
 	mutex   *sync.RWMutex
 	Storage map[string]*storage.Team
+	// Apps holds the names of the apps owned by each team
+	Apps map[string][]string
+	// Roles holds the role of the users of each team by email, the users
+	// without an entry are members
+	Roles map[string]map[string]string
 
 	UserOps user.Operations
 }
@@ -26,7 +31,14 @@ func (f *FakeOperations) Create(name, email, url string) error {
 	return nil
 }
 
-func (f *FakeOperations) AddUser(name, userEmail string) error {
+func (f *FakeOperations) AddUser(name, userEmail, role string) error {
+	if role == "" {
+		role = RoleMember
+	}
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -47,12 +59,125 @@ func (f *FakeOperations) AddUser(name, userEmail string) error {
 	}
 
 	t.Users = append(t.Users, *u)
+	f.setRole(name, userEmail, role)
+	return nil
+}
+
+func (f *FakeOperations) SetRole(name, userEmail, role string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, err := f.role(name, userEmail); err != nil {
+		return err
+	}
+	f.setRole(name, userEmail, role)
+	return nil
+}
+
+func (f *FakeOperations) Role(name, userEmail string) (string, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.role(name, userEmail)
+}
+
+func (f *FakeOperations) role(name, userEmail string) (string, error) {
+	var t *storage.Team
+	for _, v := range f.Storage {
+		if v.Name == name {
+			t = v
+			break
+		}
+	}
+	if t == nil {
+		return "", ErrNotFound
+	}
+	for _, userOfTeam := range t.Users {
+		if userOfTeam.Email != userEmail {
+			continue
+		}
+		if role, ok := f.Roles[name][userEmail]; ok {
+			return role, nil
+		}
+		return RoleMember, nil
+	}
+	return "", ErrUserNotInTeam
+}
+
+func (f *FakeOperations) setRole(name, userEmail, role string) {
+	if f.Roles[name] == nil {
+		f.Roles[name] = make(map[string]string)
+	}
+	f.Roles[name][userEmail] = role
+}
+
+func (f *FakeOperations) RemoveUser(name, userEmail string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	t, found := f.Storage[name]
+	if !found {
+		return ErrNotFound
+	}
+
+	if _, err := f.UserOps.GetUser(userEmail); err != nil {
+		return err
+	}
+
+	for i, userOfTeam := range t.Users {
+		if userOfTeam.Email == userEmail {
+			t.Users = append(t.Users[:i], t.Users[i+1:]...)
+			delete(f.Roles[name], userEmail)
+			return nil
+		}
+	}
+	return ErrUserNotInTeam
+}
+
+func (f *FakeOperations) Delete(name string, force bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[name]; !found {
+		return ErrNotFound
+	}
+
+	if !force && len(f.Apps[name]) > 0 {
+		return ErrTeamHasApps
+	}
+
+	delete(f.Apps, name)
+	delete(f.Storage, name)
+	delete(f.Roles, name)
+	return nil
+}
+
+func (f *FakeOperations) Update(name, email, url string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	t, found := f.Storage[name]
+	if !found {
+		return ErrNotFound
+	}
+
+	if email != "" {
+		t.Email = email
+	}
+	if url != "" {
+		t.URL = url
+	}
 	return nil
 }
 
 func (f *FakeOperations) List() ([]*storage.Team, error) {
 	var teams []*storage.Team
 	for _, v := range f.Storage {
+		v.Roles = f.Roles[v.Name]
 		teams = append(teams, v)
 	}
 	return teams, nil
@@ -63,6 +188,7 @@ func (f *FakeOperations) ListByUser(userEmail string) ([]*storage.Team, error) {
 	for _, v := range f.Storage {
 		for _, u := range v.Users {
 			if u.Email == userEmail {
+				v.Roles = f.Roles[v.Name]
 				teams = append(teams, v)
 			}
 		}
@@ -74,5 +200,7 @@ func NewFakeOperations() Operations {
 	return &FakeOperations{
 		mutex:   &sync.RWMutex{},
 		Storage: make(map[string]*storage.Team),
+		Apps:    make(map[string][]string),
+		Roles:   make(map[string]map[string]string),
 		UserOps: user.NewFakeOperations()}
 }
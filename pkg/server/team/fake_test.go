@@ -56,7 +56,7 @@ func TestFakeOperationsAddUser(t *testing.T) {
 		t.Fatal("error trying to create a fake team:", err)
 	}
 
-	if err := fake.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := fake.AddUser(expectedTeam, expectedUserEmail, RoleMember); err != nil {
 		t.Errorf("error trying on add user to a team: %v", err)
 	}
 }
@@ -64,7 +64,7 @@ func TestFakeOperationsAddUser(t *testing.T) {
 func TestFakeOperationsAddUserTeamNotFound(t *testing.T) {
 	fake := NewFakeOperations()
 
-	if err := fake.AddUser("teresa", "gopher"); err != ErrNotFound {
+	if err := fake.AddUser("teresa", "gopher", RoleMember); err != ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -77,7 +77,7 @@ func TestFakeOperationsAddUserUserNotFound(t *testing.T) {
 		t.Fatal("error trying to create a fake team:", err)
 	}
 
-	if err := fake.AddUser(expectedTeam, "gopher"); err != user.ErrNotFound {
+	if err := fake.AddUser(expectedTeam, "gopher", RoleMember); err != user.ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -93,7 +93,7 @@ func TestFakeOperationsAddUserUserAlreadyInTeam(t *testing.T) {
 		Users: []storage.User{storage.User{Email: expectedUserEmail}},
 	}
 
-	if err := fake.AddUser(expectedName, expectedUserEmail); err != ErrUserAlreadyInTeam {
+	if err := fake.AddUser(expectedName, expectedUserEmail, RoleMember); err != ErrUserAlreadyInTeam {
 		t.Errorf("expected error ErrUserAlreadyInTeam, got %v", err)
 	}
 }
@@ -187,3 +187,39 @@ func TestFakeOperationsListByUserWithoutTeams(t *testing.T) {
 		t.Errorf("expected 0, got %d", len(teams))
 	}
 }
+
+func TestFakeOperationsRemoveUser(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedUserEmail := "gopher"
+	expectedName := "teresa"
+	fake.(*FakeOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{
+		Name:  expectedName,
+		Users: []storage.User{storage.User{Email: expectedUserEmail}},
+	}
+
+	for _, expectedErr := range []error{nil, ErrUserNotInTeam} {
+		if err := fake.RemoveUser(expectedName, expectedUserEmail); err != expectedErr {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+	}
+}
+
+func TestFakeOperationsDeleteTeamHasApps(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{Name: expectedName}
+	fake.(*FakeOperations).Apps[expectedName] = []string{"app1"}
+
+	if err := fake.Delete(expectedName, false); err != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+	if err := fake.Delete(expectedName, true); err != nil {
+		t.Errorf("error trying to force the deletion of a team: %v", err)
+	}
+	if _, found := fake.(*FakeOperations).Storage[expectedName]; found {
+		t.Errorf("expected team %s to be deleted", expectedName)
+	}
+}
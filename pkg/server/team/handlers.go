@@ -13,6 +13,19 @@ type Service struct {
 	ops Operations
 }
 
+// canManage reports whether the user can manage the members of the team,
+// only admins and the owners of the team can.
+func (s *Service) canManage(u *storage.User, teamName string) bool {
+	if u.IsAdmin {
+		return true
+	}
+	role, err := s.ops.Role(teamName, u.Email)
+	if err != nil {
+		return false
+	}
+	return RoleAllows(role, RoleOwner)
+}
+
 func (s *Service) Create(ctx context.Context, request *teampb.CreateRequest) (*teampb.Empty, error) {
 	u := ctx.Value("user").(*storage.User)
 	if !u.IsAdmin {
@@ -25,11 +38,55 @@ func (s *Service) Create(ctx context.Context, request *teampb.CreateRequest) (*t
 }
 
 func (s *Service) AddUser(ctx context.Context, request *teampb.AddUserRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !s.canManage(u, request.Name) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.AddUser(request.Name, request.User, request.Role); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) RemoveUser(ctx context.Context, request *teampb.RemoveUserRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !s.canManage(u, request.Name) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.RemoveUser(request.Name, request.User); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, request *teampb.DeleteRequest) (*teampb.Empty, error) {
 	u := ctx.Value("user").(*storage.User)
 	if !u.IsAdmin {
 		return nil, auth.ErrPermissionDenied
 	}
-	if err := s.ops.AddUser(request.Name, request.User); err != nil {
+	if err := s.ops.Delete(request.Name, request.Force); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) Update(ctx context.Context, request *teampb.UpdateRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !s.canManage(u, request.Name) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.Update(request.Name, request.Email, request.Url); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) SetRole(ctx context.Context, request *teampb.SetRoleRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !s.canManage(u, request.Name) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.SetRole(request.Name, request.User, request.Role); err != nil {
 		return nil, err
 	}
 	return &teampb.Empty{}, nil
@@ -56,7 +113,11 @@ func (s *Service) List(ctx context.Context, _ *teampb.Empty) (*teampb.ListRespon
 	for _, t := range teams {
 		currentTeam := &teampb.ListResponse_Team{Name: t.Name, Email: t.Email, Url: t.URL}
 		for _, user := range t.Users {
-			currentUser := &teampb.ListResponse_User{Name: user.Name, Email: user.Email}
+			role := t.Roles[user.Email]
+			if role == "" {
+				role = RoleMember
+			}
+			currentUser := &teampb.ListResponse_User{Name: user.Name, Email: user.Email, Role: role}
 			currentTeam.Users = append(currentTeam.Users, currentUser)
 		}
 		resp.Teams = append(resp.Teams, currentTeam)
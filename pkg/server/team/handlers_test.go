@@ -224,3 +224,181 @@ func TestTeamList(t *testing.T) {
 		t.Errorf("expected 2, got %d", len(resp.Teams))
 	}
 }
+
+func TestTeamRemoveUserSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	expectedUserEmail := "gopher@luizalabs.com"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{
+		Name:  expectedName,
+		Users: []storage.User{storage.User{Email: expectedUserEmail}},
+	}
+	fake.(*FakeOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "admin@luizalabs.com", IsAdmin: true})
+
+	req := &teampb.RemoveUserRequest{Name: expectedName, User: expectedUserEmail}
+	if _, err := s.RemoveUser(ctx, req); err != nil {
+		t.Fatal("Got error on make RemoveUser: ", err)
+	}
+
+	if users := fake.(*FakeOperations).Storage[expectedName].Users; len(users) != 0 {
+		t.Errorf("expected 0 users, got %d", len(users))
+	}
+}
+
+func TestTeamRemoveUserErrPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &storage.User{IsAdmin: false})
+	if _, err := s.RemoveUser(ctx, &teampb.RemoveUserRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestTeamDeleteSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{Name: expectedName}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "gopher", IsAdmin: true})
+
+	if _, err := s.Delete(ctx, &teampb.DeleteRequest{Name: expectedName}); err != nil {
+		t.Fatal("Got error on make Delete: ", err)
+	}
+	if _, found := fake.(*FakeOperations).Storage[expectedName]; found {
+		t.Errorf("expected team %s to be deleted", expectedName)
+	}
+}
+
+func TestTeamDeleteTeamHasApps(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{Name: expectedName}
+	fake.(*FakeOperations).Apps[expectedName] = []string{"app1"}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "gopher", IsAdmin: true})
+
+	if _, err := s.Delete(ctx, &teampb.DeleteRequest{Name: expectedName}); err != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+}
+
+func TestTeamDeleteErrPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &storage.User{IsAdmin: false})
+	if _, err := s.Delete(ctx, &teampb.DeleteRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestTeamUpdateSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	expectedEmail := "teresa@luizalabs.com"
+	expectedURL := "http://teresa.io"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{Name: expectedName}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "gopher", IsAdmin: true})
+
+	req := &teampb.UpdateRequest{Name: expectedName, Email: expectedEmail, Url: expectedURL}
+	if _, err := s.Update(ctx, req); err != nil {
+		t.Fatal("Got error on make Update: ", err)
+	}
+
+	team := fake.(*FakeOperations).Storage[expectedName]
+	if team.Email != expectedEmail {
+		t.Errorf("expected %s, got %s", expectedEmail, team.Email)
+	}
+	if team.URL != expectedURL {
+		t.Errorf("expected %s, got %s", expectedURL, team.URL)
+	}
+}
+
+func TestTeamUpdateErrPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &storage.User{IsAdmin: false})
+	if _, err := s.Update(ctx, &teampb.UpdateRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestTeamAddUserByOwner(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	ownerEmail := "owner@luizalabs.com"
+	expectedUserEmail := "gopher@luizalabs.com"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{
+		Name:  expectedName,
+		Users: []storage.User{storage.User{Email: ownerEmail}},
+	}
+	fake.(*FakeOperations).Roles[expectedName] = map[string]string{ownerEmail: RoleOwner}
+	fake.(*FakeOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: ownerEmail})
+
+	req := &teampb.AddUserRequest{Name: expectedName, User: expectedUserEmail, Role: RoleViewer}
+	if _, err := s.AddUser(ctx, req); err != nil {
+		t.Fatal("Got error on make AddUser: ", err)
+	}
+
+	if role, _ := fake.Role(expectedName, expectedUserEmail); role != RoleViewer {
+		t.Errorf("expected %s, got %s", RoleViewer, role)
+	}
+}
+
+func TestTeamManageMembersErrPermissionDenied(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	memberEmail := "member@luizalabs.com"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{
+		Name:  expectedName,
+		Users: []storage.User{storage.User{Email: memberEmail}},
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: memberEmail})
+
+	if _, err := s.AddUser(ctx, &teampb.AddUserRequest{Name: expectedName}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := s.SetRole(ctx, &teampb.SetRoleRequest{Name: expectedName}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := s.RemoveUser(ctx, &teampb.RemoveUserRequest{Name: expectedName}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestTeamSetRoleSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+
+	expectedName := "teresa"
+	expectedUserEmail := "gopher@luizalabs.com"
+	fake.(*FakeOperations).Storage[expectedName] = &storage.Team{
+		Name:  expectedName,
+		Users: []storage.User{storage.User{Email: expectedUserEmail}},
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: "admin@luizalabs.com", IsAdmin: true})
+
+	req := &teampb.SetRoleRequest{Name: expectedName, User: expectedUserEmail, Role: RoleOwner}
+	if _, err := s.SetRole(ctx, req); err != nil {
+		t.Fatal("Got error on make SetRole: ", err)
+	}
+
+	if role, _ := fake.Role(expectedName, expectedUserEmail); role != RoleOwner {
+		t.Errorf("expected %s, got %s", RoleOwner, role)
+	}
+}
@@ -0,0 +1,42 @@
+package team
+
+import "github.com/luizalabs/teresa-api/pkg/server/user"
+
+// SyncTeams syncs the teams of the user with the groups of an identity
+// provider (see user.SyncTeams); teams that don't exist are ignored.
+func SyncTeams(ops Operations, userEmail string, names []string, managed func(team string) bool) error {
+	return user.SyncTeams(&memberships{ops}, userEmail, names, managed)
+}
+
+// memberships are the user.TeamMemberships of the Operations.
+type memberships struct {
+	ops Operations
+}
+
+func (m *memberships) Teams(email string) ([]string, error) {
+	teams, err := m.ops.ListByUser(email)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(teams))
+	for i, t := range teams {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+func (m *memberships) Join(name, email string) error {
+	err := m.ops.AddUser(name, email, RoleMember)
+	if err == ErrNotFound || err == ErrUserAlreadyInTeam {
+		return nil
+	}
+	return err
+}
+
+func (m *memberships) Leave(name, email string) error {
+	err := m.ops.RemoveUser(name, email)
+	if err == ErrNotFound || err == ErrUserNotInTeam {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+func TestSyncTeams(t *testing.T) {
+	fake := NewFakeOperations().(*FakeOperations)
+	email := "gopher@luizalabs.com"
+	fake.UserOps.(*user.FakeOperations).Storage[email] = &storage.User{Email: email}
+	fake.Storage["luizalabs"] = &storage.Team{Name: "luizalabs"}
+	fake.Storage["gophers"] = &storage.Team{Name: "gophers"}
+	fake.Storage["infra"] = &storage.Team{Name: "infra"}
+	fake.Storage["staff"] = &storage.Team{Name: "staff"}
+	for _, name := range []string{"gophers", "infra", "staff"} {
+		if err := fake.AddUser(name, email, RoleOwner); err != nil {
+			t.Fatal("error adding user to team: ", err)
+		}
+	}
+
+	managed := func(team string) bool { return team != "staff" }
+	if err := SyncTeams(fake, email, []string{"luizalabs", "gophers", "unknown"}, managed); err != nil {
+		t.Fatal("error syncing teams: ", err)
+	}
+
+	var testCases = []struct {
+		team         string
+		expectedRole string
+	}{
+		{"luizalabs", RoleMember},
+		{"gophers", RoleOwner},
+		{"staff", RoleOwner},
+	}
+	for _, tc := range testCases {
+		role, err := fake.Role(tc.team, email)
+		if err != nil {
+			t.Fatalf("error getting role in team %s: %v", tc.team, err)
+		}
+		if role != tc.expectedRole {
+			t.Errorf("expected %s in team %s, got %s", tc.expectedRole, tc.team, role)
+		}
+	}
+	if _, err := fake.Role("infra", email); err != ErrUserNotInTeam {
+		t.Errorf("expected ErrUserNotInTeam in team infra, got %v", err)
+	}
+
+	if err := SyncTeams(fake, "invalid@luizalabs.com", []string{"luizalabs"}, managed); err != user.ErrNotFound {
+		t.Errorf("expected user.ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package team
+
+const (
+	// RoleOwner can deploy, change the apps and manage the members of the team
+	RoleOwner = "owner"
+	// RoleMember can create apps, deploy and change their env vars
+	RoleMember = "member"
+	// RoleViewer can only see the info and the logs of the apps
+	RoleViewer = "viewer"
+)
+
+var roleLevels = map[string]int{
+	RoleViewer: 1,
+	RoleMember: 2,
+	RoleOwner:  3,
+}
+
+// IsValidRole reports whether role is one of the team roles.
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RoleAllows reports whether a member with role has the permissions of
+// required; every role has the permissions of the roles below it.
+func RoleAllows(role, required string) bool {
+	return IsValidRole(role) && roleLevels[role] >= roleLevels[required]
+}
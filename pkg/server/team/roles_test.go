@@ -0,0 +1,29 @@
+package team
+
+import "testing"
+
+func TestRoleAllows(t *testing.T) {
+	var testCases = []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{RoleOwner, RoleOwner, true},
+		{RoleOwner, RoleMember, true},
+		{RoleOwner, RoleViewer, true},
+		{RoleMember, RoleOwner, false},
+		{RoleMember, RoleMember, true},
+		{RoleMember, RoleViewer, true},
+		{RoleViewer, RoleOwner, false},
+		{RoleViewer, RoleMember, false},
+		{RoleViewer, RoleViewer, true},
+		{"", RoleViewer, false},
+		{"admin", RoleViewer, false},
+	}
+
+	for _, tc := range testCases {
+		if actual := RoleAllows(tc.role, tc.required); actual != tc.expected {
+			t.Errorf("expected %v for %q allowing %q, got %v", tc.expected, tc.role, tc.required, actual)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package team
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/labels"
 	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
 	"github.com/luizalabs/teresa-api/pkg/server/user"
 	"github.com/pkg/errors"
@@ -12,14 +14,25 @@ import (
 
 type Operations interface {
 	Create(name, email, url string) error
-	AddUser(name, userEmail string) error
+	AddUser(name, userEmail, role string) error
 	List() ([]*storage.Team, error)
 	ListByUser(userEmail string) ([]*storage.Team, error)
+	RemoveUser(name, userEmail string) error
+	Delete(name string, force bool) error
+	Update(name, email, url string) error
+	SetRole(name, userEmail, role string) error
+	Role(name, userEmail string) (string, error)
+}
+
+type K8sOperations interface {
+	NamespaceListByLabel(label, value string) ([]string, error)
+	DeleteNamespace(namespace string) error
 }
 
 type DatabaseOperations struct {
 	DB      *gorm.DB
 	UserOps user.Operations
+	kops    K8sOperations
 }
 
 func (dbt *DatabaseOperations) Create(name, email, url string) error {
@@ -41,7 +54,15 @@ func (dbt *DatabaseOperations) Create(name, email, url string) error {
 	return nil
 }
 
-func (dbt *DatabaseOperations) AddUser(name, userEmail string) error {
+// AddUser adds the user to the team with role, RoleMember if it is empty.
+func (dbt *DatabaseOperations) AddUser(name, userEmail, role string) error {
+	if role == "" {
+		role = RoleMember
+	}
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
 	t, err := dbt.getTeam(name)
 	if err != nil {
 		return err
@@ -51,15 +72,171 @@ func (dbt *DatabaseOperations) AddUser(name, userEmail string) error {
 		return err
 	}
 
-	usersOfTeam := []storage.User{}
-	dbt.DB.Model(t).Association("Users").Find(&usersOfTeam)
-	for _, userOfTeam := range usersOfTeam {
-		if userOfTeam.Email == userEmail {
+	if _, err := dbt.getMembership(t, u); err != ErrUserNotInTeam {
+		if err == nil {
 			return ErrUserAlreadyInTeam
 		}
+		return err
+	}
+
+	tu := &storage.TeamUser{TeamID: t.ID, UserID: u.ID, Role: role}
+	if err := dbt.DB.Create(tu).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("adding user %s to team %s", userEmail, name)),
+		)
+	}
+	return nil
+}
+
+func (dbt *DatabaseOperations) SetRole(name, userEmail, role string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	tu, err := dbt.membership(name, userEmail)
+	if err != nil {
+		return err
+	}
+
+	tu.Role = role
+	if err := dbt.DB.Save(tu).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("setting role of user %s in team %s", userEmail, name)),
+		)
+	}
+	return nil
+}
+
+// Role returns the role of the user in the team or ErrUserNotInTeam.
+func (dbt *DatabaseOperations) Role(name, userEmail string) (string, error) {
+	tu, err := dbt.membership(name, userEmail)
+	if err != nil {
+		return "", err
+	}
+	return tu.Role, nil
+}
+
+func (dbt *DatabaseOperations) membership(name, userEmail string) (*storage.TeamUser, error) {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	u, err := dbt.UserOps.GetUser(userEmail)
+	if err != nil {
+		return nil, err
+	}
+	return dbt.getMembership(t, u)
+}
+
+func (dbt *DatabaseOperations) getMembership(t *storage.Team, u *storage.User) (*storage.TeamUser, error) {
+	tu := new(storage.TeamUser)
+	if dbt.DB.Where(&storage.TeamUser{TeamID: t.ID, UserID: u.ID}).First(tu).RecordNotFound() {
+		return nil, ErrUserNotInTeam
+	}
+	return tu, nil
+}
+
+func (dbt *DatabaseOperations) RemoveUser(name, userEmail string) error {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
+	}
+	u, err := dbt.UserOps.GetUser(userEmail)
+	if err != nil {
+		return err
+	}
+
+	tu, err := dbt.getMembership(t, u)
+	if err != nil {
+		return err
+	}
+	if err := dbt.DB.Where(tu).Delete(storage.TeamUser{}).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing user %s from team %s", userEmail, name)),
+		)
+	}
+	return nil
+}
+
+// Delete deletes the team; a team owning apps is only deleted with force,
+// which deletes its apps too.
+func (dbt *DatabaseOperations) Delete(name string, force bool) error {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
+	}
+
+	apps, err := dbt.kops.NamespaceListByLabel(labels.Team, name)
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("listing apps of team %s", name)),
+		)
+	}
+	if len(apps) > 0 && !force {
+		return teresa_errors.New(
+			ErrTeamHasApps,
+			fmt.Errorf("team %s owns the apps %s", name, strings.Join(apps, ", ")),
+		)
+	}
+	for _, app := range apps {
+		if err := dbt.kops.DeleteNamespace(app); err != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("deleting app %s of team %s", app, name)),
+			)
+		}
+	}
+
+	tx := dbt.DB.Begin()
+	if err := tx.Model(t).Association("Users").Clear().Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing users of team %s", name)),
+		)
+	}
+	if err := tx.Delete(t).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("deleting team %s", name)),
+		)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("deleting team %s", name)),
+		)
+	}
+	return nil
+}
+
+// Update changes the email and the URL of the team, the empty ones are
+// kept.
+func (dbt *DatabaseOperations) Update(name, email, url string) error {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
 	}
 
-	return dbt.DB.Model(t).Association("Users").Append(u).Error
+	if email != "" {
+		t.Email = email
+	}
+	if url != "" {
+		t.URL = url
+	}
+
+	if err := dbt.DB.Save(t).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("updating team %s", name)),
+		)
+	}
+	return nil
 }
 
 func (dbt *DatabaseOperations) List() ([]*storage.Team, error) {
@@ -97,14 +274,45 @@ func (dbt *DatabaseOperations) ListByUser(userEmail string) ([]*storage.Team, er
 	return teams, nil
 }
 
+// teamMember is a user of a team with its role.
+type teamMember struct {
+	storage.User
+	TeamID uint
+	Role   string
+}
+
+// findTeamUsers loads the users of the teams and their roles in a single
+// query.
 func (dbt *DatabaseOperations) findTeamUsers(teams []*storage.Team) error {
-	for _, t := range teams {
-		if err := dbt.DB.Model(t).Association("Users").Find(&t.Users).Error; err != nil {
-			return teresa_errors.New(
-				teresa_errors.ErrInternalServerError,
-				errors.Wrap(err, fmt.Sprintf("associating team %s with its users", t.Name)),
-			)
-		}
+	if len(teams) == 0 {
+		return nil
+	}
+	byID := make(map[uint]*storage.Team)
+	ids := make([]uint, len(teams))
+	for i, t := range teams {
+		byID[t.ID] = t
+		ids[i] = t.ID
+		t.Users = nil
+		t.Roles = make(map[string]string)
+	}
+
+	var members []*teamMember
+	err := dbt.DB.Table("users").
+		Select("users.*, teams_users.team_id, teams_users.role").
+		Joins("join teams_users on teams_users.user_id = users.id").
+		Where("teams_users.team_id in (?)", ids).
+		Order("users.id").
+		Scan(&members).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "finding the users of the teams"),
+		)
+	}
+	for _, m := range members {
+		t := byID[m.TeamID]
+		t.Users = append(t.Users, m.User)
+		t.Roles[m.Email] = m.Role
 	}
 	return nil
 }
@@ -117,7 +325,7 @@ func (dbt *DatabaseOperations) getTeam(name string) (*storage.Team, error) {
 	return t, nil
 }
 
-func NewDatabaseOperations(db *gorm.DB, uOps user.Operations) Operations {
-	db.AutoMigrate(&storage.Team{})
-	return &DatabaseOperations{DB: db, UserOps: uOps}
+func NewDatabaseOperations(db *gorm.DB, uOps user.Operations, kops K8sOperations) Operations {
+	db.AutoMigrate(&storage.Team{}, &storage.TeamUser{})
+	return &DatabaseOperations{DB: db, UserOps: uOps, kops: kops}
 }
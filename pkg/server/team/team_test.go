@@ -1,11 +1,13 @@
 package team
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa-api/models/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
 	"github.com/luizalabs/teresa-api/pkg/server/user"
 )
 
@@ -24,7 +26,7 @@ func TestDatabaseOperationsCreate(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 
 	expectedEmail := "teresa@luizalabs.com"
 	expectedName := "teresa"
@@ -57,7 +59,7 @@ func TestDatabaseOperationsCreateTeamAlreadyExists(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 
 	teamName := "teresa"
 	if err := createFakeTeam(db, teamName, "", ""); err != nil {
@@ -79,7 +81,7 @@ func TestDatabaseOperationsAddUser(t *testing.T) {
 
 	expectedUserEmail := "gopher"
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 	dbt.(*DatabaseOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
 
 	expectedTeam := "teresa"
@@ -87,7 +89,7 @@ func TestDatabaseOperationsAddUser(t *testing.T) {
 		t.Fatal("error on create a team:", err)
 	}
 
-	if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleMember); err != nil {
 		t.Errorf("error trying on add user to a team: %v", err)
 	}
 }
@@ -99,8 +101,8 @@ func TestDatabaseOperationsAddUserTeamNotFound(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
-	if err := dbt.AddUser("teresa", "gopher"); err != ErrNotFound {
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+	if err := dbt.AddUser("teresa", "gopher", RoleMember); err != ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -113,14 +115,14 @@ func TestDatabaseOperationsAddUserUserNotFound(t *testing.T) {
 	db.AutoMigrate(&storage.User{})
 	defer db.Close()
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 
 	expectedTeam := "teresa"
 	if err := dbt.Create(expectedTeam, "", ""); err != nil {
 		t.Fatal("error on create a team:", err)
 	}
 
-	if err := dbt.AddUser(expectedTeam, "gopher"); err != user.ErrNotFound {
+	if err := dbt.AddUser(expectedTeam, "gopher", RoleMember); err != user.ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -135,7 +137,7 @@ func TestDatabaseOperationsAddUserUserAlreadyInTeam(t *testing.T) {
 
 	expectedUserEmail := "gopher"
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 	dbt.(*DatabaseOperations).UserOps.(*user.FakeOperations).Storage[expectedUserEmail] = &storage.User{Email: expectedUserEmail}
 
 	expectedTeam := "teresa"
@@ -144,7 +146,7 @@ func TestDatabaseOperationsAddUserUserAlreadyInTeam(t *testing.T) {
 	}
 
 	for _, expectedErr := range []error{nil, ErrUserAlreadyInTeam} {
-		if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != expectedErr {
+		if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleMember); err != expectedErr {
 			t.Errorf("expected %v, got %v", expectedErr, err)
 		}
 	}
@@ -157,7 +159,7 @@ func TestDatabaseOperationsListWithoutTeams(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
 	teams, err := dbt.List()
 	if err != nil {
 		t.Error("error on list teams:", err)
@@ -182,8 +184,8 @@ func TestDatabaseOperationsList(t *testing.T) {
 	}
 	defer db.Close()
 
-	uOps := user.NewDatabaseOperations(db, auth.NewFake())
-	dbt := NewDatabaseOperations(db, uOps)
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
 	for _, tc := range testData {
 		if err := dbt.Create(tc.teamName, "", ""); err != nil {
 			t.Fatal("error on create team:", err)
@@ -192,7 +194,7 @@ func TestDatabaseOperationsList(t *testing.T) {
 			if err := uOps.Create(email, email, "12345678", false); err != nil {
 				t.Fatal("error on create user", err)
 			}
-			if err := dbt.AddUser(tc.teamName, email); err != nil {
+			if err := dbt.AddUser(tc.teamName, email, RoleMember); err != nil {
 				t.Fatal("error on add user to team: ", err)
 			}
 		}
@@ -229,6 +231,56 @@ func TestDatabaseOperationsList(t *testing.T) {
 	}
 }
 
+func TestDatabaseOperationsListRoles(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
+	roles := map[string]string{
+		"gopher@luizalabs.com": RoleOwner,
+		"k8s@luizalabs.com":    RoleViewer,
+	}
+	for _, name := range []string{"teresa", "gophers"} {
+		if err := dbt.Create(name, "", ""); err != nil {
+			t.Fatal("error on create team:", err)
+		}
+	}
+	for email, role := range roles {
+		if err := uOps.Create(email, email, "12345678", false); err != nil {
+			t.Fatal("error on create user", err)
+		}
+		if err := dbt.AddUser("teresa", email, role); err != nil {
+			t.Fatal("error on add user to team: ", err)
+		}
+	}
+	if err := dbt.AddUser("gophers", "gopher@luizalabs.com", RoleMember); err != nil {
+		t.Fatal("error on add user to team: ", err)
+	}
+
+	teams, err := dbt.List()
+	if err != nil {
+		t.Fatal("error on list teams:", err)
+	}
+	for _, team := range teams {
+		expected := roles
+		if team.Name == "gophers" {
+			expected = map[string]string{"gopher@luizalabs.com": RoleMember}
+		}
+		if len(team.Users) != len(expected) {
+			t.Errorf("expected %d users in team %s, got %d", len(expected), team.Name, len(team.Users))
+		}
+		for email, role := range expected {
+			if team.Roles[email] != role {
+				t.Errorf("expected %s for %s in team %s, got %s", role, email, team.Name, team.Roles[email])
+			}
+		}
+	}
+}
+
 func TestDatabaseOperationsListByUser(t *testing.T) {
 	expectedUserEmail := "gopher@luizalabs.com"
 
@@ -248,8 +300,8 @@ func TestDatabaseOperationsListByUser(t *testing.T) {
 	}
 	defer db.Close()
 
-	uOps := user.NewDatabaseOperations(db, auth.NewFake())
-	dbt := NewDatabaseOperations(db, uOps)
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
 	for _, tc := range testData {
 		if err := dbt.Create(tc.teamName, "", ""); err != nil {
 			t.Fatal("error on create team:", err)
@@ -259,7 +311,7 @@ func TestDatabaseOperationsListByUser(t *testing.T) {
 			if err != nil && err != user.ErrUserAlreadyExists {
 				t.Fatal("error on create user", err)
 			}
-			if err := dbt.AddUser(tc.teamName, email); err != nil {
+			if err := dbt.AddUser(tc.teamName, email, RoleMember); err != nil {
 				t.Fatal("error on add user to team: ", err)
 			}
 		}
@@ -292,8 +344,8 @@ func TestDatabaseOperationsListByUserWithoutTeams(t *testing.T) {
 
 	expectedUserEmail := "gopher@luizalabs.com"
 
-	uOps := user.NewDatabaseOperations(db, auth.NewFake())
-	dbt := NewDatabaseOperations(db, uOps)
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
 
 	if err := uOps.Create("", expectedUserEmail, "12345678", false); err != nil {
 		t.Fatal("error on creating user:", err)
@@ -307,3 +359,311 @@ func TestDatabaseOperationsListByUserWithoutTeams(t *testing.T) {
 		t.Errorf("expected 0, got %d", len(teams))
 	}
 }
+
+type fakeK8sOperations struct {
+	namespaces []string
+	deleted    []string
+}
+
+func (f *fakeK8sOperations) NamespaceListByLabel(label, value string) ([]string, error) {
+	return f.namespaces, nil
+}
+
+func (f *fakeK8sOperations) DeleteNamespace(namespace string) error {
+	f.deleted = append(f.deleted, namespace)
+	return nil
+}
+
+func TestDatabaseOperationsRemoveUser(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
+
+	expectedUserEmail := "gopher@luizalabs.com"
+	if err := uOps.Create(expectedUserEmail, expectedUserEmail, "12345678", false); err != nil {
+		t.Fatal("error on create user", err)
+	}
+
+	expectedTeam := "teresa"
+	if err := dbt.Create(expectedTeam, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+	if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleMember); err != nil {
+		t.Fatal("error on add user to a team:", err)
+	}
+
+	for _, expectedErr := range []error{nil, ErrUserNotInTeam} {
+		if err := dbt.RemoveUser(expectedTeam, expectedUserEmail); err != expectedErr {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+	}
+
+	teams, err := dbt.ListByUser(expectedUserEmail)
+	if err != nil {
+		t.Fatal("error on list teams of user:", err)
+	}
+	if len(teams) != 0 {
+		t.Errorf("expected 0 teams, got %d", len(teams))
+	}
+}
+
+func TestDatabaseOperationsRemoveUserTeamNotFound(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+	if err := dbt.RemoveUser("teresa", "gopher"); err != ErrNotFound {
+		t.Errorf("expected error ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsDelete(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	db.AutoMigrate(&storage.User{})
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+
+	teamName := "teresa"
+	if err := createFakeTeam(db, teamName, "", ""); err != nil {
+		t.Fatal("error on create a fake team:", err)
+	}
+
+	if err := dbt.Delete(teamName, false); err != nil {
+		t.Fatal("error trying to delete a team:", err)
+	}
+	if _, err := dbt.(*DatabaseOperations).getTeam(teamName); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsDeleteTeamHasApps(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	db.AutoMigrate(&storage.User{})
+	defer db.Close()
+
+	kops := &fakeK8sOperations{namespaces: []string{"app1"}}
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), kops)
+
+	teamName := "teresa"
+	if err := createFakeTeam(db, teamName, "", ""); err != nil {
+		t.Fatal("error on create a fake team:", err)
+	}
+
+	if err := dbt.Delete(teamName, false); teresa_errors.Get(err) != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+	if len(kops.deleted) != 0 {
+		t.Errorf("expected no app deleted, got %v", kops.deleted)
+	}
+	if err := dbt.Delete(teamName, true); err != nil {
+		t.Errorf("error trying to force the deletion of a team: %v", err)
+	}
+	if !reflect.DeepEqual(kops.deleted, kops.namespaces) {
+		t.Errorf("expected the apps %v to be deleted, got %v", kops.namespaces, kops.deleted)
+	}
+}
+
+func TestDatabaseOperationsDeleteTeamNotFound(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+	if err := dbt.Delete("teresa", true); err != ErrNotFound {
+		t.Errorf("expected error ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsUpdate(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+
+	teamName := "teresa"
+	if err := createFakeTeam(db, teamName, "old@luizalabs.com", "http://old.teresa.io"); err != nil {
+		t.Fatal("error on create a fake team:", err)
+	}
+
+	expectedEmail := "teresa@luizalabs.com"
+	expectedURL := "http://teresa.io"
+	if err := dbt.Update(teamName, expectedEmail, expectedURL); err != nil {
+		t.Fatal("error trying to update a team:", err)
+	}
+
+	team, err := dbt.(*DatabaseOperations).getTeam(teamName)
+	if err != nil {
+		t.Fatal("error on get team:", err)
+	}
+	if team.Email != expectedEmail {
+		t.Errorf("expected %s, got %s", expectedEmail, team.Email)
+	}
+	if team.URL != expectedURL {
+		t.Errorf("expected %s, got %s", expectedURL, team.URL)
+	}
+}
+
+func TestDatabaseOperationsUpdatePartial(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+
+	teamName := "teresa"
+	expectedEmail := "teresa@luizalabs.com"
+	if err := createFakeTeam(db, teamName, expectedEmail, "http://old.teresa.io"); err != nil {
+		t.Fatal("error on create a fake team:", err)
+	}
+
+	expectedURL := "http://teresa.io"
+	if err := dbt.Update(teamName, "", expectedURL); err != nil {
+		t.Fatal("error trying to update a team:", err)
+	}
+
+	team, err := dbt.(*DatabaseOperations).getTeam(teamName)
+	if err != nil {
+		t.Fatal("error on get team:", err)
+	}
+	if team.Email != expectedEmail {
+		t.Errorf("expected %s, got %s", expectedEmail, team.Email)
+	}
+	if team.URL != expectedURL {
+		t.Errorf("expected %s, got %s", expectedURL, team.URL)
+	}
+}
+
+func TestDatabaseOperationsUpdateTeamNotFound(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+	if err := dbt.Update("teresa", "", ""); err != ErrNotFound {
+		t.Errorf("expected error ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsRole(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
+
+	expectedTeam := "teresa"
+	if err := dbt.Create(expectedTeam, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+
+	var testCases = []struct {
+		email string
+		role  string
+	}{
+		{"owner@luizalabs.com", RoleOwner},
+		{"member@luizalabs.com", ""},
+		{"viewer@luizalabs.com", RoleViewer},
+	}
+	for _, tc := range testCases {
+		if err := uOps.Create(tc.email, tc.email, "12345678", false); err != nil {
+			t.Fatal("error on create user", err)
+		}
+		if err := dbt.AddUser(expectedTeam, tc.email, tc.role); err != nil {
+			t.Fatal("error on add user to a team:", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		expectedRole := tc.role
+		if expectedRole == "" {
+			expectedRole = RoleMember
+		}
+		role, err := dbt.Role(expectedTeam, tc.email)
+		if err != nil {
+			t.Fatal("error on get role:", err)
+		}
+		if role != expectedRole {
+			t.Errorf("expected %s, got %s", expectedRole, role)
+		}
+	}
+
+	if err := dbt.SetRole(expectedTeam, "viewer@luizalabs.com", RoleOwner); err != nil {
+		t.Fatal("error on set role:", err)
+	}
+	if role, _ := dbt.Role(expectedTeam, "viewer@luizalabs.com"); role != RoleOwner {
+		t.Errorf("expected %s, got %s", RoleOwner, role)
+	}
+	if role, _ := dbt.Role(expectedTeam, "member@luizalabs.com"); role != RoleMember {
+		t.Errorf("expected %s, got %s", RoleMember, role)
+	}
+}
+
+func TestDatabaseOperationsRoleUserNotInTeam(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	uOps := user.NewDatabaseOperations(db, auth.NewFake(), nil)
+	dbt := NewDatabaseOperations(db, uOps, &fakeK8sOperations{})
+
+	expectedTeam := "teresa"
+	expectedUserEmail := "gopher@luizalabs.com"
+	if err := dbt.Create(expectedTeam, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+	if err := uOps.Create(expectedUserEmail, expectedUserEmail, "12345678", false); err != nil {
+		t.Fatal("error on create user", err)
+	}
+
+	if _, err := dbt.Role(expectedTeam, expectedUserEmail); err != ErrUserNotInTeam {
+		t.Errorf("expected ErrUserNotInTeam, got %v", err)
+	}
+	if err := dbt.SetRole(expectedTeam, expectedUserEmail, RoleViewer); err != ErrUserNotInTeam {
+		t.Errorf("expected ErrUserNotInTeam, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsInvalidRole(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations(), &fakeK8sOperations{})
+	if err := dbt.AddUser("teresa", "gopher", "admin"); err != ErrInvalidRole {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+	if err := dbt.SetRole("teresa", "gopher", "admin"); err != ErrInvalidRole {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package token
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound      = status.Errorf(codes.NotFound, "Token not found")
+	ErrInvalidAction = status.Errorf(codes.InvalidArgument, "Invalid token action, use deploy or read")
+	ErrInvalidScope  = status.Errorf(codes.InvalidArgument, "A token needs at least one app or team and one action")
+)
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+type FakeOperations struct {
+	mutex  *sync.RWMutex
+	nextID uint
+	// Storage holds the tokens by their value
+	Storage map[string]*storage.APIToken
+
+	UserOps user.Operations
+}
+
+func (f *FakeOperations) Create(user *storage.User, t *storage.APIToken) (string, error) {
+	if err := validateScope(t); err != nil {
+		return "", err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.nextID++
+	token := fmt.Sprintf("%sfake%d", Prefix, f.nextID)
+	t.ID = f.nextID
+	t.User = user.Email
+	f.Storage[token] = t
+	return token, nil
+}
+
+func (f *FakeOperations) List(user *storage.User) ([]*storage.APIToken, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	var tokens []*storage.APIToken
+	for _, t := range f.Storage {
+		if user.IsAdmin || t.User == user.Email {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (f *FakeOperations) Revoke(user *storage.User, id uint) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for k, t := range f.Storage {
+		if t.ID != id {
+			continue
+		}
+		if !user.IsAdmin && t.User != user.Email {
+			return auth.ErrPermissionDenied
+		}
+		delete(f.Storage, k)
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (f *FakeOperations) Authenticate(token string) (*storage.User, *Scope, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	t, found := f.Storage[token]
+	if !found {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+	u, err := f.UserOps.GetUser(t.User)
+	if err != nil {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+	return u, newScope(t), nil
+}
+
+func (f *FakeOperations) Check(scope *Scope, fullMethod string, req interface{}) error {
+	app, _ := audit.Target(fullMethod, req)
+	if !scope.allowsMethod(audit.MethodName(fullMethod)) || !contains(scope.Apps, app) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func NewFakeOperations() Operations {
+	return &FakeOperations{
+		mutex:   &sync.RWMutex{},
+		Storage: make(map[string]*storage.APIToken),
+		UserOps: user.NewFakeOperations(),
+	}
+}
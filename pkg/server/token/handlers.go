@@ -0,0 +1,53 @@
+package token
+
+import (
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	tokenpb "github.com/luizalabs/teresa-api/pkg/protobuf/token"
+	"google.golang.org/grpc"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) Create(ctx context.Context, request *tokenpb.CreateRequest) (*tokenpb.CreateResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	t := &storage.APIToken{
+		Name:    request.Name,
+		Apps:    joinList(request.Apps),
+		Teams:   joinList(request.Teams),
+		Actions: joinList(request.Actions),
+	}
+	token, err := s.ops.Create(u, t)
+	if err != nil {
+		return nil, err
+	}
+	return &tokenpb.CreateResponse{Id: uint64(t.ID), Token: token}, nil
+}
+
+func (s *Service) List(ctx context.Context, _ *tokenpb.Empty) (*tokenpb.ListResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	tokens, err := s.ops.List(u)
+	if err != nil {
+		return nil, err
+	}
+	return newListResponse(tokens), nil
+}
+
+func (s *Service) Revoke(ctx context.Context, request *tokenpb.RevokeRequest) (*tokenpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if err := s.ops.Revoke(u, uint(request.Id)); err != nil {
+		return nil, err
+	}
+	return &tokenpb.Empty{}, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	tokenpb.RegisterTokenServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
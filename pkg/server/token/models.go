@@ -0,0 +1,50 @@
+package token
+
+import (
+	"strings"
+	"time"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	tokenpb "github.com/luizalabs/teresa-api/pkg/protobuf/token"
+)
+
+func joinList(items []string) string {
+	return strings.Join(items, ",")
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func newListResponse(tokens []*storage.APIToken) *tokenpb.ListResponse {
+	resp := &tokenpb.ListResponse{}
+	for _, t := range tokens {
+		item := &tokenpb.ListResponse_Token{
+			Id:        uint64(t.ID),
+			Name:      t.Name,
+			User:      t.User,
+			Prefix:    t.Prefix,
+			Apps:      splitList(t.Apps),
+			Teams:     splitList(t.Teams),
+			Actions:   splitList(t.Actions),
+			CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		}
+		if t.LastUsedAt != nil {
+			item.LastUsedAt = t.LastUsedAt.Format(time.RFC3339)
+		}
+		resp.Tokens = append(resp.Tokens, item)
+	}
+	return resp
+}
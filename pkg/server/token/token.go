@@ -0,0 +1,229 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	deploypb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/app"
+	"github.com/luizalabs/teresa-api/pkg/server/audit"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Prefix tells the API tokens apart from the login JWTs
+	Prefix     = "trs_"
+	tokenSize  = 32
+	prefixSize = len(Prefix) + 8
+
+	ActionDeploy = "deploy"
+	ActionRead   = "read"
+)
+
+// actionMethods are the rpcs each action allows, the tokens can't call
+// any other rpc.
+var actionMethods = map[string][]string{
+	ActionDeploy: {"Deploy.Make", "Deploy.Promote"},
+	ActionRead:   {"App.Info", "App.Logs", "App.Events", "App.Metrics"},
+}
+
+type Operations interface {
+	Create(user *storage.User, t *storage.APIToken) (string, error)
+	List(user *storage.User) ([]*storage.APIToken, error)
+	Revoke(user *storage.User, id uint) error
+	Authenticate(token string) (*storage.User, *Scope, error)
+	Check(scope *Scope, fullMethod string, req interface{}) error
+}
+
+type K8sOperations interface {
+	NamespaceLabel(namespace, label string) (string, error)
+}
+
+// Scope holds the apps, teams and actions an API token is limited to.
+type Scope struct {
+	Apps    []string
+	Teams   []string
+	Actions []string
+}
+
+func (s *Scope) allowsMethod(method string) bool {
+	for _, action := range s.Actions {
+		for _, m := range actionMethods[action] {
+			if m == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newScope(t *storage.APIToken) *Scope {
+	return &Scope{
+		Apps:    splitList(t.Apps),
+		Teams:   splitList(t.Teams),
+		Actions: splitList(t.Actions),
+	}
+}
+
+type DatabaseOperations struct {
+	DB      *gorm.DB
+	UserOps user.Operations
+	kops    K8sOperations
+}
+
+// IsAPIToken reports whether token is an API token and not a login JWT.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+// Create saves the hash of a new token of the user with the scope of t,
+// returning the token; it is not stored and can't be shown again.
+func (ops *DatabaseOperations) Create(user *storage.User, t *storage.APIToken) (string, error) {
+	if err := validateScope(t); err != nil {
+		return "", err
+	}
+
+	token, err := genToken()
+	if err != nil {
+		return "", teresa_errors.NewInternalServerError(err)
+	}
+	t.User = user.Email
+	t.Hash = hash(token)
+	t.Prefix = token[:prefixSize]
+
+	if err := ops.DB.Create(t).Error; err != nil {
+		return "", teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("saving token of user %s", user.Email)),
+		)
+	}
+	return token, nil
+}
+
+// List returns the tokens of the user, or of every user for admins.
+func (ops *DatabaseOperations) List(user *storage.User) ([]*storage.APIToken, error) {
+	q := ops.DB
+	if !user.IsAdmin {
+		q = q.Where(&storage.APIToken{User: user.Email})
+	}
+
+	var tokens []*storage.APIToken
+	if err := q.Order("id").Find(&tokens).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "finding tokens"),
+		)
+	}
+	return tokens, nil
+}
+
+func (ops *DatabaseOperations) Revoke(user *storage.User, id uint) error {
+	t := new(storage.APIToken)
+	if ops.DB.First(t, id).RecordNotFound() {
+		return ErrNotFound
+	}
+	if !user.IsAdmin && t.User != user.Email {
+		return auth.ErrPermissionDenied
+	}
+
+	if err := ops.DB.Delete(t).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("deleting token %d", id)),
+		)
+	}
+	return nil
+}
+
+// Authenticate returns the user of the token and its scope.
+func (ops *DatabaseOperations) Authenticate(token string) (*storage.User, *Scope, error) {
+	t := new(storage.APIToken)
+	if ops.DB.Where(&storage.APIToken{Hash: hash(token)}).First(t).RecordNotFound() {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+
+	u, err := ops.UserOps.GetUser(t.User)
+	if err != nil {
+		return nil, nil, auth.ErrPermissionDenied
+	}
+
+	now := time.Now()
+	ops.DB.Model(t).UpdateColumn("last_used_at", &now)
+	return u, newScope(t), nil
+}
+
+// Check tells if a call of the rpc with the request req is in the scope:
+// the rpc must be allowed by one of the actions and the target app must be
+// one of the apps or belong to one of the teams.
+func (ops *DatabaseOperations) Check(scope *Scope, fullMethod string, req interface{}) error {
+	method := audit.MethodName(fullMethod)
+	if !scope.allowsMethod(method) {
+		return auth.ErrPermissionDenied
+	}
+
+	app, _ := audit.Target(fullMethod, req)
+	if !ops.appInScope(scope, app) {
+		return auth.ErrPermissionDenied
+	}
+	if r, ok := req.(*deploypb.PromoteRequest); ok && !ops.appInScope(scope, r.FromApp) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (ops *DatabaseOperations) appInScope(scope *Scope, appName string) bool {
+	if appName == "" {
+		return false
+	}
+	if contains(scope.Apps, appName) {
+		return true
+	}
+	if len(scope.Teams) == 0 {
+		return false
+	}
+	teamName, err := ops.kops.NamespaceLabel(appName, app.TeresaTeamLabel)
+	if err != nil {
+		return false
+	}
+	return contains(scope.Teams, teamName)
+}
+
+func validateScope(t *storage.APIToken) error {
+	actions := splitList(t.Actions)
+	if len(actions) == 0 || t.Apps == "" && t.Teams == "" {
+		return ErrInvalidScope
+	}
+	for _, action := range actions {
+		if _, ok := actionMethods[action]; !ok {
+			return ErrInvalidAction
+		}
+	}
+	return nil
+}
+
+func genToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + hex.EncodeToString(b), nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewDatabaseOperations(db *gorm.DB, uOps user.Operations, kops K8sOperations) Operations {
+	db.AutoMigrate(&storage.APIToken{})
+	return &DatabaseOperations{DB: db, UserOps: uOps, kops: kops}
+}
@@ -0,0 +1,180 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	apppb "github.com/luizalabs/teresa-api/pkg/protobuf/app"
+	deploypb "github.com/luizalabs/teresa-api/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/user"
+)
+
+type fakeK8sOperations struct{}
+
+func (f *fakeK8sOperations) NamespaceLabel(namespace, label string) (string, error) {
+	switch namespace {
+	case "teresa", "other":
+		return "luizalabs", nil
+	case "gopher":
+		return "gophers", nil
+	}
+	return "", errors.New("not found")
+}
+
+func newTestOperations(t *testing.T) (*DatabaseOperations, func()) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).Storage["gopher@luizalabs.com"] = &storage.User{Email: "gopher@luizalabs.com"}
+
+	ops := NewDatabaseOperations(db, uOps, &fakeK8sOperations{}).(*DatabaseOperations)
+	return ops, func() { db.Close() }
+}
+
+func TestDatabaseOperationsCreateAndAuthenticate(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	u := &storage.User{Email: "gopher@luizalabs.com"}
+	tk := &storage.APIToken{Name: "ci", Apps: "teresa", Actions: ActionDeploy}
+	token, err := ops.Create(u, tk)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	if !IsAPIToken(token) {
+		t.Errorf("expected a token with the %s prefix, got %s", Prefix, token)
+	}
+	if !strings.HasPrefix(token, tk.Prefix) {
+		t.Errorf("expected %s to start with %s", token, tk.Prefix)
+	}
+	if tk.Hash == token || strings.Contains(tk.Hash, token) {
+		t.Error("expected the token to be stored hashed")
+	}
+
+	actual, scope, err := ops.Authenticate(token)
+	if err != nil {
+		t.Fatal("error authenticating token: ", err)
+	}
+	if actual.Email != u.Email {
+		t.Errorf("expected %s, got %s", u.Email, actual.Email)
+	}
+	if len(scope.Apps) != 1 || scope.Apps[0] != "teresa" {
+		t.Errorf("expected the teresa app in the scope, got %v", scope.Apps)
+	}
+
+	if _, _, err := ops.Authenticate(Prefix + "invalid"); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsCreateErrors(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	var testCases = []struct {
+		token       *storage.APIToken
+		expectedErr error
+	}{
+		{&storage.APIToken{Actions: ActionDeploy}, ErrInvalidScope},
+		{&storage.APIToken{Apps: "teresa"}, ErrInvalidScope},
+		{&storage.APIToken{Teams: "luizalabs", Actions: "deploy,admin"}, ErrInvalidAction},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ops.Create(&storage.User{}, tc.token); err != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestDatabaseOperationsListAndRevoke(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	owner := &storage.User{Email: "gopher@luizalabs.com"}
+	other := &storage.User{Email: "other@luizalabs.com"}
+	token, err := ops.Create(owner, &storage.APIToken{Apps: "teresa", Actions: ActionRead})
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	if _, err := ops.Create(other, &storage.APIToken{Apps: "other", Actions: ActionRead}); err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	tokens, err := ops.List(owner)
+	if err != nil {
+		t.Fatal("error listing tokens: ", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(tokens))
+	}
+	if all, _ := ops.List(&storage.User{IsAdmin: true}); len(all) != 2 {
+		t.Errorf("expected 2 tokens for admins, got %d", len(all))
+	}
+
+	id := tokens[0].ID
+	if err := ops.Revoke(other, id); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := ops.Revoke(owner, id); err != nil {
+		t.Fatal("error revoking token: ", err)
+	}
+	if err := ops.Revoke(owner, id); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, _, err := ops.Authenticate(token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a revoked token, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsCheck(t *testing.T) {
+	ops, closeDB := newTestOperations(t)
+	defer closeDB()
+
+	deployApp := func(app string) interface{} {
+		return &deploypb.DeployRequest{
+			Value: &deploypb.DeployRequest_Info_{Info: &deploypb.DeployRequest_Info{App: app}},
+		}
+	}
+
+	var testCases = []struct {
+		scope      *Scope
+		fullMethod string
+		req        interface{}
+		expected   error
+	}{
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}}, "/deploy.Deploy/Make", deployApp("teresa"), nil},
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}}, "/deploy.Deploy/Make", deployApp("gopher"), auth.ErrPermissionDenied},
+		{&Scope{Teams: []string{"luizalabs"}, Actions: []string{ActionDeploy}}, "/deploy.Deploy/Make", deployApp("teresa"), nil},
+		{&Scope{Teams: []string{"luizalabs"}, Actions: []string{ActionDeploy}}, "/deploy.Deploy/Make", deployApp("gopher"), auth.ErrPermissionDenied},
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}}, "/app.App/Logs", &apppb.LogsRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionRead}}, "/app.App/Logs", &apppb.LogsRequest{Name: "teresa"}, nil},
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionRead}}, "/app.App/SetEnv", &apppb.SetEnvRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+		{&Scope{Apps: []string{"teresa"}, Actions: []string{ActionRead, ActionDeploy}}, "/team.Team/List", nil, auth.ErrPermissionDenied},
+		{
+			&Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}},
+			"/deploy.Deploy/Promote",
+			&deploypb.PromoteRequest{FromApp: "gopher", ToApp: "teresa"},
+			auth.ErrPermissionDenied,
+		},
+		{
+			&Scope{Teams: []string{"luizalabs"}, Actions: []string{ActionDeploy}},
+			"/deploy.Deploy/Promote",
+			&deploypb.PromoteRequest{FromApp: "other", ToApp: "teresa"},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := ops.Check(tc.scope, tc.fullMethod, tc.req); err != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.fullMethod, err)
+		}
+	}
+}
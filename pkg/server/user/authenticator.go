@@ -0,0 +1,69 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+// Identity is a user authenticated by an Authenticator and the teams it is
+// a member of in the backend, if it has teams; the user leaves the
+// ManagedTeams it isn't a member of in the backend anymore.
+type Identity struct {
+	Email        string
+	Teams        []string
+	ManagedTeams []string
+}
+
+// Authenticator checks the password of a user in a backend, like the users
+// table or a LDAP directory, returning auth.ErrPermissionDenied for unknown
+// users and wrong passwords.
+type Authenticator interface {
+	Authenticate(email, password string) (*Identity, error)
+}
+
+type chain []Authenticator
+
+// Authenticate tries the authenticators in order, returning the identity of
+// the first that accepts the password.
+func (c chain) Authenticate(email, password string) (*Identity, error) {
+	err := auth.ErrPermissionDenied
+	for _, a := range c {
+		var id *Identity
+		if id, err = a.Authenticate(email, password); err == nil {
+			return id, nil
+		}
+	}
+	return nil, err
+}
+
+// NewChain returns an Authenticator trying each of the authenticators.
+func NewChain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+// DatabaseAuthenticator checks the bcrypt hashed passwords of the users
+// table.
+type DatabaseAuthenticator struct {
+	DB *gorm.DB
+}
+
+func (d *DatabaseAuthenticator) Authenticate(email, password string) (*Identity, error) {
+	u := new(storage.User)
+	if d.DB.Where(&storage.User{Email: email}).First(u).RecordNotFound() {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			errors.Wrap(err, fmt.Sprintf("Authentication failed for user %s", email)),
+		)
+	}
+	return &Identity{Email: email}, nil
+}
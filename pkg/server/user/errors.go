@@ -6,8 +6,16 @@ import (
 )
 
 var (
-	ErrNotFound          = status.Errorf(codes.NotFound, "User not found")
-	ErrUserAlreadyExists = status.Errorf(codes.AlreadyExists, "User already exists")
-	ErrInvalidPassword   = status.Errorf(codes.InvalidArgument, "Invalid password")
-	ErrInvalidEmail      = status.Errorf(codes.InvalidArgument, "Invalid e-mail")
+	ErrNotFound           = status.Errorf(codes.NotFound, "User not found")
+	ErrUserAlreadyExists  = status.Errorf(codes.AlreadyExists, "User already exists")
+	ErrInvalidPassword    = status.Errorf(codes.InvalidArgument, "Invalid password")
+	ErrInvalidEmail       = status.Errorf(codes.InvalidArgument, "Invalid e-mail")
+	ErrWeakPassword       = status.Errorf(codes.InvalidArgument, "Password needs more kinds of characters (lowercase and uppercase letters, digits and symbols)")
+	ErrSamePassword       = status.Errorf(codes.InvalidArgument, "The new password must be different from the current one")
+	ErrLocked             = status.Errorf(codes.ResourceExhausted, "Too many failed logins, try again later")
+	ErrTOTPCodeRequired   = status.Errorf(codes.Unauthenticated, "Two-factor authentication code required")
+	ErrInvalidTOTPCode    = status.Errorf(codes.InvalidArgument, "Invalid two-factor authentication code")
+	ErrTOTPNotEnrolled    = status.Errorf(codes.FailedPrecondition, "Two-factor authentication not enrolled")
+	ErrTOTPAlreadyEnabled = status.Errorf(codes.FailedPrecondition, "Two-factor authentication already enabled")
+	ErrTOTPRequired       = status.Errorf(codes.FailedPrecondition, "Two-factor authentication is required for admins, enable it with teresa 2fa enable")
 )
@@ -10,16 +10,94 @@ import (
 type FakeOperations struct {
 	mutex   *sync.RWMutex
 	Storage map[string]*storage.User
+	Revoked map[string]bool
+	// TOTPRequiredForAdmins is returned by TOTPRequired for the admins
+	// without two-factor authentication
+	TOTPRequiredForAdmins bool
 }
 
-func (f *FakeOperations) Login(email, password string) (string, error) {
+func (f *FakeOperations) Login(email, password, code, addr string) (*Tokens, error) {
 	f.mutex.RLock()
 	defer f.mutex.RUnlock()
 
-	if user, ok := f.Storage[email]; !ok || user.Password != password {
-		return "", auth.ErrPermissionDenied
+	user, ok := f.Storage[email]
+	if !ok || user.Password != password {
+		return nil, auth.ErrPermissionDenied
 	}
-	return "good token", nil
+	if user.TOTPEnabled && code == "" {
+		return nil, ErrTOTPCodeRequired
+	}
+	if user.TOTPEnabled && code != "123456" {
+		return nil, auth.ErrPermissionDenied
+	}
+	return &Tokens{Token: "good token", RefreshToken: "good refresh token"}, nil
+}
+
+func (f *FakeOperations) Refresh(refreshToken string) (*Tokens, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if refreshToken != "good refresh token" || f.Revoked[refreshToken] {
+		return nil, auth.ErrPermissionDenied
+	}
+	return &Tokens{Token: "good token", RefreshToken: "good refresh token"}, nil
+}
+
+func (f *FakeOperations) IssueTokens(email, code string) (*Tokens, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	user, found := f.Storage[email]
+	if !found {
+		return nil, ErrNotFound
+	}
+	if user.TOTPEnabled && code == "" {
+		return nil, ErrTOTPCodeRequired
+	}
+	if user.TOTPEnabled && code != "123456" {
+		return nil, auth.ErrPermissionDenied
+	}
+	return &Tokens{Token: "good token", RefreshToken: "good refresh token"}, nil
+}
+
+func (f *FakeOperations) Logout(email, token, refreshToken string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[email]; !found {
+		return auth.ErrPermissionDenied
+	}
+	f.Revoked[token] = true
+	if refreshToken != "" {
+		f.Revoked[refreshToken] = true
+	}
+	return nil
+}
+
+func (f *FakeOperations) RevokeSessions(email string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	user, found := f.Storage[email]
+	if !found {
+		return ErrNotFound
+	}
+	user.TokenVersion++
+	return nil
+}
+
+func (f *FakeOperations) Authenticate(claims *auth.Claims) (*storage.User, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	user, found := f.Storage[claims.Email]
+	if !found {
+		return nil, ErrNotFound
+	}
+	if user.TokenVersion != claims.Version || f.Revoked[claims.ID] {
+		return nil, auth.ErrPermissionDenied
+	}
+	return &storage.User{Email: user.Email, Password: user.Password}, nil
 }
 
 func (f *FakeOperations) GetUser(email string) (*storage.User, error) {
@@ -71,8 +149,85 @@ func (f *FakeOperations) Create(name, email, pass string, admin bool) error {
 	return nil
 }
 
+func (f *FakeOperations) Provision(email string) (*storage.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	user, found := f.Storage[email]
+	if !found {
+		user = &storage.User{Name: email, Email: email}
+		f.Storage[email] = user
+	}
+	return &storage.User{Email: user.Email, Password: user.Password}, nil
+}
+
+func (f *FakeOperations) Unlock(email string) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[email]; !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (f *FakeOperations) EnrollTOTP(email string) (*TOTPEnrollment, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[email]; !found {
+		return nil, ErrNotFound
+	}
+	return &TOTPEnrollment{Secret: "secret", ProvisioningURI: "otpauth://totp/Teresa:" + email}, nil
+}
+
+func (f *FakeOperations) EnableTOTP(email, code string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	user, found := f.Storage[email]
+	if !found {
+		return nil, ErrNotFound
+	}
+	if code != "123456" {
+		return nil, ErrInvalidTOTPCode
+	}
+	user.TOTPEnabled = true
+	return []string{"recovery-code"}, nil
+}
+
+func (f *FakeOperations) DisableTOTP(email string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	user, found := f.Storage[email]
+	if !found {
+		return ErrNotFound
+	}
+	user.TOTPEnabled = false
+	return nil
+}
+
+func (f *FakeOperations) VerifyTOTP(email, code string) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[email]; !found {
+		return ErrNotFound
+	}
+	if code != "123456" {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (f *FakeOperations) TOTPRequired(u *storage.User) bool {
+	return f.TOTPRequiredForAdmins && u.IsAdmin && !u.TOTPEnabled
+}
+
 func NewFakeOperations() Operations {
 	return &FakeOperations{
 		mutex:   &sync.RWMutex{},
-		Storage: make(map[string]*storage.User)}
+		Storage: make(map[string]*storage.User),
+		Revoked: make(map[string]bool)}
 }
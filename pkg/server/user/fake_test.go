@@ -17,20 +17,20 @@ func TestFakeOperationsLogin(t *testing.T) {
 		Email:    expectedEmail,
 	}
 
-	token, err := fake.Login(expectedEmail, expectedPassword)
+	tokens, err := fake.Login(expectedEmail, expectedPassword, "", "")
 	if err != nil {
 		t.Fatal("Error on perform Login in FakeOperations: ", err)
 	}
 	expectedToken := "good token"
-	if token != expectedToken {
-		t.Errorf("expected %s, got %s", expectedToken, token)
+	if tokens.Token != expectedToken {
+		t.Errorf("expected %s, got %s", expectedToken, tokens.Token)
 	}
 }
 
 func TestFakeOperationsBadLogin(t *testing.T) {
 	fake := NewFakeOperations()
 
-	if _, err := fake.Login("invalid@luizalabs.com", "foo"); err != auth.ErrPermissionDenied {
+	if _, err := fake.Login("invalid@luizalabs.com", "foo", "", ""); err != auth.ErrPermissionDenied {
 		t.Errorf("expected ErrPermissionDenied, got %s", err)
 	}
 }
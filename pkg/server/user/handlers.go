@@ -1,24 +1,85 @@
 package user
 
 import (
+	"net"
+
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"github.com/luizalabs/teresa-api/models/storage"
 	userpb "github.com/luizalabs/teresa-api/pkg/protobuf/user"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
 )
 
 type Service struct {
-	ops Operations
+	ops            Operations
+	trustedProxies []*net.IPNet
 }
 
 func (s *Service) Login(ctx context.Context, request *userpb.LoginRequest) (*userpb.LoginResponse, error) {
-	token, err := s.ops.Login(request.Email, request.Password)
+	tokens, err := s.ops.Login(request.Email, request.Password, request.TotpCode, s.clientAddr(ctx))
+	if err != nil {
+		if e := teresa_errors.Get(err); e == ErrLocked || e == ErrTOTPCodeRequired {
+			return nil, e
+		}
+		return nil, auth.ErrPermissionDenied
+	}
+	return newLoginResponse(tokens), nil
+}
+
+// clientAddr returns the IP address of the client of the call, if known;
+// behind a trusted proxy it comes from the X-Forwarded-For header.
+func (s *Service) clientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if !isTrusted(host, s.trustedProxies) {
+		return host
+	}
+	md, _ := metadata.FromContext(ctx)
+	if addr := forwardedClientAddr(md["x-forwarded-for"], s.trustedProxies); addr != "" {
+		return addr
+	}
+	return host
+}
+
+func (s *Service) Refresh(ctx context.Context, request *userpb.RefreshRequest) (*userpb.LoginResponse, error) {
+	tokens, err := s.ops.Refresh(request.RefreshToken)
 	if err != nil {
 		return nil, auth.ErrPermissionDenied
 	}
-	return &userpb.LoginResponse{Token: token}, nil
+	return newLoginResponse(tokens), nil
+}
+
+func (s *Service) Logout(ctx context.Context, request *userpb.LogoutRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	md, ok := metadata.FromContext(ctx)
+	if !ok || len(md["token"]) < 1 {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.Logout(u.Email, md["token"][0], request.RefreshToken); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) RevokeSessions(ctx context.Context, request *userpb.RevokeSessionsRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !u.IsAdmin && u.Email != request.Email {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.RevokeSessions(request.Email); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
 }
 
 func (s *Service) SetPassword(ctx context.Context, request *userpb.SetPasswordRequest) (*userpb.Empty, error) {
@@ -57,10 +118,68 @@ func (s *Service) Create(ctx context.Context, request *userpb.CreateRequest) (*u
 	return &userpb.Empty{}, nil
 }
 
+func (s *Service) Unlock(ctx context.Context, request *userpb.UnlockRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.Unlock(request.Email); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) EnrollTOTP(ctx context.Context, request *userpb.Empty) (*userpb.EnrollTOTPResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	e, err := s.ops.EnrollTOTP(u.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.EnrollTOTPResponse{Secret: e.Secret, ProvisioningUri: e.ProvisioningURI}, nil
+}
+
+func (s *Service) EnableTOTP(ctx context.Context, request *userpb.EnableTOTPRequest) (*userpb.EnableTOTPResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	codes, err := s.ops.EnableTOTP(u.Email, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.EnableTOTPResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP disables the two-factor authentication of the user, with one
+// of its codes, or of any user by an admin, like when the device is lost.
+func (s *Service) DisableTOTP(ctx context.Context, request *userpb.DisableTOTPRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	email := request.Email
+	if email == "" {
+		email = u.Email
+	}
+	if email == u.Email {
+		if err := s.ops.VerifyTOTP(email, request.Code); err != nil {
+			return nil, err
+		}
+	} else if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.DisableTOTP(email); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func newLoginResponse(tokens *Tokens) *userpb.LoginResponse {
+	return &userpb.LoginResponse{Token: tokens.Token, RefreshToken: tokens.RefreshToken}
+}
+
 func (s *Service) RegisterService(grpcServer *grpc.Server) {
 	userpb.RegisterUserServer(grpcServer, s)
 }
 
-func NewService(ops Operations) *Service {
-	return &Service{ops: ops}
+func NewService(ops Operations, opts *Options) *Service {
+	s := &Service{ops: ops}
+	if opts != nil {
+		s.trustedProxies = parseTrustedProxies(opts.TrustedProxies)
+	}
+	return s
 }
@@ -1,7 +1,11 @@
 package user
 
 import (
+	"net"
+
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"testing"
 
@@ -20,7 +24,7 @@ func TestUserLoginSuccess(t *testing.T) {
 		Email:    expectedEmail,
 	}
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	r, err := s.Login(
 		context.Background(),
 		&userpb.LoginRequest{Email: expectedEmail, Password: expectedPassword},
@@ -35,7 +39,7 @@ func TestUserLoginSuccess(t *testing.T) {
 
 func TestUserLoginFail(t *testing.T) {
 	fake := NewFakeOperations()
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	_, err := s.Login(
 		context.Background(),
 		&userpb.LoginRequest{Email: "invalid@luizalabs.com", Password: "123"},
@@ -45,6 +49,29 @@ func TestUserLoginFail(t *testing.T) {
 	}
 }
 
+func TestUserLoginTOTP(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &storage.User{Email: email, Password: "123456", TOTPEnabled: true}
+	s := NewService(fake, nil)
+
+	var testCases = []struct {
+		code        string
+		expectedErr error
+	}{
+		{"", ErrTOTPCodeRequired},
+		{"000000", auth.ErrPermissionDenied},
+		{"123456", nil},
+	}
+
+	for _, tc := range testCases {
+		req := &userpb.LoginRequest{Email: email, Password: "123456", TotpCode: tc.code}
+		if _, err := s.Login(context.Background(), req); err != tc.expectedErr {
+			t.Errorf("expected %v for %q, got %v", tc.expectedErr, tc.code, err)
+		}
+	}
+}
+
 func TestSetPasswordSuccess(t *testing.T) {
 	fake := NewFakeOperations()
 
@@ -55,7 +82,7 @@ func TestSetPasswordSuccess(t *testing.T) {
 		Email:    expectedEmail,
 	}
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: expectedEmail})
 	_, err := s.SetPassword(
 		ctx,
@@ -79,7 +106,7 @@ func TestDeleteSuccess(t *testing.T) {
 		Email:    email,
 	}
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", admin)
 	_, err := s.Delete(
 		ctx,
@@ -102,7 +129,7 @@ func TestDeletePermissionDenied(t *testing.T) {
 		Email:    email,
 	}
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", fakeAdmin)
 	_, err := s.Delete(
 		ctx,
@@ -124,7 +151,7 @@ func TestCreateSuccess(t *testing.T) {
 	email := "teresa@luizalabs.com"
 	pass := "test1234"
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", admin)
 	_, err := s.Create(
 		ctx,
@@ -143,7 +170,7 @@ func TestCreateErrPermissionDenied(t *testing.T) {
 	}
 	email := "teresa@luizalabs.com"
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", fakeAdmin)
 	_, err := s.Create(
 		ctx,
@@ -168,7 +195,7 @@ func TestCreateErrUserAlreadyExists(t *testing.T) {
 		Name:  name,
 	}
 
-	s := NewService(fake)
+	s := NewService(fake, nil)
 	ctx := context.WithValue(context.Background(), "user", admin)
 	_, err := s.Create(
 		ctx,
@@ -178,3 +205,144 @@ func TestCreateErrUserAlreadyExists(t *testing.T) {
 		t.Errorf("expected ErrUserAlreadyExists, got %s", err)
 	}
 }
+
+func TestRevokeSessionsPermission(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &storage.User{Email: email}
+	s := NewService(fake, nil)
+
+	var testCases = []struct {
+		user        *storage.User
+		expectedErr error
+	}{
+		{&storage.User{Email: "gopher@luizalabs.com"}, auth.ErrPermissionDenied},
+		{&storage.User{Email: email}, nil},
+		{&storage.User{Email: "admin@luizalabs.com", IsAdmin: true}, nil},
+	}
+
+	for _, tc := range testCases {
+		ctx := context.WithValue(context.Background(), "user", tc.user)
+		_, err := s.RevokeSessions(ctx, &userpb.RevokeSessionsRequest{Email: email})
+		if err != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.user.Email, err)
+		}
+	}
+}
+
+func TestUnlockPermission(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &storage.User{Email: email}
+	s := NewService(fake, nil)
+
+	var testCases = []struct {
+		user        *storage.User
+		email       string
+		expectedErr error
+	}{
+		{&storage.User{Email: email}, email, auth.ErrPermissionDenied},
+		{&storage.User{Email: "admin@luizalabs.com", IsAdmin: true}, email, nil},
+		{&storage.User{Email: "admin@luizalabs.com", IsAdmin: true}, "unknown@luizalabs.com", ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		ctx := context.WithValue(context.Background(), "user", tc.user)
+		if _, err := s.Unlock(ctx, &userpb.UnlockRequest{Email: tc.email}); err != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.user.Email, err)
+		}
+	}
+}
+
+func TestDisableTOTPPermission(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &storage.User{Email: email, TOTPEnabled: true}
+	s := NewService(fake, nil)
+
+	var testCases = []struct {
+		user        *storage.User
+		req         *userpb.DisableTOTPRequest
+		expectedErr error
+	}{
+		{&storage.User{Email: email}, &userpb.DisableTOTPRequest{Code: "000000"}, auth.ErrPermissionDenied},
+		{&storage.User{Email: "gopher@luizalabs.com"}, &userpb.DisableTOTPRequest{Email: email}, auth.ErrPermissionDenied},
+		{&storage.User{Email: email}, &userpb.DisableTOTPRequest{Code: "123456"}, nil},
+		{&storage.User{Email: "admin@luizalabs.com", IsAdmin: true}, &userpb.DisableTOTPRequest{Email: email}, nil},
+	}
+
+	for _, tc := range testCases {
+		ctx := context.WithValue(context.Background(), "user", tc.user)
+		if _, err := s.DisableTOTP(ctx, tc.req); err != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.user.Email, err)
+		}
+	}
+}
+
+func TestEnableTOTP(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &storage.User{Email: email}
+	s := NewService(fake, nil)
+	ctx := context.WithValue(context.Background(), "user", &storage.User{Email: email})
+
+	e, err := s.EnrollTOTP(ctx, &userpb.Empty{})
+	if err != nil {
+		t.Fatal("error enrolling: ", err)
+	}
+	if e.ProvisioningUri == "" || e.Secret == "" {
+		t.Errorf("got unexpected enrollment %+v", e)
+	}
+	r, err := s.EnableTOTP(ctx, &userpb.EnableTOTPRequest{Code: "123456"})
+	if err != nil {
+		t.Fatal("error enabling: ", err)
+	}
+	if len(r.RecoveryCodes) == 0 {
+		t.Error("expected recovery codes")
+	}
+}
+
+func TestUserRefresh(t *testing.T) {
+	s := NewService(NewFakeOperations(), nil)
+	r, err := s.Refresh(context.Background(), &userpb.RefreshRequest{RefreshToken: "good refresh token"})
+	if err != nil {
+		t.Fatal("error on refresh: ", err)
+	}
+	if r.Token != "good token" {
+		t.Errorf("expected good token, got %s", r.Token)
+	}
+	if _, err := s.Refresh(context.Background(), &userpb.RefreshRequest{RefreshToken: "bad"}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestServiceClientAddr(t *testing.T) {
+	s := NewService(NewFakeOperations(), &Options{TrustedProxies: []string{"10.0.0.0/8", "192.168.0.1"}})
+
+	var testCases = []struct {
+		peer         string
+		forwardedFor []string
+		expected     string
+	}{
+		{"203.0.113.7:4321", nil, "203.0.113.7"},
+		{"203.0.113.7:4321", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"10.1.2.3:4321", nil, "10.1.2.3"},
+		{"10.1.2.3:4321", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"10.1.2.3:4321", []string{"1.2.3.4, 198.51.100.1, 192.168.0.1"}, "198.51.100.1"},
+		{"10.1.2.3:4321", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+	}
+
+	for _, tc := range testCases {
+		addr, err := net.ResolveTCPAddr("tcp", tc.peer)
+		if err != nil {
+			t.Fatal("error resolving address: ", err)
+		}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		if tc.forwardedFor != nil {
+			ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-forwarded-for": tc.forwardedFor})
+		}
+		if actual := s.clientAddr(ctx); actual != tc.expected {
+			t.Errorf("expected %s for %s %v, got %s", tc.expected, tc.peer, tc.forwardedFor, actual)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package user
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+// Options are the login lockout and the password policy, read from the
+// TERESA_USER_* env vars.
+type Options struct {
+	// MaxFailedLogins of an e-mail and MaxFailedLoginsPerIP of a client
+	// address within FailedLoginsWindow lock them out for LockoutDuration;
+	// zero disables the lockout
+	MaxFailedLogins      int           `split_words:"true" default:"5"`
+	MaxFailedLoginsPerIP int           `envconfig:"max_failed_logins_per_ip" default:"20"`
+	FailedLoginsWindow   time.Duration `split_words:"true" default:"15m"`
+	LockoutDuration      time.Duration `split_words:"true" default:"15m"`
+	PasswordMinLength    int           `split_words:"true" default:"8"`
+	// PasswordMinClasses is how many of lowercase letters, uppercase
+	// letters, digits and symbols the passwords must have
+	PasswordMinClasses int `split_words:"true" default:"1"`
+	// TOTPRequiredForAdmins allows the admins without two-factor
+	// authentication only to enable it
+	TOTPRequiredForAdmins bool   `envconfig:"totp_required_for_admins"`
+	TOTPIssuer            string `envconfig:"totp_issuer" default:"Teresa"`
+	// TrustedProxies are the addresses or networks (CIDR) of the proxies
+	// whose X-Forwarded-For header gives the client address
+	TrustedProxies []string `split_words:"true"`
+}
+
+const (
+	defaultPasswordMinLength = 8
+	defaultTOTPIssuer        = "Teresa"
+	emailSubjectPrefix       = "email:"
+	addrSubjectPrefix        = "addr:"
+)
+
+// parseTrustedProxies returns the networks of the trusted proxies, single
+// addresses are networks of one address; invalid ones are skipped.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			log.WithError(err).Warnf("Skipping invalid trusted proxy %s", p)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedClientAddr returns the client address of a request of a trusted
+// proxy from its X-Forwarded-For addresses: the last one not of a trusted
+// proxy, as the ones before it can be forged by the client.
+func forwardedClientAddr(forwardedFor []string, trusted []*net.IPNet) string {
+	var addrs []string
+	for _, v := range forwardedFor {
+		for _, a := range strings.Split(v, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				addrs = append(addrs, a)
+			}
+		}
+	}
+	for i := len(addrs) - 1; i >= 0; i-- {
+		if !isTrusted(addrs[i], trusted) {
+			return addrs[i]
+		}
+	}
+	if len(addrs) > 0 {
+		return addrs[0]
+	}
+	return ""
+}
+
+type loginSubject struct {
+	name        string
+	maxFailures int
+}
+
+func (dbu *DatabaseOperations) loginSubjects(email, addr string) []loginSubject {
+	subjects := []loginSubject{{emailSubjectPrefix + email, dbu.opts.MaxFailedLogins}}
+	if addr != "" {
+		subjects = append(subjects, loginSubject{addrSubjectPrefix + addr, dbu.opts.MaxFailedLoginsPerIP})
+	}
+	return subjects
+}
+
+// checkLockout returns ErrLocked if any of the subjects is locked out.
+func (dbu *DatabaseOperations) checkLockout(subjects []loginSubject) error {
+	var names []string
+	for _, s := range subjects {
+		if s.maxFailures > 0 {
+			names = append(names, s.name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	var count int
+	err := dbu.DB.Model(&storage.LoginAttempts{}).
+		Where("subject in (?) and locked_until > ?", names, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "Checking login lockout"),
+		)
+	}
+	if count > 0 {
+		return ErrLocked
+	}
+	return nil
+}
+
+// recordFailure counts a failed login of the subjects, locking out the ones
+// reaching their limit of failures within the window.
+func (dbu *DatabaseOperations) recordFailure(subjects []loginSubject) error {
+	now := time.Now()
+	err := dbu.DB.
+		Where("first_failure < ? and (locked_until is null or locked_until < ?)", now.Add(-dbu.opts.FailedLoginsWindow), now).
+		Delete(&storage.LoginAttempts{}).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "Deleting expired login attempts"),
+		)
+	}
+
+	for _, s := range subjects {
+		if s.maxFailures <= 0 {
+			continue
+		}
+		a := new(storage.LoginAttempts)
+		if err := dbu.DB.Where(&storage.LoginAttempts{Subject: s.name}).FirstOrInit(a).Error; err != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("Finding login attempts of %s", s.name)),
+			)
+		}
+		if a.Failures == 0 || now.Sub(a.FirstFailure) > dbu.opts.FailedLoginsWindow {
+			a.Failures = 0
+			a.FirstFailure = now
+		}
+		a.Failures++
+		if a.Failures >= s.maxFailures {
+			until := now.Add(dbu.opts.LockoutDuration)
+			a.LockedUntil = &until
+			a.Failures = 0
+			log.WithField("subject", s.name).Warn("Locking out after too many failed logins")
+		}
+		if err := dbu.DB.Save(a).Error; err != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("Saving login attempts of %s", s.name)),
+			)
+		}
+	}
+	return nil
+}
+
+// resetFailures forgets the failed logins and the lockout of the e-mail.
+func (dbu *DatabaseOperations) resetFailures(email string) (bool, error) {
+	res := dbu.DB.Where(&storage.LoginAttempts{Subject: emailSubjectPrefix + email}).Delete(&storage.LoginAttempts{})
+	if res.Error != nil {
+		return false, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(res.Error, fmt.Sprintf("Deleting login attempts of %s", email)),
+		)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// validatePassword checks the new password of a user against the policy.
+func (dbu *DatabaseOperations) validatePassword(pass string) error {
+	if len(pass) < dbu.opts.PasswordMinLength {
+		return ErrInvalidPassword
+	}
+	var lower, upper, digit, symbol int
+	for _, r := range pass {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	if lower+upper+digit+symbol < dbu.opts.PasswordMinClasses {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+// isCurrentPassword reports whether pass is the current password of the
+// user.
+func isCurrentPassword(u *storage.User, pass string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(pass)) == nil
+}
@@ -0,0 +1,134 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+// TeamMemberships are the teams of the users, synced with the groups of
+// their identities by SyncTeams.
+type TeamMemberships interface {
+	// Teams returns the names of the teams of the user
+	Teams(email string) ([]string, error)
+	// Join adds the user as a member of the team, if the team exists and the
+	// user isn't a member of it yet
+	Join(team, email string) error
+	// Leave removes the user from the team
+	Leave(team, email string) error
+}
+
+// SyncTeams adds the user as a member of the teams it isn't a member of
+// yet, keeping the role of current members, and removes it from the managed
+// teams it is a member of but aren't in teams anymore.
+func SyncTeams(m TeamMemberships, email string, teams []string, managed func(team string) bool) error {
+	current, err := m.Teams(email)
+	if err != nil {
+		return err
+	}
+
+	want := make(map[string]bool)
+	for _, name := range teams {
+		want[name] = true
+	}
+	has := make(map[string]bool)
+	for _, name := range current {
+		if want[name] || managed == nil || !managed(name) {
+			has[name] = true
+			continue
+		}
+		if err := m.Leave(name, email); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range teams {
+		if has[name] {
+			continue
+		}
+		if err := m.Join(name, email); err != nil {
+			return err
+		}
+		has[name] = true
+	}
+	return nil
+}
+
+// dbTeamMemberships are the TeamMemberships of the users table, used for
+// the identities of the Authenticators.
+type dbTeamMemberships struct {
+	dbu *DatabaseOperations
+}
+
+func (m *dbTeamMemberships) Teams(email string) ([]string, error) {
+	var names []string
+	err := m.dbu.DB.Table("teams").
+		Joins("join teams_users on teams_users.team_id = teams.id").
+		Joins("join users on users.id = teams_users.user_id").
+		Where("users.email = ?", email).
+		Pluck("teams.name", &names).Error
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding teams of user %s", email)),
+		)
+	}
+	return names, nil
+}
+
+func (m *dbTeamMemberships) Join(team, email string) error {
+	t, u, err := m.find(team, email)
+	if err != nil || t == nil {
+		return err
+	}
+	err = m.dbu.DB.Where(&storage.TeamUser{TeamID: t.ID, UserID: u.ID}).
+		Attrs(&storage.TeamUser{Role: memberRole}).
+		FirstOrCreate(new(storage.TeamUser)).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("adding user %s to team %s", email, team)),
+		)
+	}
+	return nil
+}
+
+func (m *dbTeamMemberships) Leave(team, email string) error {
+	t, u, err := m.find(team, email)
+	if err != nil || t == nil {
+		return err
+	}
+	err = m.dbu.DB.Where(&storage.TeamUser{TeamID: t.ID, UserID: u.ID}).
+		Delete(storage.TeamUser{}).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing user %s from team %s", email, team)),
+		)
+	}
+	return nil
+}
+
+// find returns the team and the user, or a nil team if it doesn't exist.
+func (m *dbTeamMemberships) find(team, email string) (*storage.Team, *storage.User, error) {
+	t := new(storage.Team)
+	err := m.dbu.DB.Where(&storage.Team{Name: team}).First(t).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding team %s", team)),
+		)
+	}
+	u, err := m.dbu.GetUser(email)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, u, nil
+}
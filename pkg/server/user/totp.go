@@ -0,0 +1,303 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+// TOTP codes (RFC 6238) of 6 digits changing every 30 seconds, accepting
+// the codes of the previous and next time steps for clock skew.
+const (
+	totpDigits        = 6
+	totpPeriod        = 30
+	totpSkew          = 1
+	totpSecretSize    = 20
+	recoveryCodes     = 10
+	recoveryCodeBytes = 5
+)
+
+// TOTPEnrollment is the secret of the two-factor authentication of a user
+// and its otpauth:// URI, usually shown as a QR code.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+func newTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encodeTOTPSecret(b), nil
+}
+
+// encodeTOTPSecret and decodeTOTPSecret use base32 without the padding,
+// like the authenticator apps.
+func encodeTOTPSecret(b []byte) string {
+	return strings.TrimRight(base32.StdEncoding.EncodeToString(b), "=")
+}
+
+func decodeTOTPSecret(s string) ([]byte, error) {
+	if n := len(s) % 8; n != 0 {
+		s += strings.Repeat("=", 8-n)
+	}
+	return base32.StdEncoding.DecodeString(s)
+}
+
+func provisioningURI(issuer, email, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(totpPeriod))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// totpCode is the HOTP (RFC 4226) code of the counter.
+func totpCode(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// validateTOTP returns the time step of the code if it is valid at t and
+// newer than the time step last used.
+func validateTOTP(secret, code string, t time.Time, last int64) (int64, bool) {
+	key, err := decodeTOTPSecret(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	current := t.Unix() / totpPeriod
+	for counter := current - totpSkew; counter <= current+totpSkew; counter++ {
+		if counter <= last {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, counter)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(b)
+	return code[:len(code)/2] + "-" + code[len(code)/2:], nil
+}
+
+// EnrollTOTP creates a new secret of two-factor authentication for the
+// user, which is enabled by EnableTOTP.
+func (dbu *DatabaseOperations) EnrollTOTP(email string) (*TOTPEnrollment, error) {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	if err := dbu.DB.Model(u).UpdateColumn("totp_secret", secret).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Saving TOTP secret of user %s", email)),
+		)
+	}
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI(dbu.opts.TOTPIssuer, email, secret),
+	}, nil
+}
+
+// EnableTOTP enables the enrolled two-factor authentication of the user
+// after checking a code, returning new recovery codes.
+func (dbu *DatabaseOperations) EnableTOTP(email, code string) ([]string, error) {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if u.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+	counter, ok := validateTOTP(u.TOTPSecret, code, time.Now(), 0)
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes := make([]string, recoveryCodes)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+	err = dbu.inTransaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&storage.RecoveryCode{UserID: u.ID}).Delete(&storage.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		for _, c := range codes {
+			if err := tx.Create(&storage.RecoveryCode{UserID: u.ID, Hash: hashRecoveryCode(c)}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(u).UpdateColumns(map[string]interface{}{
+			"totp_enabled": true,
+			"totp_counter": counter,
+		}).Error
+	})
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Enabling TOTP of user %s", email)),
+		)
+	}
+	return codes, nil
+}
+
+// DisableTOTP disables the two-factor authentication of the user, deleting
+// its secret and recovery codes.
+func (dbu *DatabaseOperations) DisableTOTP(email string) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	err = dbu.inTransaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&storage.RecoveryCode{UserID: u.ID}).Delete(&storage.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(u).UpdateColumns(map[string]interface{}{
+			"totp_secret":  "",
+			"totp_enabled": false,
+			"totp_counter": 0,
+		}).Error
+	})
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Disabling TOTP of user %s", email)),
+		)
+	}
+	return nil
+}
+
+// VerifyTOTP checks a TOTP or recovery code of the user, which can't be
+// used again.
+func (dbu *DatabaseOperations) VerifyTOTP(email, code string) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	if !u.TOTPEnabled {
+		return ErrTOTPNotEnrolled
+	}
+	return dbu.verifyCode(u, code)
+}
+
+// TOTPRequired reports whether the user must enable the two-factor
+// authentication before doing anything else.
+func (dbu *DatabaseOperations) TOTPRequired(u *storage.User) bool {
+	return dbu.opts.TOTPRequiredForAdmins && u.IsAdmin && !u.TOTPEnabled
+}
+
+// checkSecondFactor checks the code given at the login of a user with
+// two-factor authentication.
+func (dbu *DatabaseOperations) checkSecondFactor(u *storage.User, code string) error {
+	if !u.TOTPEnabled {
+		return nil
+	}
+	if code == "" {
+		return ErrTOTPCodeRequired
+	}
+	return dbu.verifyCode(u, code)
+}
+
+func (dbu *DatabaseOperations) verifyCode(u *storage.User, code string) error {
+	code = strings.TrimSpace(code)
+	if counter, ok := validateTOTP(u.TOTPSecret, code, time.Now(), u.TOTPCounter); ok {
+		// the condition on the counter makes concurrent uses of a code fail
+		res := dbu.DB.Model(&storage.User{}).
+			Where("id = ? and totp_counter < ?", u.ID, counter).
+			UpdateColumn("totp_counter", counter)
+		if res.Error != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(res.Error, fmt.Sprintf("Saving TOTP counter of user %s", u.Email)),
+			)
+		}
+		if res.RowsAffected == 1 {
+			return nil
+		}
+	}
+
+	res := dbu.DB.Where(&storage.RecoveryCode{UserID: u.ID, Hash: hashRecoveryCode(code)}).
+		Delete(&storage.RecoveryCode{})
+	if res.Error != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(res.Error, fmt.Sprintf("Using recovery code of user %s", u.Email)),
+		)
+	}
+	if res.RowsAffected == 1 {
+		return nil
+	}
+	return teresa_errors.New(
+		auth.ErrPermissionDenied,
+		errors.Errorf("invalid two-factor code of user %s", u.Email),
+	)
+}
+
+func (dbu *DatabaseOperations) inTransaction(fn func(tx *gorm.DB) error) error {
+	tx := dbu.DB.Begin()
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
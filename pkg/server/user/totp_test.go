@@ -0,0 +1,198 @@
+package user
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 test vectors, truncated to 6 digits
+	key := []byte("12345678901234567890")
+	var testCases = []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range testCases {
+		if actual := totpCode(key, tc.unix/totpPeriod); actual != tc.expected {
+			t.Errorf("expected %s at %d, got %s", tc.expected, tc.unix, actual)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := encodeTOTPSecret([]byte("12345678901234567890"))
+	now := time.Unix(1111111109, 0)
+
+	var testCases = []struct {
+		code     string
+		last     int64
+		expected bool
+	}{
+		{"081804", 0, true},
+		{"081804", now.Unix() / totpPeriod, false},
+		{totpCode([]byte("12345678901234567890"), now.Unix()/totpPeriod-1), 0, true},
+		{totpCode([]byte("12345678901234567890"), now.Unix()/totpPeriod+2), 0, false},
+		{"000000", 0, false},
+		{"81804", 0, false},
+	}
+
+	for _, tc := range testCases {
+		if _, actual := validateTOTP(secret, tc.code, now, tc.last); actual != tc.expected {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.code, actual)
+		}
+	}
+}
+
+func TestTOTPSecretEncoding(t *testing.T) {
+	for _, s := range []string{"", "f", "fo", "foobar", "12345678901234567890"} {
+		encoded := encodeTOTPSecret([]byte(s))
+		if strings.Contains(encoded, "=") {
+			t.Errorf("expected no padding, got %s", encoded)
+		}
+		decoded, err := decodeTOTPSecret(encoded)
+		if err != nil || string(decoded) != s {
+			t.Errorf("expected %q, got %q and error %v", s, decoded, err)
+		}
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	u, err := url.Parse(provisioningURI("Teresa", "gopher@luizalabs.com", "SECRET"))
+	if err != nil {
+		t.Fatal("error parsing uri: ", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Teresa:gopher@luizalabs.com" {
+		t.Errorf("got unexpected uri %s", u)
+	}
+	if q := u.Query(); q.Get("secret") != "SECRET" || q.Get("issuer") != "Teresa" {
+		t.Errorf("got unexpected query %s", u.RawQuery)
+	}
+}
+
+func currentTOTPCode(t *testing.T, secret string, step int64) string {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		t.Fatal("error decoding secret: ", err)
+	}
+	return totpCode(key, time.Now().Unix()/totpPeriod+step)
+}
+
+func TestDatabaseOperationsTOTP(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
+	email := "teresa@luizalabs.com"
+	if err := dbu.Create("teresa", email, "secret-pass", false); err != nil {
+		t.Fatal("error creating user: ", err)
+	}
+
+	if _, err := dbu.EnableTOTP(email, "123456"); err != ErrTOTPNotEnrolled {
+		t.Errorf("expected ErrTOTPNotEnrolled, got %v", err)
+	}
+	e, err := dbu.EnrollTOTP(email)
+	if err != nil {
+		t.Fatal("error enrolling: ", err)
+	}
+	if _, err := dbu.Login(email, "secret-pass", "", ""); err != nil {
+		t.Errorf("expected login without code before enabling, got %v", err)
+	}
+	if _, err := dbu.EnableTOTP(email, "000000"); err != ErrInvalidTOTPCode {
+		t.Errorf("expected ErrInvalidTOTPCode, got %v", err)
+	}
+	recovery, err := dbu.EnableTOTP(email, currentTOTPCode(t, e.Secret, 0))
+	if err != nil {
+		t.Fatal("error enabling: ", err)
+	}
+	if len(recovery) != recoveryCodes {
+		t.Errorf("expected %d recovery codes, got %d", recoveryCodes, len(recovery))
+	}
+	if _, err := dbu.EnrollTOTP(email); err != ErrTOTPAlreadyEnabled {
+		t.Errorf("expected ErrTOTPAlreadyEnabled, got %v", err)
+	}
+
+	if _, err := dbu.Login(email, "secret-pass", "", ""); err != ErrTOTPCodeRequired {
+		t.Errorf("expected ErrTOTPCodeRequired, got %v", err)
+	}
+	if _, err := dbu.Login(email, "wrong", "", ""); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a wrong password, got %v", err)
+	}
+	code := currentTOTPCode(t, e.Secret, 1)
+	if _, err := dbu.Login(email, "secret-pass", code, ""); err != nil {
+		t.Errorf("expected login with code, got %v", err)
+	}
+	if _, err := dbu.Login(email, "secret-pass", code, ""); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied reusing a code, got %v", err)
+	}
+
+	if _, err := dbu.IssueTokens(email, ""); err != ErrTOTPCodeRequired {
+		t.Errorf("expected ErrTOTPCodeRequired issuing tokens, got %v", err)
+	}
+	if _, err := dbu.IssueTokens(email, currentTOTPCode(t, e.Secret, 1)); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied issuing tokens with a used code, got %v", err)
+	}
+
+	if _, err := dbu.Login(email, "secret-pass", recovery[0], ""); err != nil {
+		t.Errorf("expected login with recovery code, got %v", err)
+	}
+	if _, err := dbu.Login(email, "secret-pass", recovery[0], ""); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied reusing a recovery code, got %v", err)
+	}
+	if err := dbu.VerifyTOTP(email, recovery[1]); err != nil {
+		t.Errorf("expected valid recovery code, got %v", err)
+	}
+
+	if err := dbu.DisableTOTP(email); err != nil {
+		t.Fatal("error disabling: ", err)
+	}
+	if _, err := dbu.Login(email, "secret-pass", "", ""); err != nil {
+		t.Errorf("expected login without code after disabling, got %v", err)
+	}
+	if err := dbu.VerifyTOTP(email, recovery[2]); err != ErrTOTPNotEnrolled {
+		t.Errorf("expected ErrTOTPNotEnrolled, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsTOTPRequired(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake(), &Options{TOTPRequiredForAdmins: true})
+	if err := dbu.Create("admin", "admin@luizalabs.com", "secret-pass", true); err != nil {
+		t.Fatal("error creating user: ", err)
+	}
+	u, err := dbu.GetUser("admin@luizalabs.com")
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+
+	if !dbu.TOTPRequired(u) {
+		t.Error("expected TOTP required for admin")
+	}
+	u.TOTPEnabled = true
+	if dbu.TOTPRequired(u) {
+		t.Error("expected TOTP not required for admin with TOTP")
+	}
+	u.IsAdmin, u.TOTPEnabled = false, false
+	if dbu.TOTPRequired(u) {
+		t.Error("expected TOTP not required for user")
+	}
+}
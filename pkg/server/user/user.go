@@ -1,8 +1,12 @@
 package user
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 
@@ -14,42 +18,251 @@ import (
 )
 
 const (
-	minPassLength = 8
+	// memberRole is the team role of the users added to the teams of their
+	// identity (team.RoleMember)
+	memberRole         = "member"
+	randomPasswordSize = 32
 )
 
+// Tokens are the short-lived token used in the calls and the refresh token
+// used to get new ones.
+type Tokens struct {
+	Token        string
+	RefreshToken string
+}
+
 type Operations interface {
-	Login(email, password string) (string, error)
+	Login(email, password, code, addr string) (*Tokens, error)
+	Refresh(refreshToken string) (*Tokens, error)
+	IssueTokens(email, code string) (*Tokens, error)
+	Logout(email, token, refreshToken string) error
+	RevokeSessions(email string) error
+	Authenticate(claims *auth.Claims) (*storage.User, error)
 	GetUser(email string) (*storage.User, error)
 	SetPassword(email, newPassword string) error
 	Delete(email string) error
 	Create(name, email, pass string, admin bool) error
+	Provision(email string) (*storage.User, error)
+	Unlock(email string) error
+	EnrollTOTP(email string) (*TOTPEnrollment, error)
+	EnableTOTP(email, code string) ([]string, error)
+	DisableTOTP(email string) error
+	VerifyTOTP(email, code string) error
+	TOTPRequired(u *storage.User) bool
 }
 
 type DatabaseOperations struct {
-	DB   *gorm.DB
-	auth auth.Auth
+	DB            *gorm.DB
+	auth          auth.Auth
+	authenticator Authenticator
+	opts          *Options
 }
 
-func (dbu *DatabaseOperations) Login(email, password string) (string, error) {
+// Login checks the password and the two-factor authentication code, if
+// enabled, of the user logging in from the client address addr, locking out
+// the e-mail and the address after too many failures.
+func (dbu *DatabaseOperations) Login(email, password, code, addr string) (*Tokens, error) {
+	subjects := dbu.loginSubjects(email, addr)
+	if err := dbu.checkLockout(subjects); err != nil {
+		return nil, err
+	}
+	id, err := dbu.authenticator.Authenticate(email, password)
+	if err != nil {
+		if teresa_errors.Get(err) == auth.ErrPermissionDenied {
+			if err := dbu.recordFailure(subjects); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	u, err := dbu.provision(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbu.checkSecondFactor(u, code); err != nil {
+		if teresa_errors.Get(err) == auth.ErrPermissionDenied {
+			if err := dbu.recordFailure(subjects); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	if _, err := dbu.resetFailures(email); err != nil {
+		return nil, err
+	}
+	return dbu.generateTokens(u)
+}
+
+// Unlock forgets the failed logins of the user, ending its lockout.
+func (dbu *DatabaseOperations) Unlock(email string) error {
+	found, err := dbu.resetFailures(email)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if _, err := dbu.GetUser(email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Provision returns the user, creating it with a random password when it
+// logs in for the first time with another backend or identity provider.
+func (dbu *DatabaseOperations) Provision(email string) (*storage.User, error) {
 	u, err := dbu.GetUser(email)
+	if err != ErrNotFound {
+		return u, err
+	}
+	pass, err := randomPassword()
 	if err != nil {
-		return "", auth.ErrPermissionDenied
+		return nil, teresa_errors.NewInternalServerError(err)
 	}
-	if err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
-		return "", teresa_errors.New(
-			auth.ErrPermissionDenied,
-			errors.Wrap(err, fmt.Sprintf("Authentication failed for user %s", email)),
-		)
+	if err := dbu.create(email, email, pass, false); err != nil {
+		return nil, err
 	}
+	log.WithField("user", email).Info("Created user on its first login")
+	return dbu.GetUser(email)
+}
 
-	token, err := dbu.auth.GenerateToken(email)
+// provision provisions the user of an identity of another backend and
+// syncs its teams with the teams of the identity.
+func (dbu *DatabaseOperations) provision(id *Identity) (*storage.User, error) {
+	u, err := dbu.Provision(id.Email)
+	if err != nil || len(id.Teams) == 0 && len(id.ManagedTeams) == 0 {
+		return u, err
+	}
+	managed := make(map[string]bool)
+	for _, name := range id.ManagedTeams {
+		managed[name] = true
+	}
+	isManaged := func(team string) bool { return managed[team] }
+	if err := SyncTeams(&dbTeamMemberships{dbu}, id.Email, id.Teams, isManaged); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// IssueTokens returns tokens for a user authenticated by other means than
+// its password, like OpenID Connect, checking the code of the users with
+// two-factor authentication.
+func (dbu *DatabaseOperations) IssueTokens(email, code string) (*Tokens, error) {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbu.checkSecondFactor(u, code); err != nil {
+		return nil, err
+	}
+	return dbu.generateTokens(u)
+}
+
+func (dbu *DatabaseOperations) generateTokens(u *storage.User) (*Tokens, error) {
+	token, err := dbu.auth.GenerateToken(u.Email, u.TokenVersion)
 	if err != nil {
-		return "", teresa_errors.New(
+		return nil, teresa_errors.New(
 			auth.ErrPermissionDenied,
 			errors.Wrap(err, "Signing JWT token"),
 		)
 	}
-	return token, nil
+	refreshToken, err := dbu.auth.GenerateRefreshToken(u.Email, u.TokenVersion)
+	if err != nil {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			errors.Wrap(err, "Signing JWT refresh token"),
+		)
+	}
+	return &Tokens{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// Refresh returns new tokens in exchange of a valid refresh token, which is
+// revoked.
+func (dbu *DatabaseOperations) Refresh(refreshToken string) (*Tokens, error) {
+	claims, err := dbu.auth.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	u, err := dbu.Authenticate(claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbu.revoke(claims); err != nil {
+		return nil, err
+	}
+	return dbu.generateTokens(u)
+}
+
+// Logout revokes the token of the user and its refresh token, if given.
+func (dbu *DatabaseOperations) Logout(email, token, refreshToken string) error {
+	claims, err := dbu.auth.ValidateToken(token)
+	if err != nil {
+		return err
+	}
+	if claims.Email != email {
+		return auth.ErrPermissionDenied
+	}
+	if err := dbu.revoke(claims); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	claims, err = dbu.auth.ValidateRefreshToken(refreshToken)
+	if err != nil || claims.Email != email {
+		return auth.ErrPermissionDenied
+	}
+	return dbu.revoke(claims)
+}
+
+func (dbu *DatabaseOperations) revoke(claims *auth.Claims) error {
+	err := dbu.DB.Where("expires_at < ?", time.Now()).Delete(&storage.RevokedToken{}).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "Deleting expired revoked tokens"),
+		)
+	}
+	rt := &storage.RevokedToken{TokenID: claims.ID, ExpiresAt: claims.ExpiresAt}
+	if err := dbu.DB.Where(&storage.RevokedToken{TokenID: claims.ID}).FirstOrCreate(rt).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Revoking token of user %s", claims.Email)),
+		)
+	}
+	return nil
+}
+
+// RevokeSessions revokes all the tokens issued to the user by bumping its
+// token version.
+func (dbu *DatabaseOperations) RevokeSessions(email string) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	err = dbu.DB.Model(u).UpdateColumn("token_version", gorm.Expr("token_version + ?", 1)).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Revoking sessions of user %s", email)),
+		)
+	}
+	return nil
+}
+
+// Authenticate returns the user of the claims of a valid token, unless the
+// token was revoked.
+func (dbu *DatabaseOperations) Authenticate(claims *auth.Claims) (*storage.User, error) {
+	u, err := dbu.GetUser(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if u.TokenVersion != claims.Version {
+		return nil, auth.ErrPermissionDenied
+	}
+	if !dbu.DB.Where(&storage.RevokedToken{TokenID: claims.ID}).First(&storage.RevokedToken{}).RecordNotFound() {
+		return nil, auth.ErrPermissionDenied
+	}
+	return u, nil
 }
 
 func (dbu *DatabaseOperations) GetUser(email string) (*storage.User, error) {
@@ -65,6 +278,12 @@ func (dbu *DatabaseOperations) SetPassword(email, newPassword string) error {
 	if err != nil {
 		return err
 	}
+	if err := dbu.validatePassword(newPassword); err != nil {
+		return err
+	}
+	if isCurrentPassword(u, newPassword) {
+		return ErrSamePassword
+	}
 	pass, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return teresa_errors.New(
@@ -97,12 +316,16 @@ func (dbu *DatabaseOperations) Delete(email string) error {
 }
 
 func (dbu *DatabaseOperations) Create(name, email, pass string, admin bool) error {
+	if err := dbu.validatePassword(pass); err != nil {
+		return err
+	}
+	return dbu.create(name, email, pass, admin)
+}
+
+func (dbu *DatabaseOperations) create(name, email, pass string, admin bool) error {
 	if !validations.ValidateEmail(email) {
 		return ErrInvalidEmail
 	}
-	if len(pass) < minPassLength {
-		return ErrInvalidPassword
-	}
 
 	u := new(storage.User)
 	if !dbu.DB.Where(&storage.User{Email: email}).First(u).RecordNotFound() {
@@ -128,7 +351,23 @@ func (dbu *DatabaseOperations) Create(name, email, pass string, admin bool) erro
 	return nil
 }
 
-func NewDatabaseOperations(db *gorm.DB, a auth.Auth) Operations {
-	db.AutoMigrate(&storage.User{})
-	return &DatabaseOperations{DB: db, auth: a}
+func randomPassword() (string, error) {
+	b := make([]byte, randomPasswordSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewDatabaseOperations returns the Operations of the users table; Login
+// checks the password in the table and then in the authenticators, if any.
+// Without options there is no lockout, passwords need 8 characters and the
+// two-factor authentication is optional.
+func NewDatabaseOperations(db *gorm.DB, a auth.Auth, opts *Options, authenticators ...Authenticator) Operations {
+	db.AutoMigrate(&storage.User{}, &storage.RevokedToken{}, &storage.LoginAttempts{}, &storage.RecoveryCode{})
+	if opts == nil {
+		opts = &Options{PasswordMinLength: defaultPasswordMinLength, TOTPIssuer: defaultTOTPIssuer}
+	}
+	authenticators = append([]Authenticator{&DatabaseAuthenticator{DB: db}}, authenticators...)
+	return &DatabaseOperations{DB: db, auth: a, authenticator: NewChain(authenticators...), opts: opts}
 }
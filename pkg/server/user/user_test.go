@@ -1,7 +1,10 @@
 package user
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -9,6 +12,7 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/luizalabs/teresa-api/models/storage"
 	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
 )
 
 func createFakeUser(db *gorm.DB, email, password string) error {
@@ -32,7 +36,7 @@ func TestDatabaseOperationsLogin(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 
 	expectedEmail := "teresa@luizalabs.com"
 	expectedPassword := "secret"
@@ -40,13 +44,16 @@ func TestDatabaseOperationsLogin(t *testing.T) {
 		t.Fatal("error on create fake user: ", err)
 	}
 
-	token, err := dbu.Login(expectedEmail, expectedPassword)
+	tokens, err := dbu.Login(expectedEmail, expectedPassword, "", "")
 	if err != nil {
 		t.Fatal("Error on perform Login: ", err)
 	}
-	if token == "" {
+	if tokens.Token == "" {
 		t.Error("expected a valid token, got a blank string")
 	}
+	if tokens.RefreshToken == "" {
+		t.Error("expected a valid refresh token, got a blank string")
+	}
 }
 
 func TestDatabaseOperationsBadLogin(t *testing.T) {
@@ -56,13 +63,123 @@ func TestDatabaseOperationsBadLogin(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 
-	if _, err := dbu.Login("invalid@luizalabs.com", "secret"); err != auth.ErrPermissionDenied {
+	if _, err := dbu.Login("invalid@luizalabs.com", "secret", "", ""); err != auth.ErrPermissionDenied {
 		t.Errorf("expected ErrPermissionDenied, got %s", err)
 	}
 }
 
+type fakeAuthenticator struct {
+	identity *Identity
+}
+
+func (f *fakeAuthenticator) Authenticate(email, password string) (*Identity, error) {
+	if email != f.identity.Email || password != "secret" {
+		return nil, auth.ErrPermissionDenied
+	}
+	return f.identity, nil
+}
+
+func TestDatabaseOperationsLoginAuthenticators(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+	db.AutoMigrate(&storage.Team{}, &storage.TeamUser{})
+
+	email := "gopher@luizalabs.com"
+	fa := &fakeAuthenticator{identity: &Identity{Email: email, Teams: []string{"luizalabs", "unknown"}}}
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil, fa)
+
+	team := &storage.Team{Name: "luizalabs"}
+	if err := db.Create(team).Error; err != nil {
+		t.Fatal("error creating team: ", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := dbu.Login(email, "secret", "", ""); err != nil {
+			t.Fatal("error on login: ", err)
+		}
+	}
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("expected the user to be created, got ", err)
+	}
+	var members []storage.TeamUser
+	if err := db.Where(&storage.TeamUser{UserID: u.ID}).Find(&members).Error; err != nil {
+		t.Fatal("error getting teams: ", err)
+	}
+	if len(members) != 1 || members[0].TeamID != team.ID || members[0].Role != memberRole {
+		t.Errorf("expected the user as member of luizalabs, got %v", members)
+	}
+
+	if _, err := dbu.Login(email, "wrong", "", ""); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginSyncsTeams(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+	db.AutoMigrate(&storage.Team{}, &storage.TeamUser{})
+
+	email := "gopher@luizalabs.com"
+	fa := &fakeAuthenticator{identity: &Identity{
+		Email:        email,
+		Teams:        []string{"luizalabs"},
+		ManagedTeams: []string{"luizalabs", "infra"},
+	}}
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil, fa)
+	u, err := dbu.Provision(email)
+	if err != nil {
+		t.Fatal("error provisioning user: ", err)
+	}
+
+	teams := make(map[string]*storage.Team)
+	for _, name := range []string{"luizalabs", "infra", "gophers"} {
+		team := &storage.Team{Name: name}
+		if err := db.Create(team).Error; err != nil {
+			t.Fatal("error creating team: ", err)
+		}
+		teams[name] = team
+	}
+	for _, name := range []string{"infra", "gophers"} {
+		tu := &storage.TeamUser{TeamID: teams[name].ID, UserID: u.ID, Role: "owner"}
+		if err := db.Create(tu).Error; err != nil {
+			t.Fatal("error adding user to team: ", err)
+		}
+	}
+
+	if _, err := dbu.Login(email, "secret", "", ""); err != nil {
+		t.Fatal("error on login: ", err)
+	}
+
+	var members []storage.TeamUser
+	if err := db.Where(&storage.TeamUser{UserID: u.ID}).Order("team_id").Find(&members).Error; err != nil {
+		t.Fatal("error getting teams: ", err)
+	}
+	var testCases = []struct {
+		team         string
+		expectedRole string
+	}{
+		{"luizalabs", memberRole},
+		{"gophers", "owner"},
+	}
+	if len(members) != len(testCases) {
+		t.Fatalf("expected %d teams, got %v", len(testCases), members)
+	}
+	for i, tc := range testCases {
+		if members[i].TeamID != teams[tc.team].ID || members[i].Role != tc.expectedRole {
+			t.Errorf("expected %s in team %s, got %v", tc.expectedRole, tc.team, members[i])
+		}
+	}
+}
+
 func TestDatabaseOperationsGetUser(t *testing.T) {
 	db, err := gorm.Open("sqlite3", ":memory:")
 	if err != nil {
@@ -70,7 +187,7 @@ func TestDatabaseOperationsGetUser(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 
 	expectedEmail := "teresa@luizalabs.com"
 	if err = createFakeUser(db, expectedEmail, ""); err != nil {
@@ -93,7 +210,7 @@ func TestDatabaseOperationsGetUserNotFound(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	if _, err := dbu.GetUser("gopher@luizalabs.com"); err != ErrNotFound {
 		t.Errorf("expected ErrNotFound, got %s", err)
 	}
@@ -106,10 +223,10 @@ func TestDatabaseOperationsSetPassword(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 
 	expectedEmail := "teresa@luizalabs.com"
-	expectedPassword := "secret"
+	expectedPassword := "new-secret"
 	if err = createFakeUser(db, expectedEmail, "123456"); err != nil {
 		t.Fatal("error on create fake user: ", err)
 	}
@@ -117,11 +234,101 @@ func TestDatabaseOperationsSetPassword(t *testing.T) {
 	if err = dbu.SetPassword(expectedEmail, expectedPassword); err != nil {
 		t.Fatal("error trying to set a new password: ", err)
 	}
-	if _, err = dbu.Login(expectedEmail, expectedPassword); err != nil {
+	if _, err = dbu.Login(expectedEmail, expectedPassword, "", ""); err != nil {
 		t.Error("error trying to make login with new password: ", err)
 	}
 }
 
+func TestDatabaseOperationsPasswordPolicy(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake(), &Options{PasswordMinLength: 10, PasswordMinClasses: 3})
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, email, "Secret-123"); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+
+	var testCases = []struct {
+		password    string
+		expectedErr error
+	}{
+		{"Secret-1", ErrInvalidPassword},
+		{"secretsecret", ErrWeakPassword},
+		{"SecretSecret", ErrWeakPassword},
+		{"Secret-123", ErrSamePassword},
+		{"Secret-1234", nil},
+		{"secret 1234", nil},
+	}
+
+	for _, tc := range testCases {
+		if err := dbu.SetPassword(email, tc.password); err != tc.expectedErr {
+			t.Errorf("expected %v for %s, got %v", tc.expectedErr, tc.password, err)
+		}
+	}
+	if err := dbu.Create("gopher", "gopher@luizalabs.com", "gophergopher", false); err != ErrWeakPassword {
+		t.Errorf("expected ErrWeakPassword, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginLockout(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake(), &Options{
+		MaxFailedLogins:      3,
+		MaxFailedLoginsPerIP: 5,
+		FailedLoginsWindow:   time.Minute,
+		LockoutDuration:      time.Minute,
+		PasswordMinLength:    8,
+	})
+	email := "teresa@luizalabs.com"
+	other := "gopher@luizalabs.com"
+	for _, e := range []string{email, other} {
+		if err = dbu.Create(e, e, "secret-pass", false); err != nil {
+			t.Fatal("error creating user: ", err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := dbu.Login(email, "wrong", "", "10.0.0.1"); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+			t.Fatalf("expected ErrPermissionDenied on attempt %d, got %v", i, err)
+		}
+	}
+	if _, err := dbu.Login(email, "secret-pass", "", "10.0.0.2"); err != ErrLocked {
+		t.Errorf("expected ErrLocked for the e-mail, got %v", err)
+	}
+	if _, err := dbu.Login(other, "secret-pass", "", "10.0.0.1"); err != nil {
+		t.Errorf("expected other users to login, got %v", err)
+	}
+
+	if err := dbu.Unlock(email); err != nil {
+		t.Fatal("error unlocking user: ", err)
+	}
+	if _, err := dbu.Login(email, "secret-pass", "", "10.0.0.2"); err != nil {
+		t.Errorf("expected login after unlock, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := dbu.Login("unknown@luizalabs.com", "secret-pass", "", "10.0.0.1"); err == nil {
+			t.Fatal("expected error for unknown user, got nil")
+		}
+	}
+	if _, err := dbu.Login(other, "secret-pass", "", "10.0.0.1"); err != ErrLocked {
+		t.Errorf("expected ErrLocked for the address, got %v", err)
+	}
+
+	if err := dbu.Unlock("nobody@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestDatabaseOperationsSetPasswordForInvalidUser(t *testing.T) {
 	db, err := gorm.Open("sqlite3", ":memory:")
 	if err != nil {
@@ -129,7 +336,7 @@ func TestDatabaseOperationsSetPasswordForInvalidUser(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	if err := dbu.SetPassword("gopher@luizalabs.com", "123"); err != ErrNotFound {
 		t.Errorf("expected ErrNotFound, got %v", err)
 	}
@@ -142,7 +349,7 @@ func TestDatabaseOperationsDelete(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	email := "teresa@luizalabs.com"
 	if err := createFakeUser(db, email, "123456"); err != nil {
 		t.Fatal("error creating fake user: ", err)
@@ -162,7 +369,7 @@ func TestDatabaseOperationsDeleteUserNotFound(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	if err := dbu.Delete("gopher@luizalabs.com"); err != ErrNotFound {
 		t.Errorf("expected ErrNotFound, got %s", err)
 	}
@@ -175,7 +382,7 @@ func TestDatabaseOperationsCreate(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	name := "teresa"
 	email := "teresa@luizalabs.com"
 	pass := "test1234"
@@ -208,7 +415,7 @@ func TestDatabaseOperationsCreateUserAlreadyExists(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	email := "teresa@luizalabs.com"
 
 	if err := createFakeUser(db, email, "12345678"); err != nil {
@@ -226,7 +433,7 @@ func TestDatabaseOperationsCreateUserInvalidPassword(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 	email := "teresa@luizalabs.com"
 
 	if err := dbu.Create("gopher", email, "test", false); err != ErrInvalidPassword {
@@ -241,9 +448,143 @@ func TestDatabaseOperationsCreateUserInvalidEmail(t *testing.T) {
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, auth.NewFake(), nil)
 
 	if err := dbu.Create("gopher", "gopher", "12345678", false); err != ErrInvalidEmail {
 		t.Errorf("expected ErrInvalidEmail, got %v", err)
 	}
 }
+
+func newJWTAuth(t *testing.T) auth.Auth {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal("error generating key: ", err)
+	}
+	opts := &auth.Options{TokenExpiration: time.Hour, RefreshTokenExpiration: 24 * time.Hour}
+	return auth.New(key, &key.PublicKey, opts)
+}
+
+func TestDatabaseOperationsRefresh(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	a := newJWTAuth(t)
+	dbu := NewDatabaseOperations(db, a, nil)
+
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, email, "secret"); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	tokens, err := dbu.Login(email, "secret", "", "")
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+
+	if _, err := dbu.Refresh(tokens.Token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied refreshing with an access token, got %v", err)
+	}
+	newTokens, err := dbu.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatal("error on refresh: ", err)
+	}
+	claims, err := a.ValidateToken(newTokens.Token)
+	if err != nil {
+		t.Fatal("error on validate the new token: ", err)
+	}
+	if _, err := dbu.Authenticate(claims); err != nil {
+		t.Error("error on authenticate with the new token: ", err)
+	}
+	if _, err := dbu.Refresh(tokens.RefreshToken); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied reusing a refresh token, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLogout(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	a := newJWTAuth(t)
+	dbu := NewDatabaseOperations(db, a, nil)
+
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, email, "secret"); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	tokens, err := dbu.Login(email, "secret", "", "")
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+	other, err := dbu.Login(email, "secret", "", "")
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+
+	if err := dbu.Logout("gopher@luizalabs.com", tokens.Token, ""); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for the token of other user, got %v", err)
+	}
+	if err := dbu.Logout(email, tokens.Token, tokens.RefreshToken); err != nil {
+		t.Fatal("error on logout: ", err)
+	}
+
+	claims, _ := a.ValidateToken(tokens.Token)
+	if _, err := dbu.Authenticate(claims); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a revoked token, got %v", err)
+	}
+	if _, err := dbu.Refresh(tokens.RefreshToken); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a revoked refresh token, got %v", err)
+	}
+	claims, _ = a.ValidateToken(other.Token)
+	if _, err := dbu.Authenticate(claims); err != nil {
+		t.Error("expected the other session to remain valid, got ", err)
+	}
+}
+
+func TestDatabaseOperationsRevokeSessions(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	a := newJWTAuth(t)
+	dbu := NewDatabaseOperations(db, a, nil)
+
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, email, "secret"); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	tokens, err := dbu.Login(email, "secret", "", "")
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+
+	if err := dbu.RevokeSessions(email); err != nil {
+		t.Fatal("error on revoke sessions: ", err)
+	}
+	claims, _ := a.ValidateToken(tokens.Token)
+	if _, err := dbu.Authenticate(claims); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a revoked session, got %v", err)
+	}
+	if _, err := dbu.Refresh(tokens.RefreshToken); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a revoked session, got %v", err)
+	}
+
+	tokens, err = dbu.Login(email, "secret", "", "")
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+	claims, _ = a.ValidateToken(tokens.Token)
+	if _, err := dbu.Authenticate(claims); err != nil {
+		t.Error("error on authenticate after a new login: ", err)
+	}
+
+	if err := dbu.RevokeSessions("gopher@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package webhook
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound     = status.Errorf(codes.NotFound, "Webhook not found")
+	ErrInvalidURL   = status.Errorf(codes.InvalidArgument, "Invalid webhook URL")
+	ErrInvalidEvent = status.Errorf(codes.InvalidArgument, "Invalid webhook event")
+	ErrMissingOwner = status.Errorf(codes.InvalidArgument, "A team or an app is required")
+	ErrAppNotFound  = status.Errorf(codes.NotFound, "App not found")
+)
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+)
+
+type FakeOperations struct {
+	mutex     *sync.RWMutex
+	nextID    uint
+	Storage   map[uint]*storage.Webhook
+	Delivered map[uint][]*storage.WebhookDelivery
+	Events    []*Event
+}
+
+func hasPerm(user *storage.User) bool {
+	return user.Email != "bad-user@luizalabs.com"
+}
+
+func (f *FakeOperations) Create(user *storage.User, hook *storage.Webhook) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !hasPerm(user) {
+		return auth.ErrPermissionDenied
+	}
+	if hook.Team == "" && hook.App == "" {
+		return ErrMissingOwner
+	}
+	if !isValidURL(hook.URL) {
+		return ErrInvalidURL
+	}
+
+	f.nextID++
+	hook.ID = f.nextID
+	hook.CreatedBy = user.Email
+	f.Storage[hook.ID] = hook
+	return nil
+}
+
+func (f *FakeOperations) List(user *storage.User) ([]*storage.Webhook, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	var hooks []*storage.Webhook
+	for _, h := range f.Storage {
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+func (f *FakeOperations) Delete(user *storage.User, id uint) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !hasPerm(user) {
+		return auth.ErrPermissionDenied
+	}
+	if _, found := f.Storage[id]; !found {
+		return ErrNotFound
+	}
+	delete(f.Storage, id)
+	return nil
+}
+
+func (f *FakeOperations) Deliveries(user *storage.User, id uint, limit int) ([]*storage.WebhookDelivery, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if !hasPerm(user) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if _, found := f.Storage[id]; !found {
+		return nil, ErrNotFound
+	}
+	return f.Delivered[id], nil
+}
+
+func (f *FakeOperations) Notify(ev *Event) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.Events = append(f.Events, ev)
+}
+
+func NewFakeOperations() Operations {
+	return &FakeOperations{
+		mutex:     &sync.RWMutex{},
+		Storage:   make(map[uint]*storage.Webhook),
+		Delivered: make(map[uint][]*storage.WebhookDelivery),
+	}
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	webhookpb "github.com/luizalabs/teresa-api/pkg/protobuf/webhook"
+	"google.golang.org/grpc"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) Create(ctx context.Context, request *webhookpb.CreateRequest) (*webhookpb.CreateResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	hook := &storage.Webhook{
+		Team:   request.Team,
+		App:    request.App,
+		URL:    request.Url,
+		Secret: request.Secret,
+		Events: joinEvents(request.Events),
+	}
+	if err := s.ops.Create(u, hook); err != nil {
+		return nil, err
+	}
+	return &webhookpb.CreateResponse{Id: uint64(hook.ID), Secret: hook.Secret}, nil
+}
+
+func (s *Service) List(ctx context.Context, _ *webhookpb.Empty) (*webhookpb.ListResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	hooks, err := s.ops.List(u)
+	if err != nil {
+		return nil, err
+	}
+	return newListResponse(hooks), nil
+}
+
+func (s *Service) Delete(ctx context.Context, request *webhookpb.DeleteRequest) (*webhookpb.Empty, error) {
+	u := ctx.Value("user").(*storage.User)
+	if err := s.ops.Delete(u, uint(request.Id)); err != nil {
+		return nil, err
+	}
+	return &webhookpb.Empty{}, nil
+}
+
+func (s *Service) Deliveries(ctx context.Context, request *webhookpb.DeliveriesRequest) (*webhookpb.DeliveriesResponse, error) {
+	u := ctx.Value("user").(*storage.User)
+	deliveries, err := s.ops.Deliveries(u, uint(request.Id), int(request.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return newDeliveriesResponse(deliveries), nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	webhookpb.RegisterWebhookServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
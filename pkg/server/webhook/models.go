@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"github.com/luizalabs/teresa-api/models/storage"
+	webhookpb "github.com/luizalabs/teresa-api/pkg/protobuf/webhook"
+)
+
+const (
+	DeployStarted   = "deploy.started"
+	DeploySucceeded = "deploy.succeeded"
+	DeployFailed    = "deploy.failed"
+	AppEnvChanged   = "app.env_changed"
+	AppCreated      = "app.created"
+)
+
+var validEvents = map[string]bool{
+	DeployStarted:   true,
+	DeploySucceeded: true,
+	DeployFailed:    true,
+	AppEnvChanged:   true,
+	AppCreated:      true,
+}
+
+// Event is the JSON payload posted to the subscribed webhooks.
+type Event struct {
+	Type string            `json:"event"`
+	Team string            `json:"team"`
+	App  string            `json:"app"`
+	User string            `json:"user,omitempty"`
+	Data map[string]string `json:"data,omitempty"`
+	Time time.Time         `json:"time"`
+}
+
+func NewEvent(typ, team, app, user string, data map[string]string) *Event {
+	return &Event{
+		Type: typ,
+		Team: team,
+		App:  app,
+		User: user,
+		Data: data,
+		Time: time.Now().UTC(),
+	}
+}
+
+func joinEvents(events []string) string {
+	return strings.Join(events, ",")
+}
+
+func splitEvents(events string) []string {
+	if events == "" {
+		return nil
+	}
+	return strings.Split(events, ",")
+}
+
+func subscribed(hook *storage.Webhook, event string) bool {
+	if hook.Events == "" {
+		return true
+	}
+	for _, e := range splitEvents(hook.Events) {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func newListResponse(hooks []*storage.Webhook) *webhookpb.ListResponse {
+	resp := &webhookpb.ListResponse{}
+	for _, h := range hooks {
+		resp.Webhooks = append(resp.Webhooks, &webhookpb.ListResponse_Webhook{
+			Id:        uint64(h.ID),
+			Team:      h.Team,
+			App:       h.App,
+			Url:       h.URL,
+			Events:    splitEvents(h.Events),
+			CreatedBy: h.CreatedBy,
+		})
+	}
+	return resp
+}
+
+func newDeliveriesResponse(deliveries []*storage.WebhookDelivery) *webhookpb.DeliveriesResponse {
+	resp := &webhookpb.DeliveriesResponse{}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, &webhookpb.DeliveriesResponse_Delivery{
+			Event:      d.Event,
+			Attempt:    int32(d.Attempt),
+			StatusCode: int32(d.StatusCode),
+			Error:      d.Error,
+			Success:    d.Success,
+			CreatedAt:  d.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
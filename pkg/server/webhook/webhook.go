@@ -0,0 +1,319 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/labels"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDeliveries  = 20
+	secretSize         = 20
+	SignatureHeader    = "X-Teresa-Signature"
+	EventHeader        = "X-Teresa-Event"
+	DeliveryHeader     = "X-Teresa-Delivery"
+	signatureAlgorithm = "sha256"
+)
+
+type Notifier interface {
+	Notify(ev *Event)
+}
+
+type Operations interface {
+	Notifier
+	Create(user *storage.User, hook *storage.Webhook) error
+	List(user *storage.User) ([]*storage.Webhook, error)
+	Delete(user *storage.User, id uint) error
+	Deliveries(user *storage.User, id uint, limit int) ([]*storage.WebhookDelivery, error)
+}
+
+type K8sOperations interface {
+	NamespaceLabel(namespace, label string) (string, error)
+	IsNotFound(err error) bool
+}
+
+type Options struct {
+	MaxAttempts    int           `split_words:"true" default:"5"`
+	InitialBackoff time.Duration `split_words:"true" default:"2s"`
+	Timeout        time.Duration `default:"10s"`
+}
+
+type DatabaseOperations struct {
+	DB     *gorm.DB
+	tops   team.Operations
+	kops   K8sOperations
+	opts   *Options
+	client *http.Client
+	sleep  func(time.Duration)
+}
+
+// hasPerm reports whether the user is an admin or a member of the team,
+// viewers can only list the webhooks.
+func (ops *DatabaseOperations) hasPerm(user *storage.User, teamName string) bool {
+	if user.IsAdmin {
+		return true
+	}
+	role, err := ops.tops.Role(teamName, user.Email)
+	if err != nil {
+		return false
+	}
+	return team.RoleAllows(role, team.RoleMember)
+}
+
+func (ops *DatabaseOperations) Create(user *storage.User, hook *storage.Webhook) error {
+	if hook.App != "" {
+		teamName, err := ops.kops.NamespaceLabel(hook.App, labels.Team)
+		if err != nil {
+			if ops.kops.IsNotFound(err) {
+				return ErrAppNotFound
+			}
+			return teresa_errors.NewInternalServerError(err)
+		}
+		hook.Team = teamName
+	}
+	if hook.Team == "" {
+		return ErrMissingOwner
+	}
+	if !ops.hasPerm(user, hook.Team) {
+		return auth.ErrPermissionDenied
+	}
+
+	if !isValidURL(hook.URL) {
+		return ErrInvalidURL
+	}
+	for _, e := range splitEvents(hook.Events) {
+		if !validEvents[e] {
+			return ErrInvalidEvent
+		}
+	}
+
+	if hook.Secret == "" {
+		secret, err := genSecret()
+		if err != nil {
+			return teresa_errors.NewInternalServerError(err)
+		}
+		hook.Secret = secret
+	}
+	hook.CreatedBy = user.Email
+
+	if err := ops.DB.Create(hook).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("saving webhook of team %s", hook.Team)),
+		)
+	}
+	return nil
+}
+
+func (ops *DatabaseOperations) List(user *storage.User) ([]*storage.Webhook, error) {
+	q := ops.DB
+	if !user.IsAdmin {
+		teams, err := ops.tops.ListByUser(user.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(teams) == 0 {
+			return nil, nil
+		}
+		names := make([]string, len(teams))
+		for i, t := range teams {
+			names[i] = t.Name
+		}
+		q = q.Where("team in (?)", names)
+	}
+
+	var hooks []*storage.Webhook
+	if err := q.Order("id").Find(&hooks).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "finding webhooks"),
+		)
+	}
+	return hooks, nil
+}
+
+func (ops *DatabaseOperations) getWebhook(user *storage.User, id uint) (*storage.Webhook, error) {
+	hook := new(storage.Webhook)
+	if ops.DB.First(hook, id).RecordNotFound() {
+		return nil, ErrNotFound
+	}
+	if !ops.hasPerm(user, hook.Team) {
+		return nil, auth.ErrPermissionDenied
+	}
+	return hook, nil
+}
+
+func (ops *DatabaseOperations) Delete(user *storage.User, id uint) error {
+	hook, err := ops.getWebhook(user, id)
+	if err != nil {
+		return err
+	}
+
+	err = ops.DB.Where(&storage.WebhookDelivery{WebhookID: hook.ID}).Delete(storage.WebhookDelivery{}).Error
+	if err == nil {
+		err = ops.DB.Delete(hook).Error
+	}
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("deleting webhook %d", id)),
+		)
+	}
+	return nil
+}
+
+// Deliveries returns the last delivery attempts of a webhook, newest first.
+func (ops *DatabaseOperations) Deliveries(user *storage.User, id uint, limit int) ([]*storage.WebhookDelivery, error) {
+	hook, err := ops.getWebhook(user, id)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveries
+	}
+
+	var deliveries []*storage.WebhookDelivery
+	err = ops.DB.
+		Where(&storage.WebhookDelivery{WebhookID: hook.ID}).
+		Order("id desc").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding deliveries of webhook %d", id)),
+		)
+	}
+	return deliveries, nil
+}
+
+// Notify posts the event to every webhook of the app and of its team
+// subscribed to it. The deliveries are made in background.
+func (ops *DatabaseOperations) Notify(ev *Event) {
+	var hooks []*storage.Webhook
+	err := ops.DB.
+		Where("app = ? OR (app = ? AND team = ?)", ev.App, "", ev.Team).
+		Find(&hooks).Error
+	if err != nil {
+		log.WithError(err).Errorf("Finding webhooks of app %s", ev.App)
+		return
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		log.WithError(err).Errorf("Encoding event %s of app %s", ev.Type, ev.App)
+		return
+	}
+
+	for _, hook := range hooks {
+		if !subscribed(hook, ev.Type) {
+			continue
+		}
+		go ops.deliver(hook, ev.Type, payload)
+	}
+}
+
+func (ops *DatabaseOperations) deliver(hook *storage.Webhook, event string, payload []byte) {
+	backoff := ops.opts.InitialBackoff
+	for attempt := 1; attempt <= ops.opts.MaxAttempts; attempt++ {
+		d := &storage.WebhookDelivery{
+			WebhookID: hook.ID,
+			Event:     event,
+			Payload:   string(payload),
+			Attempt:   attempt,
+		}
+		statusCode, err := ops.post(hook, event, payload)
+		d.StatusCode = statusCode
+		if err != nil {
+			d.Error = err.Error()
+		} else {
+			d.Success = true
+		}
+
+		if err := ops.DB.Create(d).Error; err != nil {
+			log.WithError(err).Errorf("Saving delivery of webhook %d", hook.ID)
+		}
+		if d.Success {
+			return
+		}
+		if attempt < ops.opts.MaxAttempts {
+			ops.sleep(backoff)
+			backoff *= 2
+		}
+	}
+	log.Errorf("Giving up delivering event %s to webhook %d", event, hook.ID)
+}
+
+func (ops *DatabaseOperations) post(hook *storage.Webhook, event string, payload []byte) (int, error) {
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, fmt.Sprint(hook.ID))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, payload))
+
+	resp, err := ops.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header of a payload, the
+// hex encoded HMAC-SHA256 of it keyed by the webhook secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return fmt.Sprintf("%s=%s", signatureAlgorithm, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func isValidURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func genSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func NewDatabaseOperations(db *gorm.DB, tops team.Operations, kops K8sOperations, opts *Options) Operations {
+	db.AutoMigrate(&storage.Webhook{}, &storage.WebhookDelivery{})
+	return &DatabaseOperations{
+		DB:     db,
+		tops:   tops,
+		kops:   kops,
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		sleep:  time.Sleep,
+	}
+}
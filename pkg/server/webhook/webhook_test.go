@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa-api/models/storage"
+	"github.com/luizalabs/teresa-api/pkg/server/auth"
+	"github.com/luizalabs/teresa-api/pkg/server/team"
+	"github.com/luizalabs/teresa-api/pkg/server/teresa_errors"
+)
+
+type fakeK8sOperations struct {
+	team string
+}
+
+func (f *fakeK8sOperations) NamespaceLabel(namespace, label string) (string, error) {
+	if f.team == "" {
+		return "", errNotFound
+	}
+	return f.team, nil
+}
+
+func (f *fakeK8sOperations) IsNotFound(err error) bool {
+	return err == errNotFound
+}
+
+var errNotFound = errors.New("not found")
+
+func newTestOperations(t *testing.T, kops K8sOperations) (*DatabaseOperations, func()) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	db.DB().SetMaxOpenConns(1)
+
+	tops := team.NewFakeOperations()
+	tops.(*team.FakeOperations).Storage["luizalabs"] = &storage.Team{
+		Name:  "luizalabs",
+		Users: []storage.User{{Email: "gopher@luizalabs.com"}, {Email: "viewer@luizalabs.com"}},
+	}
+	tops.(*team.FakeOperations).Roles["luizalabs"] = map[string]string{"viewer@luizalabs.com": team.RoleViewer}
+
+	opts := &Options{MaxAttempts: 3, InitialBackoff: time.Second, Timeout: time.Second}
+	ops := NewDatabaseOperations(db, tops, kops, opts).(*DatabaseOperations)
+	ops.sleep = func(time.Duration) {}
+	return ops, func() { db.Close() }
+}
+
+func TestDatabaseOperationsCreate(t *testing.T) {
+	ops, closeDB := newTestOperations(t, &fakeK8sOperations{team: "luizalabs"})
+	defer closeDB()
+
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+	hook := &storage.Webhook{App: "teresa", URL: "https://example.com/hook", Events: "deploy.started"}
+	if err := ops.Create(user, hook); err != nil {
+		t.Fatal("error creating webhook: ", err)
+	}
+
+	if hook.Team != "luizalabs" {
+		t.Errorf("expected luizalabs, got %s", hook.Team)
+	}
+	if hook.Secret == "" {
+		t.Error("expected a generated secret, got empty")
+	}
+	if hook.CreatedBy != user.Email {
+		t.Errorf("expected %s, got %s", user.Email, hook.CreatedBy)
+	}
+}
+
+func TestDatabaseOperationsCreateErrors(t *testing.T) {
+	var testCases = []struct {
+		user        *storage.User
+		hook        *storage.Webhook
+		kops        K8sOperations
+		expectedErr error
+	}{
+		{
+			&storage.User{Email: "gopher@luizalabs.com"},
+			&storage.Webhook{URL: "https://example.com"},
+			&fakeK8sOperations{},
+			ErrMissingOwner,
+		},
+		{
+			&storage.User{Email: "gopher@luizalabs.com"},
+			&storage.Webhook{App: "teresa", URL: "https://example.com"},
+			&fakeK8sOperations{},
+			ErrAppNotFound,
+		},
+		{
+			&storage.User{Email: "bad-user@luizalabs.com"},
+			&storage.Webhook{Team: "luizalabs", URL: "https://example.com"},
+			&fakeK8sOperations{},
+			auth.ErrPermissionDenied,
+		},
+		{
+			&storage.User{Email: "viewer@luizalabs.com"},
+			&storage.Webhook{Team: "luizalabs", URL: "https://example.com"},
+			&fakeK8sOperations{},
+			auth.ErrPermissionDenied,
+		},
+		{
+			&storage.User{Email: "gopher@luizalabs.com"},
+			&storage.Webhook{Team: "luizalabs", URL: "ftp://example.com"},
+			&fakeK8sOperations{},
+			ErrInvalidURL,
+		},
+		{
+			&storage.User{Email: "gopher@luizalabs.com"},
+			&storage.Webhook{Team: "luizalabs", URL: "https://example.com", Events: "deploy.started,foo"},
+			&fakeK8sOperations{},
+			ErrInvalidEvent,
+		},
+	}
+
+	for _, tc := range testCases {
+		ops, closeDB := newTestOperations(t, tc.kops)
+		if err := ops.Create(tc.user, tc.hook); teresa_errors.Get(err) != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+		closeDB()
+	}
+}
+
+func TestDatabaseOperationsListAndDelete(t *testing.T) {
+	ops, closeDB := newTestOperations(t, &fakeK8sOperations{})
+	defer closeDB()
+
+	admin := &storage.User{Email: "admin@luizalabs.com", IsAdmin: true}
+	for _, teamName := range []string{"luizalabs", "other"} {
+		hook := &storage.Webhook{Team: teamName, URL: "https://example.com"}
+		if err := ops.Create(admin, hook); err != nil {
+			t.Fatal("error creating webhook: ", err)
+		}
+	}
+
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+	hooks, err := ops.List(user)
+	if err != nil {
+		t.Fatal("error listing webhooks: ", err)
+	}
+	if len(hooks) != 1 || hooks[0].Team != "luizalabs" {
+		t.Fatalf("expected only the webhook of luizalabs, got %v", hooks)
+	}
+
+	if err := ops.Delete(user, 2); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := ops.Delete(user, hooks[0].ID); err != nil {
+		t.Fatal("error deleting webhook: ", err)
+	}
+	if err := ops.Delete(user, hooks[0].ID); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsDeliver(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		if sig := r.Header.Get(SignatureHeader); sig != Sign("secret", body) {
+			t.Errorf("expected signature %s, got %s", Sign("secret", body), sig)
+		}
+		if ev := r.Header.Get(EventHeader); ev != DeployFailed {
+			t.Errorf("expected %s, got %s", DeployFailed, ev)
+		}
+		if calls < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	ops, closeDB := newTestOperations(t, &fakeK8sOperations{})
+	defer closeDB()
+
+	user := &storage.User{Email: "gopher@luizalabs.com"}
+	hook := &storage.Webhook{Team: "luizalabs", URL: ts.URL, Secret: "secret"}
+	if err := ops.Create(user, hook); err != nil {
+		t.Fatal("error creating webhook: ", err)
+	}
+
+	payload, _ := json.Marshal(NewEvent(DeployFailed, "luizalabs", "teresa", user.Email, nil))
+	ops.deliver(hook, DeployFailed, payload)
+
+	deliveries, err := ops.Deliveries(user, hook.ID, 0)
+	if err != nil {
+		t.Fatal("error getting deliveries: ", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+	}
+	if d := deliveries[0]; !d.Success || d.Attempt != 2 || d.StatusCode != http.StatusOK {
+		t.Errorf("expected a successful second attempt, got %+v", d)
+	}
+	if d := deliveries[1]; d.Success || d.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a failed first attempt, got %+v", d)
+	}
+}
+
+func TestSubscribed(t *testing.T) {
+	var testCases = []struct {
+		events   string
+		event    string
+		expected bool
+	}{
+		{"", AppCreated, true},
+		{"app.created,deploy.failed", DeployFailed, true},
+		{"app.created", DeployFailed, false},
+	}
+
+	for _, tc := range testCases {
+		hook := &storage.Webhook{Events: tc.events}
+		if actual := subscribed(hook, tc.event); actual != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, actual)
+		}
+	}
+}